@@ -0,0 +1,73 @@
+package attestation
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+// Algorithm identifies the signature scheme used for attestations
+const Algorithm = "ed25519"
+
+// Signer signs eligibility snapshots with the server's Ed25519 key
+type Signer struct {
+	key ed25519.PrivateKey
+}
+
+// NewSigner creates a signer from an Ed25519 private key
+func NewSigner(key ed25519.PrivateKey) *Signer {
+	return &Signer{key: key}
+}
+
+// LoadSigner reads a base64-encoded 32-byte Ed25519 seed from path
+func LoadSigner(path string) (*Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attestation key: %v", err)
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("attestation key must be a base64-encoded %d-byte Ed25519 seed", ed25519.SeedSize)
+	}
+
+	return NewSigner(ed25519.NewKeyFromSeed(seed)), nil
+}
+
+// GenerateSigner creates a signer with a fresh random key. Attestations it signs can
+// only be verified while this process runs, so it is meant for development.
+func GenerateSigner() (*Signer, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate attestation key: %v", err)
+	}
+	return NewSigner(key), nil
+}
+
+// PublicKey returns the base64-encoded public key verifiers should trust
+func (s *Signer) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey))
+}
+
+// Sign serializes the attestation and signs the exact bytes. The serialized payload is
+// returned alongside the signature so verifiers never need to re-encode it.
+func (s *Signer) Sign(attestation models.EligibilityAttestation) (*models.SignedAttestation, error) {
+	payload, err := json.Marshal(attestation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode attestation: %v", err)
+	}
+
+	return &models.SignedAttestation{
+		Attestation: attestation,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signature:   base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, payload)),
+		Algorithm:   Algorithm,
+		PublicKey:   s.PublicKey(),
+	}, nil
+}
@@ -0,0 +1,84 @@
+package attestation
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+func TestSignatureVerifiesWithPublicKey(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	keyFile := filepath.Join(t.TempDir(), "attestation.key")
+	if err := os.WriteFile(keyFile, []byte(base64.StdEncoding.EncodeToString(seed)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	signer, err := LoadSigner(keyFile)
+	if err != nil {
+		t.Fatalf("LoadSigner: %v", err)
+	}
+
+	attestation := models.EligibilityAttestation{
+		TransactionAmount: 10,
+		Count:             2,
+		RequiredBalance:   5,
+		Quorums:           []models.QuorumData{{Type: 4, Address: "peer1.did1"}, {Type: 4, Address: "peer2.did2"}},
+		Timestamp:         time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	signed, err := signer.Sign(attestation)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+
+	publicKey, _ := base64.StdEncoding.DecodeString(signed.PublicKey)
+	payload, _ := base64.StdEncoding.DecodeString(signed.Payload)
+	signature, _ := base64.StdEncoding.DecodeString(signed.Signature)
+	if signed.Algorithm != Algorithm || !ed25519.Verify(publicKey, payload, signature) {
+		t.Fatalf("signature doesn't verify against the signer's public key")
+	}
+	if want := ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey); !want.Equal(ed25519.PublicKey(publicKey)) {
+		t.Error("public key doesn't match the loaded seed")
+	}
+
+	var decoded models.EligibilityAttestation
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Timestamp.Equal(attestation.Timestamp) || len(decoded.Quorums) != 2 || decoded.Quorums[1] != attestation.Quorums[1] {
+		t.Errorf("signed payload = %+v, want %+v", decoded, attestation)
+	}
+
+	// Any change to the payload breaks the signature
+	tampered := append([]byte(nil), payload...)
+	tampered[len(tampered)-2] ^= 1
+	if ed25519.Verify(publicKey, tampered, signature) {
+		t.Error("signature verified a tampered payload")
+	}
+}
+
+func TestLoadSignerRejectsBadKeys(t *testing.T) {
+	dir := t.TempDir()
+	for name, contents := range map[string]string{
+		"not-base64": "not base64!",
+		"short":      base64.StdEncoding.EncodeToString([]byte("too short")),
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadSigner(path); err == nil {
+			t.Errorf("LoadSigner accepted a %s key", name)
+		}
+	}
+	if _, err := LoadSigner(filepath.Join(dir, "missing")); err == nil {
+		t.Error("LoadSigner accepted a missing file")
+	}
+}
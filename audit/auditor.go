@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gklps/advisory-node/metrics"
+	"github.com/gklps/advisory-node/models"
+)
+
+// Record is the full decision context of one successful quorum selection
+type Record struct {
+	Timestamp       time.Time                `json:"timestamp"`
+	TransactionID   string                   `json:"transaction_id"`
+	Request         models.QuorumListRequest `json:"request"`
+	RequiredBalance float64                  `json:"required_balance"`
+	Quorums         []models.QuorumData      `json:"quorums"`
+
+	// Quorums in the pool and how many survived each selection filter, in order
+	TotalQuorums int64                   `json:"total_quorums"`
+	Stages       []models.DiagnosisStage `json:"stages"`
+}
+
+// SelectionAuditor receives every successful selection. AuditSelection is called on
+// the request path and must not block.
+type SelectionAuditor interface {
+	AuditSelection(record Record)
+}
+
+// HTTPConfig controls delivery to an HTTP audit sink
+type HTTPConfig struct {
+	Timeout   time.Duration // Per-record HTTP timeout
+	QueueSize int           // Records buffered before new ones are dropped
+}
+
+// DefaultHTTPConfig returns the delivery settings used when none are configured
+func DefaultHTTPConfig() HTTPConfig {
+	return HTTPConfig{
+		Timeout:   5 * time.Second,
+		QueueSize: 1000,
+	}
+}
+
+// HTTPSink POSTs each record as JSON to a fixed URL from a background worker.
+// AuditSelection never blocks: records arriving while the buffer is full are dropped
+// and counted, and a record the sink rejects is logged rather than retried.
+type HTTPSink struct {
+	url    string
+	config HTTPConfig
+	client *http.Client
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan Record
+	wg     sync.WaitGroup
+}
+
+// NewHTTPSink starts a sink posting to url; zero config fields fall back to DefaultHTTPConfig
+func NewHTTPSink(url string, config HTTPConfig) *HTTPSink {
+	defaults := DefaultHTTPConfig()
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaults.QueueSize
+	}
+
+	s := &HTTPSink{
+		url:    url,
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+		queue:  make(chan Record, config.QueueSize),
+	}
+
+	s.wg.Add(1)
+	go s.worker()
+
+	return s
+}
+
+// AuditSelection queues record for delivery without blocking the caller
+func (s *HTTPSink) AuditSelection(record Record) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.queue <- record:
+	default:
+		metrics.AuditRecordsDropped.Inc()
+		log.Printf("⚠️  Audit queue full, dropped selection record for %s\n", record.TransactionID)
+	}
+}
+
+// Close stops accepting records and waits for the buffered ones to be delivered
+func (s *HTTPSink) Close() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *HTTPSink) worker() {
+	defer s.wg.Done()
+
+	for record := range s.queue {
+		if err := s.post(record); err != nil {
+			metrics.AuditRecordsDropped.Inc()
+			log.Printf("⚠️  Failed to deliver audit record for %s to %s: %v\n", record.TransactionID, s.url, err)
+		}
+	}
+}
+
+func (s *HTTPSink) post(record Record) error {
+	payload, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sink responded %s", resp.Status)
+	}
+	return nil
+}
@@ -125,8 +125,10 @@ func startCleanupRoutine(store *storage.MemoryStore) {
 
 	for {
 		<-ticker.C
-		removed := store.CleanupStaleQuorums()
-		if removed > 0 {
+		removed, err := store.CleanupStaleQuorums()
+		if err != nil {
+			log.Printf("Cleanup failed: %v\n", err)
+		} else if removed > 0 {
 			log.Printf("Cleaned up %d stale quorums\n", removed)
 		}
 	}
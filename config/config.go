@@ -0,0 +1,204 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the settings that can be read from a YAML config file. Every field is
+// optional; a setting left out of the file keeps its flag default. Field names follow
+// the command line flags, so `stale_threshold` sets -stale-threshold.
+type Config struct {
+	Port      *string         `yaml:"port"`
+	CORS      CORSConfig      `yaml:"cors"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Staleness StalenessConfig `yaml:"staleness"`
+	Selection SelectionConfig `yaml:"selection"`
+
+	// Older spelling of cors.origins, still accepted; cors.origins wins when both are set
+	CORSOrigins *string `yaml:"cors_origins"`
+}
+
+// CORSConfig holds the -cors-* settings
+type CORSConfig struct {
+	Origins          *string        `yaml:"origins"`
+	AllowHeaders     *string        `yaml:"allow_headers"`
+	AllowMethods     *string        `yaml:"allow_methods"`
+	ExposeHeaders    *string        `yaml:"expose_headers"`
+	AllowCredentials *bool          `yaml:"allow_credentials"`
+	MaxAge           *time.Duration `yaml:"max_age"`
+}
+
+// DatabaseConfig holds the -db-* settings
+type DatabaseConfig struct {
+	Type            *string        `yaml:"type"`
+	Host            *string        `yaml:"host"`
+	Port            *int           `yaml:"port"`
+	Name            *string        `yaml:"name"`
+	User            *string        `yaml:"user"`
+	Password        *string        `yaml:"password"`
+	SSLMode         *string        `yaml:"ssl_mode"`
+	SQLiteCreateDir *bool          `yaml:"sqlite_create_dir"`
+	ReplicaURL      *string        `yaml:"replica_url"`
+	MaxReplicaLag   *time.Duration `yaml:"max_replica_lag"`
+	MaxOpenConns    *int           `yaml:"max_open_conns"`
+	MaxIdleConns    *int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime *time.Duration `yaml:"conn_max_lifetime"`
+}
+
+// StalenessConfig holds the heartbeat staleness settings
+type StalenessConfig struct {
+	AvailabilityWindow *time.Duration `yaml:"availability_window"`
+	StaleThreshold     *time.Duration `yaml:"stale_threshold"`
+	CleanupInterval    *time.Duration `yaml:"cleanup_interval"`
+	// CleanupFailureThreshold is how many cleanup runs in a row may fail before /ready
+	// reports not ready
+	CleanupFailureThreshold *int `yaml:"cleanup_failure_threshold"`
+}
+
+// SelectionConfig holds the quorum selection settings
+type SelectionConfig struct {
+	PreferFreshHeartbeats   *bool              `yaml:"prefer_fresh_heartbeats"`
+	TierWeights             map[string]float64 `yaml:"tier_weights"`
+	NewNodeRamp             *time.Duration     `yaml:"new_node_ramp"`
+	NewNodeMaxShare         *float64           `yaml:"new_node_max_share"`
+	BalanceSafetyMultiplier *float64           `yaml:"balance_safety_multiplier"`
+	MinQuorumBalance        *float64           `yaml:"min_quorum_balance"`
+	BalancePrecision        *int               `yaml:"balance_precision"`
+	EmptyTokensMeans        *string            `yaml:"empty_tokens_means"`
+	MinPoolSize             *int               `yaml:"min_pool_size"`
+	TokenMinPoolSizes       map[string]int     `yaml:"token_min_pool_sizes"`
+	MaxAssignmentImbalance  *float64           `yaml:"max_assignment_imbalance"`
+}
+
+// LoadConfig reads a YAML config file. The settings may sit at the top level or under
+// an `advisory_node` key, so the block from a RubixGo config can be used as is; in that
+// case other top-level keys are ignored. Unknown settings are rejected so typos don't
+// silently fall back to defaults.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var root map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	if block, ok := root["advisory_node"]; ok {
+		if data, err = yaml.Marshal(&block); err != nil {
+			return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	}
+
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Flags returns the settings present in the file as command line flag values, keyed by
+// flag name
+func (c *Config) Flags() map[string]string {
+	flags := make(map[string]string)
+	setString(flags, "port", c.Port)
+	setString(flags, "cors", c.CORSOrigins)
+	setString(flags, "cors", c.CORS.Origins)
+	setString(flags, "cors-allow-headers", c.CORS.AllowHeaders)
+	setString(flags, "cors-allow-methods", c.CORS.AllowMethods)
+	setString(flags, "cors-expose-headers", c.CORS.ExposeHeaders)
+	setBool(flags, "cors-allow-credentials", c.CORS.AllowCredentials)
+	setDuration(flags, "cors-max-age", c.CORS.MaxAge)
+
+	db := c.Database
+	setString(flags, "db-type", db.Type)
+	setString(flags, "db-host", db.Host)
+	setInt(flags, "db-port", db.Port)
+	setString(flags, "db-name", db.Name)
+	setString(flags, "db-user", db.User)
+	setString(flags, "db-password", db.Password)
+	setString(flags, "db-ssl", db.SSLMode)
+	setBool(flags, "sqlite-create-dir", db.SQLiteCreateDir)
+	setString(flags, "replica-url", db.ReplicaURL)
+	setDuration(flags, "max-replica-lag", db.MaxReplicaLag)
+	setInt(flags, "db-max-open-conns", db.MaxOpenConns)
+	setInt(flags, "db-max-idle-conns", db.MaxIdleConns)
+	setDuration(flags, "db-conn-max-lifetime", db.ConnMaxLifetime)
+
+	setDuration(flags, "availability-window", c.Staleness.AvailabilityWindow)
+	setDuration(flags, "stale-threshold", c.Staleness.StaleThreshold)
+	setDuration(flags, "cleanup-interval", c.Staleness.CleanupInterval)
+	setInt(flags, "cleanup-failure-threshold", c.Staleness.CleanupFailureThreshold)
+
+	sel := c.Selection
+	setBool(flags, "prefer-fresh-heartbeats", sel.PreferFreshHeartbeats)
+	if sel.TierWeights != nil {
+		flags["tier-weights"] = joinPairs(sel.TierWeights, func(w float64) string {
+			return strconv.FormatFloat(w, 'g', -1, 64)
+		})
+	}
+	setDuration(flags, "new-node-ramp", sel.NewNodeRamp)
+	setFloat(flags, "new-node-max-share", sel.NewNodeMaxShare)
+	setFloat(flags, "balance-safety-multiplier", sel.BalanceSafetyMultiplier)
+	setFloat(flags, "min-quorum-balance", sel.MinQuorumBalance)
+	setInt(flags, "balance-precision", sel.BalancePrecision)
+	setString(flags, "empty-tokens-means", sel.EmptyTokensMeans)
+	setInt(flags, "min-pool-size", sel.MinPoolSize)
+	if sel.TokenMinPoolSizes != nil {
+		flags["token-min-pool-sizes"] = joinPairs(sel.TokenMinPoolSizes, strconv.Itoa)
+	}
+	setFloat(flags, "max-assignment-imbalance", sel.MaxAssignmentImbalance)
+	return flags
+}
+
+func setString(flags map[string]string, name string, value *string) {
+	if value != nil {
+		flags[name] = *value
+	}
+}
+
+func setInt(flags map[string]string, name string, value *int) {
+	if value != nil {
+		flags[name] = strconv.Itoa(*value)
+	}
+}
+
+func setFloat(flags map[string]string, name string, value *float64) {
+	if value != nil {
+		flags[name] = strconv.FormatFloat(*value, 'g', -1, 64)
+	}
+}
+
+func setBool(flags map[string]string, name string, value *bool) {
+	if value != nil {
+		flags[name] = strconv.FormatBool(*value)
+	}
+}
+
+func setDuration(flags map[string]string, name string, value *time.Duration) {
+	if value != nil {
+		flags[name] = value.String()
+	}
+}
+
+// joinPairs formats a map in the key=value,... form of the list flags, sorted by key
+func joinPairs[V any](values map[string]V, format func(V) string) string {
+	pairs := make([]string, 0, len(values))
+	for key, value := range values {
+		pairs = append(pairs, key+"="+format(value))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
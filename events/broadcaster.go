@@ -0,0 +1,113 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Subscriber limits: events buffered per subscriber before it is dropped for falling
+// behind, and how many subscribers may be connected at once
+const (
+	subscriberBuffer = 64
+	maxSubscribers   = 1000
+)
+
+// Event is one change to the quorum pool. Type is the availability event recorded for
+// the quorum: registered, re-registered, available, stale, unregistered or disabled.
+type Event struct {
+	Type      string    `json:"type"`
+	DID       string    `json:"did"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Broadcaster fans pool changes out to in-process subscribers, such as the SSE stream.
+// Publishing never blocks: a subscriber whose buffer is full is dropped and its channel
+// closed, so a stalled client can't hold up the store. A nil Broadcaster discards
+// events, so callers need not check whether streaming is wired up.
+type Broadcaster struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+	closed      bool
+}
+
+// NewBroadcaster creates a broadcaster with no subscribers
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{subscribers: make(map[chan Event]struct{})}
+}
+
+// Subscribe returns a channel receiving every event published from now on, and a
+// function that unsubscribes it. The channel is closed when the subscriber is dropped
+// or the broadcaster closes. It returns false when the subscriber limit is reached.
+func (b *Broadcaster) Subscribe() (<-chan Event, func(), bool) {
+	if b == nil {
+		return nil, nil, false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed || len(b.subscribers) >= maxSubscribers {
+		return nil, nil, false
+	}
+
+	ch := make(chan Event, subscriberBuffer)
+	b.subscribers[ch] = struct{}{}
+	return ch, func() { b.remove(ch) }, true
+}
+
+// Publish delivers events to every subscriber
+func (b *Broadcaster) Publish(events ...Event) {
+	if b == nil || len(events) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		if !deliver(ch, events) {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// deliver queues events on ch, returning false if its buffer fills up
+func deliver(ch chan Event, events []Event) bool {
+	for _, event := range events {
+		select {
+		case ch <- event:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Close disconnects every subscriber and refuses new ones, so open streams end on
+// shutdown instead of holding the server open
+func (b *Broadcaster) Close() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+// remove unsubscribes ch, unless it was already dropped
+func (b *Broadcaster) remove(ch chan Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscribers[ch]; ok {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
@@ -193,7 +193,7 @@ func ExampleIntegration() {
 
 	// Example 1: Register a quorum when node starts
 	did := "bafybmi123456789012345678901234567890123456789012345678901234"
-	peerID := "12D3KooWExample"
+	peerID := "12D3KooWHwsKu3GS9rh5X5eS9RTKGFy6NcdX1bV1UHcH8sQ8WqCM"
 	
 	err := client.RegisterQuorum(did, peerID, 0, 1)
 	if err != nil {
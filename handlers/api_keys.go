@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/models"
+	"github.com/gklps/advisory-node/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyHeader carries the API key on HTTP requests; gRPC clients send it as the
+// x-api-key metadata entry
+const APIKeyHeader = "X-API-Key"
+
+// RequireScope guards a route group with API keys: every request needs a key holding
+// scope. It does nothing unless API keys are enabled.
+func (h *QuorumHandler) RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.authorizeAPIKey(c, scope) {
+			c.Next()
+		}
+	}
+}
+
+// RequireQuorumScopes guards /api/quorum with API keys: writes need the write scope, and
+// reads need the read scope unless reads are public. Every request other than GET, HEAD
+// and OPTIONS is a write, except on the routes in readOnly (by route path).
+func (h *QuorumHandler) RequireQuorumScopes(readOnly ...string) gin.HandlerFunc {
+	reads := make(map[string]bool, len(readOnly))
+	for _, path := range readOnly {
+		reads[path] = true
+	}
+
+	return func(c *gin.Context) {
+		scope := storage.ScopeWrite
+		if !isWriteMethod(c.Request.Method) || reads[c.FullPath()] {
+			scope = h.readScope()
+		}
+		if h.authorizeAPIKey(c, scope) {
+			c.Next()
+		}
+	}
+}
+
+// readScope is the scope reads need: none when they are public
+func (h *QuorumHandler) readScope() string {
+	if h.config.APIKeysPublicReads {
+		return ""
+	}
+	return storage.ScopeRead
+}
+
+// authorizeAPIKey checks the request's API key for scope, answering 401 for a missing
+// or unknown key and 403 for one without the scope. It reports whether the request may
+// proceed; an empty scope, or API keys being disabled, lets every request through.
+func (h *QuorumHandler) authorizeAPIKey(c *gin.Context, scope string) bool {
+	if !h.config.APIKeys || scope == "" {
+		return true
+	}
+
+	code, message := h.checkAPIKey(c.GetHeader(APIKeyHeader), scope)
+	switch code {
+	case codes.OK:
+		return true
+	case codes.Unauthenticated:
+		respondError(c, http.StatusUnauthorized, models.ErrCodeUnauthorized, models.BasicResponse{Status: false, Message: message})
+	case codes.PermissionDenied:
+		respondError(c, http.StatusForbidden, models.ErrCodeForbidden, models.BasicResponse{Status: false, Message: message})
+	default:
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{Status: false, Message: message})
+	}
+	c.Abort()
+	return false
+}
+
+// checkGRPCAPIKey refuses a gRPC call whose x-api-key metadata lacks scope, as
+// authorizeAPIKey does for HTTP
+func (h *QuorumHandler) checkGRPCAPIKey(ctx context.Context, scope string) error {
+	if !h.config.APIKeys || scope == "" {
+		return nil
+	}
+
+	var key string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(strings.ToLower(APIKeyHeader)); len(values) > 0 {
+			key = values[0]
+		}
+	}
+	if code, message := h.checkAPIKey(key, scope); code != codes.OK {
+		return status.Error(code, message)
+	}
+	return nil
+}
+
+// checkAPIKey looks key up and checks it holds scope, describing the outcome as a gRPC
+// code and message shared by both APIs
+func (h *QuorumHandler) checkAPIKey(key, scope string) (codes.Code, string) {
+	if key == "" {
+		return codes.Unauthenticated, "An API key is required in the " + APIKeyHeader + " header"
+	}
+
+	if h.ext == nil {
+		return codes.Unimplemented, "API keys are not supported by this store"
+	}
+	scopes, err := h.ext.LookupAPIKey(key)
+	if err != nil {
+		log.Printf("❌ API key lookup failed: %v\n", err)
+		return codes.Internal, "Failed to check API key"
+	}
+	if scopes == nil {
+		return codes.Unauthenticated, "Invalid API key"
+	}
+	if !storage.ScopeAllows(scopes, scope) {
+		return codes.PermissionDenied, fmt.Sprintf("This API key lacks the %s scope", scope)
+	}
+	return codes.OK, ""
+}
+
+// CreateAPIKey handles POST /api/admin/api-keys
+// The response carries the key itself, which is not stored and can't be shown again. It
+// is refused unless an admin token or API keys are configured, since it would otherwise
+// let anyone mint an admin key.
+func (h *QuorumHandler) CreateAPIKey(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok || !h.requireAdminCredential(c, "Creating API keys") {
+		return
+	}
+
+	var req models.APIKeyRequest
+	if err := h.bindJSON(c, &req); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	scopes, err := storage.ValidateScopes(req.Scopes)
+	if err == nil && (req.Name == "" || len(req.Name) > 128) {
+		err = errors.New("name must be 1-128 characters")
+	}
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid API key: " + err.Error(),
+		})
+		return
+	}
+
+	key, info, err := ext.CreateAPIKey(req.Name, scopes)
+	if errors.Is(err, storage.ErrAPIKeyNameTaken) {
+		respondError(c, http.StatusConflict, models.ErrCodeAPIKeyNameTaken, models.BasicResponse{
+			Status:  false,
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to create API key: " + err.Error(),
+		})
+		return
+	}
+
+	log.Printf("🔑 Created API key %q with scopes %v\n", info.Name, info.Scopes)
+	respond(c, http.StatusCreated, gin.H{
+		"status":  true,
+		"message": fmt.Sprintf("Created API key %s; store it now, it is not shown again", info.Name),
+		"key":     key,
+		"api_key": info,
+	})
+}
+
+// ListAPIKeys handles GET /api/admin/api-keys
+func (h *QuorumHandler) ListAPIKeys(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	keys, err := ext.ListAPIKeys()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to list API keys: " + err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"status":   true,
+		"count":    len(keys),
+		"api_keys": keys,
+	})
+}
+
+// DeleteAPIKey handles DELETE /api/admin/api-keys/:id
+func (h *QuorumHandler) DeleteAPIKey(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid API key ID",
+		})
+		return
+	}
+
+	if err := ext.DeleteAPIKey(uint(id)); err != nil {
+		if errors.Is(err, storage.ErrAPIKeyNotFound) {
+			respondError(c, http.StatusNotFound, models.ErrCodeAPIKeyNotFound, models.BasicResponse{
+				Status:  false,
+				Message: err.Error(),
+			})
+			return
+		}
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to delete API key: " + err.Error(),
+		})
+		return
+	}
+
+	log.Printf("🔑 Deleted API key %d\n", id)
+	respond(c, http.StatusOK, models.BasicResponse{
+		Status:  true,
+		Message: "API key deleted",
+	})
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/models"
+)
+
+// balanceUnits maps each accepted ?balance_unit= to the number of units per RBT
+var balanceUnits = map[string]float64{
+	"rbt":   1,
+	"milli": 1e3,
+	"micro": 1e6,
+}
+
+// maxBalancePrecision bounds ?balance_precision=
+const maxBalancePrecision = 8
+
+// balanceFormat scales and rounds the balance fields of quorum responses. Stored
+// balances are always RBT; the format only changes what a response reports.
+type balanceFormat struct {
+	unit      string
+	scale     float64
+	precision int // Decimal places to round to; -1 leaves values unrounded
+}
+
+// parseBalanceFormat reads ?balance_unit= (default rbt) and ?balance_precision=
+func parseBalanceFormat(c *gin.Context) (balanceFormat, *paramError) {
+	format := balanceFormat{unit: "rbt", scale: 1, precision: -1}
+
+	if unit := strings.ToLower(strings.TrimSpace(c.Query("balance_unit"))); unit != "" {
+		scale, ok := balanceUnits[unit]
+		if !ok {
+			return format, &paramError{models.ErrCodeInvalidRequest, "Invalid balance_unit. Use rbt, milli or micro"}
+		}
+		format.unit, format.scale = unit, scale
+	}
+
+	if precisionStr := c.Query("balance_precision"); precisionStr != "" {
+		precision, err := strconv.Atoi(precisionStr)
+		if err != nil || precision < 0 || precision > maxBalancePrecision {
+			return format, &paramError{models.ErrCodeInvalidRequest,
+				fmt.Sprintf("balance_precision must be an integer between 0 and %d", maxBalancePrecision)}
+		}
+		format.precision = precision
+	} else if format.scale != 1 {
+		// Scaling leaves float noise (1234.5678899999998); trim it by default
+		format.precision = maxBalancePrecision
+	}
+
+	return format, nil
+}
+
+// convert scales an RBT amount to the format's unit and rounds it
+func (f balanceFormat) convert(rbt float64) float64 {
+	value := rbt * f.scale
+	if f.precision >= 0 {
+		pow := math.Pow10(f.precision)
+		value = math.Round(value*pow) / pow
+	}
+	return value
+}
+
+// apply rewrites q's balance fields in the format's unit
+func (f balanceFormat) apply(q *models.QuorumInfo) {
+	q.Balance = f.convert(q.Balance)
+	q.MinParticipationBalance = f.convert(q.MinParticipationBalance)
+	q.MaxTransactionShare = f.convert(q.MaxTransactionShare)
+}
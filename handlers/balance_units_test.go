@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/models"
+	"github.com/gklps/advisory-node/storage"
+)
+
+func TestQuorumInfoBalanceUnits(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	h := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.GET("/api/quorum/info/:did", h.GetQuorumInfo)
+	did := registerTestQuorum(t, store, "1", 1234.56789, func(req *models.QuorumRegistrationRequest) {
+		req.MinParticipationBalance = 1.5
+		req.MaxTransactionShare = 250
+	})
+
+	tests := []struct {
+		query                  string
+		wantUnit               string
+		wantBalance, wantFloor float64
+		wantShare              float64
+	}{
+		{"", "rbt", 1234.56789, 1.5, 250},
+		{"?balance_unit=rbt", "rbt", 1234.56789, 1.5, 250},
+		{"?balance_unit=milli", "milli", 1234567.89, 1500, 250000},
+		{"?balance_unit=MICRO", "micro", 1234567890, 1500000, 250000000},
+		{"?balance_precision=2", "rbt", 1234.57, 1.5, 250},
+		{"?balance_unit=milli&balance_precision=0", "milli", 1234568, 1500, 250000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := serve(router, http.MethodGet, "/api/quorum/info/"+did+tt.query, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body)
+			}
+			var resp struct {
+				Quorum      models.QuorumInfo `json:"quorum"`
+				BalanceUnit string            `json:"balance_unit"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			q := resp.Quorum
+			if resp.BalanceUnit != tt.wantUnit || q.Balance != tt.wantBalance ||
+				q.MinParticipationBalance != tt.wantFloor || q.MaxTransactionShare != tt.wantShare {
+				t.Errorf("got %s %v/%v/%v, want %s %v/%v/%v", resp.BalanceUnit, q.Balance, q.MinParticipationBalance,
+					q.MaxTransactionShare, tt.wantUnit, tt.wantBalance, tt.wantFloor, tt.wantShare)
+			}
+		})
+	}
+
+	for _, query := range []string{"?balance_unit=satoshi", "?balance_precision=9", "?balance_precision=-1"} {
+		if w := serve(router, http.MethodGet, "/api/quorum/info/"+did+query, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("%s status = %d, want 400", query, w.Code)
+		}
+	}
+
+	// Stored balances stay in RBT
+	quorum, err := store.GetQuorumByDID(did)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quorum.Balance != 1234.56789 {
+		t.Errorf("stored balance = %v, want 1234.56789", quorum.Balance)
+	}
+}
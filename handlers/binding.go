@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// strictJSONBinding decodes JSON like binding.JSON but fails on fields the target struct doesn't declare,
+// so typos such as "balnce" are reported instead of silently leaving a zero value
+type strictJSONBinding struct{}
+
+func (strictJSONBinding) Name() string {
+	return "json"
+}
+
+func (strictJSONBinding) Bind(req *http.Request, obj interface{}) error {
+	if req == nil || req.Body == nil {
+		return errors.New("invalid request")
+	}
+
+	return decodeStrict(json.NewDecoder(req.Body), obj)
+}
+
+// BindBody decodes an already-read body, such as one line of an NDJSON upload
+func (strictJSONBinding) BindBody(body []byte, obj interface{}) error {
+	return decodeStrict(json.NewDecoder(bytes.NewReader(body)), obj)
+}
+
+func decodeStrict(decoder *json.Decoder, obj interface{}) error {
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		return err
+	}
+
+	return binding.Validator.ValidateStruct(obj)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/storage"
+)
+
+func TestUnknownJSONFields(t *testing.T) {
+	body := `{"did":"` + testDID("1") + `","peer_id":"peer1","did_type":4,"balance":10,"balnce":5}`
+
+	tests := []struct {
+		name   string
+		strict bool
+		want   int
+	}{
+		{"lenient", false, http.StatusOK},
+		{"strict", true, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newTestStore(t, storage.DBConfig{})
+			h := newTestHandler(store, HandlerConfig{StrictJSON: tt.strict})
+			router := gin.New()
+			router.POST("/api/quorum/register", h.RegisterQuorum)
+
+			w := serve(router, http.MethodPost, "/api/quorum/register", body)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d; body %s", w.Code, tt.want, w.Body)
+			}
+
+			_, err := store.GetQuorumByDID(testDID("1"))
+			if tt.strict {
+				if err == nil {
+					t.Error("strict mode registered the quorum anyway")
+				}
+				if message, _ := decodeBody(t, w)["message"].(string); !strings.Contains(message, `unknown field "balnce"`) {
+					t.Errorf("message = %q, want it to name the unknown field", message)
+				}
+			} else if err != nil {
+				t.Errorf("lenient mode didn't register the quorum: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/models"
+)
+
+// DefaultMaxBodySize is the largest request body accepted by default
+const DefaultMaxBodySize = 1 << 20
+
+// DefaultMaxImportSize is the largest pool export POST /api/admin/import accepts by
+// default; the document is decoded whole, so it has a cap of its own
+const DefaultMaxImportSize = 64 << 20
+
+// LimitBodySize answers requests whose body exceeds maxBytes with 413 before a handler
+// buffers it. Bodies within the limit are read up front and restored, so chunked
+// uploads are caught too. Routes in exempt (by route path, e.g. a streaming upload)
+// and a maxBytes that is not positive are not limited.
+func LimitBodySize(maxBytes int64, exempt ...string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if maxBytes <= 0 || c.Request.Body == nil || c.Request.Body == http.NoBody || skip[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			respondBodyTooLarge(c, maxBytes)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondBodyTooLarge(c, maxBytes)
+			return
+		}
+		if err != nil {
+			respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+				Status:  false,
+				Message: "Failed to read request body: " + err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+	}
+}
+
+func respondBodyTooLarge(c *gin.Context, maxBytes int64) {
+	respondError(c, http.StatusRequestEntityTooLarge, models.ErrCodeBodyTooLarge, models.BasicResponse{
+		Status:  false,
+		Message: fmt.Sprintf("Request body exceeds the %d byte limit", maxBytes),
+	})
+	c.Abort()
+}
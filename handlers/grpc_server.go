@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/gklps/advisory-node/metrics"
+	"github.com/gklps/advisory-node/models"
+	"github.com/gklps/advisory-node/proto/advisorypb"
+	"github.com/gklps/advisory-node/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GRPCServer serves the AdvisoryNode gRPC service. It wraps a QuorumHandler, so both
+// APIs share one store, configuration, selection backoff and webhook publisher, and apply
+// the same validation. Failures are reported as gRPC status codes carrying the message
+// the REST API would return.
+type GRPCServer struct {
+	advisorypb.UnimplementedAdvisoryNodeServer
+	h *QuorumHandler
+}
+
+// NewGRPCServer creates a gRPC service backed by handler
+func NewGRPCServer(handler *QuorumHandler) *GRPCServer {
+	return &GRPCServer{h: handler}
+}
+
+// extended returns the store's full API, or an Unimplemented status when the store only
+// provides the core Store methods
+func (s *GRPCServer) extended() (storage.ExtendedStore, error) {
+	if s.h.ext == nil {
+		return nil, status.Error(codes.Unimplemented, "Not supported by this store; use a database store")
+	}
+	return s.h.ext, nil
+}
+
+// RegisterQuorum mirrors POST /api/quorum/register for quorums without a public key
+func (s *GRPCServer) RegisterQuorum(ctx context.Context, in *advisorypb.RegisterQuorumRequest) (*advisorypb.BasicResponse, error) {
+	metrics.RegisterRequests.Inc()
+
+	if err := s.h.checkMaintenance(); err != nil {
+		return nil, err
+	}
+	if err := s.h.checkGRPCAPIKey(ctx, storage.ScopeWrite); err != nil {
+		return nil, err
+	}
+	if in.GetDid() == "" || in.GetPeerId() == "" || in.GetDidType() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "did, peer_id and did_type are required")
+	}
+	req := &models.QuorumRegistrationRequest{
+		DID:                     in.GetDid(),
+		PeerID:                  in.GetPeerId(),
+		Balance:                 in.GetBalance(),
+		DIDType:                 int(in.GetDidType()),
+		SupportedTokens:         in.GetSupportedTokens(),
+		Capabilities:            in.GetCapabilities(),
+		Region:                  in.GetRegion(),
+		Tier:                    in.GetTier(),
+		Namespace:               in.GetNamespace(),
+		Metadata:                in.GetMetadata(),
+		MinParticipationBalance: in.GetMinParticipationBalance(),
+		MaxTransactionShare:     in.GetMaxTransactionShare(),
+	}
+
+	paramErr := s.h.validateRegistration(req)
+	if paramErr == nil {
+		paramErr = s.h.checkUnsignedRegistration(req)
+	}
+	if paramErr != nil {
+		return nil, paramErr.grpcStatus()
+	}
+
+	if err := s.h.store.RegisterQuorum(req); err != nil {
+		if errors.Is(err, storage.ErrTombstoned) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, status.Error(codes.Internal, "Failed to register quorum: "+err.Error())
+	}
+	s.h.backoff.poolGrew()
+	s.h.publishRegistered(req)
+
+	return &advisorypb.BasicResponse{
+		Status:  true,
+		Message: fmt.Sprintf("Quorum registered successfully with balance: %.4f", req.Balance),
+	}, nil
+}
+
+// ConfirmAvailability mirrors POST /api/quorum/confirm-availability
+func (s *GRPCServer) ConfirmAvailability(ctx context.Context, in *advisorypb.ConfirmAvailabilityRequest) (*advisorypb.BasicResponse, error) {
+	if err := s.h.checkMaintenance(); err != nil {
+		return nil, err
+	}
+	if err := s.h.checkGRPCAPIKey(ctx, storage.ScopeWrite); err != nil {
+		return nil, err
+	}
+	if !isValidDID(in.GetDid()) {
+		return nil, status.Error(codes.InvalidArgument, "Invalid DID format")
+	}
+
+	if err := s.h.store.ConfirmAvailability(in.GetDid()); err != nil {
+		return nil, quorumStatus(err, "Failed to confirm availability")
+	}
+	s.h.backoff.poolGrew()
+
+	return &advisorypb.BasicResponse{Status: true, Message: "Availability confirmed"}, nil
+}
+
+// GetAvailableQuorums mirrors GET /api/quorum/available. Insufficient or not-ready pools
+// are Unavailable and backed-off clients ResourceExhausted.
+func (s *GRPCServer) GetAvailableQuorums(ctx context.Context, in *advisorypb.GetAvailableQuorumsRequest) (*advisorypb.QuorumListResponse, error) {
+	if err := s.h.checkGRPCAPIKey(ctx, s.h.readScope()); err != nil {
+		return nil, err
+	}
+	req := selectionRequest(in)
+	paramErr := s.h.validateSelection(&req)
+	if paramErr == nil {
+		paramErr = s.h.checkSelectionFilters(req)
+	}
+	if paramErr != nil {
+		return nil, paramErr.grpcStatus()
+	}
+
+	client, params := grpcClientIP(ctx), selectionKey(req)
+	if wait, backedOff := s.h.backoff.retryAfter(client, params); backedOff {
+		metrics.AvailableRequests.WithLabelValues("throttled").Inc()
+		return nil, status.Errorf(codes.ResourceExhausted, "Repeated selections with these parameters failed; retry after %s", wait.Round(time.Second))
+	}
+
+	requiredBalance := s.h.store.RequiredBalance(req.TransactionAmount, req.Count)
+	selection, err := s.h.selectQuorums(req, requiredBalance)
+	switch {
+	case err == nil:
+		metrics.AvailableRequests.WithLabelValues("success").Inc()
+		s.h.backoff.succeed(client, params)
+	case errors.Is(err, storage.ErrAssignmentNotRecorded):
+		metrics.AvailableRequests.WithLabelValues("error").Inc()
+		return nil, status.Error(codes.Internal, "Selection failed: "+err.Error())
+	default:
+		metrics.AvailableRequests.WithLabelValues("insufficient").Inc()
+		s.h.backoff.fail(client, params)
+
+		var poolErr *storage.PoolNotReadyError
+		if errors.As(err, &poolErr) {
+			return nil, status.Error(codes.Unavailable, "Selection unavailable: "+poolErr.Error())
+		}
+		return nil, status.Errorf(codes.Unavailable, "Not enough quorums with required balance (%.4f RBT): %v",
+			s.h.effectiveRequiredBalance(requiredBalance), err)
+	}
+
+	quorums := make([]*advisorypb.QuorumData, 0, len(selection.Quorums))
+	for _, q := range selection.Quorums {
+		quorums = append(quorums, &advisorypb.QuorumData{Type: int32(q.Type), Address: q.Address})
+	}
+	return &advisorypb.QuorumListResponse{
+		Status:         true,
+		Message:        selectionMessage(req, selection, requiredBalance),
+		Quorums:        quorums,
+		TransactionId:  selection.TransactionID,
+		RequestedCount: int32(req.Count),
+		DeliveredCount: int32(len(quorums)),
+	}, nil
+}
+
+// UpdateBalance mirrors PUT /api/quorum/balance for quorums without a public key
+func (s *GRPCServer) UpdateBalance(ctx context.Context, in *advisorypb.UpdateBalanceRequest) (*advisorypb.BasicResponse, error) {
+	if err := s.h.checkMaintenance(); err != nil {
+		return nil, err
+	}
+	if err := s.h.checkGRPCAPIKey(ctx, storage.ScopeWrite); err != nil {
+		return nil, err
+	}
+	ext, err := s.extended()
+	if err != nil {
+		return nil, err
+	}
+	if !isValidDID(in.GetDid()) {
+		return nil, status.Error(codes.InvalidArgument, "Invalid DID format")
+	}
+	if in.GetBalance() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "Balance cannot be negative")
+	}
+	if err := s.h.checkUnsignedRequest(in.GetDid()); err != nil {
+		return nil, err
+	}
+
+	if err := ext.UpdateQuorumBalance(in.GetDid(), in.GetBalance()); err != nil {
+		return nil, quorumStatus(err, "Failed to update balance")
+	}
+	s.h.backoff.poolGrew()
+
+	return &advisorypb.BasicResponse{
+		Status:  true,
+		Message: fmt.Sprintf("Balance updated to %.4f RBT", in.GetBalance()),
+	}, nil
+}
+
+// Heartbeat mirrors a plain POST /api/quorum/heartbeat for quorums without a public key
+func (s *GRPCServer) Heartbeat(ctx context.Context, in *advisorypb.HeartbeatRequest) (*advisorypb.BasicResponse, error) {
+	if err := s.h.checkMaintenance(); err != nil {
+		return nil, err
+	}
+	if err := s.h.checkGRPCAPIKey(ctx, storage.ScopeWrite); err != nil {
+		return nil, err
+	}
+	if !isValidDID(in.GetDid()) {
+		return nil, status.Error(codes.InvalidArgument, "Invalid DID format")
+	}
+	if err := s.h.checkUnsignedRequest(in.GetDid()); err != nil {
+		return nil, err
+	}
+
+	if err := s.h.store.UpdateHeartbeat(in.GetDid()); err != nil {
+		return nil, quorumStatus(err, "Failed to update heartbeat")
+	}
+
+	return &advisorypb.BasicResponse{Status: true, Message: "Heartbeat updated"}, nil
+}
+
+// GetHealth mirrors GET /api/quorum/health
+func (s *GRPCServer) GetHealth(ctx context.Context, in *advisorypb.GetHealthRequest) (*advisorypb.HealthStatus, error) {
+	if err := s.h.checkGRPCAPIKey(ctx, s.h.readScope()); err != nil {
+		return nil, err
+	}
+	namespace := in.GetNamespace()
+	if namespace != "" {
+		namespace = normalizeNamespace(namespace)
+		if !isValidNamespace(namespace) {
+			return nil, status.Error(codes.InvalidArgument, "Invalid namespace. Use up to 64 lowercase letters, digits, '-' or '_'")
+		}
+	}
+
+	health := s.h.store.GetHealthStatus()
+	if namespace != "" {
+		ext, err := s.extended()
+		if err != nil {
+			return nil, err
+		}
+		health = ext.GetNamespaceHealthStatus(namespace)
+	}
+	return &advisorypb.HealthStatus{
+		Status:           health.Status,
+		TotalQuorums:     int32(health.TotalQuorums),
+		AvailableQuorums: int32(health.AvailableQuorums),
+		Uptime:           health.Uptime,
+		LastCheck:        timestamppb.New(health.LastCheck),
+	}, nil
+}
+
+// selectionRequest converts a selection request for validateSelection, which checks it
+// as it does the REST query parameters
+func selectionRequest(in *advisorypb.GetAvailableQuorumsRequest) models.QuorumListRequest {
+	req := models.QuorumListRequest{
+		Count:             int(in.GetCount()),
+		TransactionAmount: in.GetTransactionAmount(),
+		LastCharTID:       in.GetLastCharTid(),
+		Type:              int(in.GetType()),
+		FTName:            in.GetFtName(),
+		TransactionID:     in.GetTransactionId(),
+		MinRegions:        int(in.GetMinRegions()),
+		Region:            in.GetRegion(),
+		Requires:          in.GetRequires(),
+		MinSuccessfulTxns: in.GetMinSuccessfulTxns(),
+		Namespace:         in.GetNamespace(),
+		MinTier:           in.GetMinTier(),
+	}
+	if in.DidType != nil {
+		didType := int(in.GetDidType())
+		req.DIDType = &didType
+	}
+	return req
+}
+
+// checkUnsignedRequest rejects a gRPC request acting on did when REST would require it
+// to be signed: the quorum has a public key, or signatures are required for every quorum
+func (h *QuorumHandler) checkUnsignedRequest(did string) error {
+	key, err := h.publicKey(did)
+	if err != nil && !errors.Is(err, storage.ErrQuorumNotFound) {
+		return status.Error(codes.Internal, "Failed to look up public key: "+err.Error())
+	}
+	if key != "" {
+		return status.Error(codes.Unauthenticated, "Quorums with a public_key must send signed requests through the REST API")
+	}
+	if h.config.RequireSignatures {
+		return status.Error(codes.Unauthenticated, "Signed requests are required: use the REST API")
+	}
+	return nil
+}
+
+// grpcStatus converts a validation failure to the matching gRPC status
+func (e *paramError) grpcStatus() error {
+	switch e.Code {
+	case models.ErrCodeInternal:
+		return status.Error(codes.Internal, e.Message)
+	case models.ErrCodeInvalidSignature:
+		return status.Error(codes.Unauthenticated, e.Message)
+	case models.ErrCodeTombstoned:
+		return status.Error(codes.PermissionDenied, e.Message)
+	case models.ErrCodeNotImplemented:
+		return status.Error(codes.Unimplemented, e.Message)
+	default:
+		return status.Error(codes.InvalidArgument, e.Message)
+	}
+}
+
+// quorumStatus reports a store failure for a DID-addressed request, like respondQuorumError
+func quorumStatus(err error, action string) error {
+	if errors.Is(err, storage.ErrQuorumNotFound) {
+		return status.Error(codes.NotFound, "Quorum not found")
+	}
+	if errors.Is(err, storage.ErrQuorumDisabled) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, action+": "+err.Error())
+}
+
+// grpcClientIP returns the caller's IP, which keys the selection backoff as on REST
+func grpcClientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/proto/advisorypb"
+	"github.com/gklps/advisory-node/storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestSelectionValidationMatchesAcrossTransports(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	handler := newTestHandler(store, HandlerConfig{})
+	server := NewGRPCServer(handler)
+	router := gin.New()
+	router.GET("/api/quorum/available", handler.GetAvailableQuorums)
+
+	didType := func(v int32) *int32 { return &v }
+	for _, tc := range []struct {
+		query string
+		in    *advisorypb.GetAvailableQuorumsRequest
+	}{
+		{"count=3", &advisorypb.GetAvailableQuorumsRequest{Count: 3}},
+		{"count=2000000000&transaction_amount=10&min_regions=2", &advisorypb.GetAvailableQuorumsRequest{Count: 2000000000, TransactionAmount: 10, MinRegions: 2}},
+		{"count=3&transaction_amount=10&min_regions=4", &advisorypb.GetAvailableQuorumsRequest{Count: 3, TransactionAmount: 10, MinRegions: 4}},
+		{"count=3&transaction_amount=10&region=EU%20West", &advisorypb.GetAvailableQuorumsRequest{Count: 3, TransactionAmount: 10, Region: "EU West"}},
+		{"count=3&transaction_amount=10&requires=gpu,no%20spaces", &advisorypb.GetAvailableQuorumsRequest{Count: 3, TransactionAmount: 10, Requires: []string{"gpu", "no spaces"}}},
+		{"count=3&transaction_amount=10&min_successful_txns=-1", &advisorypb.GetAvailableQuorumsRequest{Count: 3, TransactionAmount: 10, MinSuccessfulTxns: -1}},
+		{"count=3&transaction_amount=10&namespace=no%20spaces", &advisorypb.GetAvailableQuorumsRequest{Count: 3, TransactionAmount: 10, Namespace: "no spaces"}},
+		{"count=3&transaction_amount=10&did_type=5", &advisorypb.GetAvailableQuorumsRequest{Count: 3, TransactionAmount: 10, DidType: didType(5)}},
+		{"count=3&transaction_amount=10&min_tier=platinum", &advisorypb.GetAvailableQuorumsRequest{Count: 3, TransactionAmount: 10, MinTier: "platinum"}},
+	} {
+		w := serve(router, http.MethodGet, "/api/quorum/available?"+tc.query, "")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: REST status = %d, want 400; body %s", tc.query, w.Code, w.Body)
+			continue
+		}
+		message := fmt.Sprint(decodeBody(t, w)["message"])
+
+		_, err := server.GetAvailableQuorums(context.Background(), tc.in)
+		if st := status.Convert(err); st.Code() != codes.InvalidArgument || st.Message() != message {
+			t.Errorf("%s: gRPC status = %v %q, want InvalidArgument %q", tc.query, st.Code(), st.Message(), message)
+		}
+	}
+}
+
+func TestGRPCRateLimit(t *testing.T) {
+	interceptor := GRPCRateLimit(NewRateLimiter("write", 1, true), NewRateLimiter("query", 1, false))
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 5000}})
+	call := func(method string, req interface{}) error {
+		t.Helper()
+		_, err := interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method},
+			func(context.Context, interface{}) (interface{}, error) { return nil, nil })
+		return err
+	}
+	limited := func(err error) bool { return status.Code(err) == codes.ResourceExhausted }
+
+	// One query a minute: the second selection is refused
+	selection := &advisorypb.GetAvailableQuorumsRequest{Count: 3, TransactionAmount: 10}
+	if err := call(advisorypb.AdvisoryNode_GetAvailableQuorums_FullMethodName, selection); err != nil {
+		t.Fatalf("first selection: %v", err)
+	}
+	if err := call(advisorypb.AdvisoryNode_GetHealth_FullMethodName, &advisorypb.GetHealthRequest{}); !limited(err) {
+		t.Errorf("query over the limit: error = %v, want ResourceExhausted", err)
+	}
+
+	// Writes are limited per DID, so another DID from the same client still gets through
+	first := &advisorypb.UpdateBalanceRequest{Did: testDID("1"), Balance: 10}
+	if err := call(advisorypb.AdvisoryNode_UpdateBalance_FullMethodName, first); err != nil {
+		t.Fatalf("first write: %v", err)
+	}
+	if err := call(advisorypb.AdvisoryNode_RegisterQuorum_FullMethodName, &advisorypb.RegisterQuorumRequest{Did: testDID("1")}); !limited(err) {
+		t.Errorf("write over the limit: error = %v, want ResourceExhausted", err)
+	}
+	if err := call(advisorypb.AdvisoryNode_UpdateBalance_FullMethodName, &advisorypb.UpdateBalanceRequest{Did: testDID("2")}); err != nil {
+		t.Errorf("write for another DID: %v", err)
+	}
+
+	// Heartbeats and confirmations are not limited, as on REST
+	for i := 0; i < 3; i++ {
+		if err := call(advisorypb.AdvisoryNode_Heartbeat_FullMethodName, &advisorypb.HeartbeatRequest{Did: testDID("1")}); err != nil {
+			t.Errorf("heartbeat %d: %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/metrics"
+	"github.com/gklps/advisory-node/models"
+)
+
+// DefaultIdempotencyTTL is how long a processed Idempotency-Key is remembered
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// Idempotency cache bounds: the longest key accepted, and how many keys are tracked
+// before expired ones are swept
+const (
+	maxIdempotencyKeyLength  = 255
+	maxTrackedIdempotencyKey = 10000
+)
+
+// IdempotencyCache replays the stored response to a request retried with the same
+// Idempotency-Key header instead of running it again, so a node re-registering after a
+// network error doesn't record the registration twice. Keys are scoped to the route; a
+// key reused with a different body is refused. Server errors are not stored, so those
+// requests can be retried under the same key.
+type IdempotencyCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*idempotentResponse // Key: route + Idempotency-Key
+}
+
+type idempotentResponse struct {
+	fingerprint [sha256.Size]byte // Hash of the request body
+	done        bool              // False while the first request is still being handled
+	status      int
+	contentType string
+	body        []byte
+	expiresAt   time.Time
+}
+
+// NewIdempotencyCache creates a cache remembering responses for ttl. It returns nil when
+// ttl is not positive; a nil cache's middleware ignores Idempotency-Key.
+func NewIdempotencyCache(ttl time.Duration) *IdempotencyCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &IdempotencyCache{
+		ttl:     ttl,
+		entries: make(map[string]*idempotentResponse),
+	}
+}
+
+// Middleware replays stored responses for repeated Idempotency-Key headers. A key still
+// being handled is answered with 409, and a key reused with a different body with 422.
+func (ic *IdempotencyCache) Middleware() gin.HandlerFunc {
+	if ic == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader("Idempotency-Key")
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+				Status:  false,
+				Message: "Idempotency-Key must be at most 255 characters",
+			})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+				Status:  false,
+				Message: "Failed to read request body: " + err.Error(),
+			})
+			c.Abort()
+			return
+		}
+
+		cacheKey := c.FullPath() + "|" + key
+		fingerprint := sha256.Sum256(body)
+		entry, claimed := ic.claim(cacheKey, fingerprint, time.Now())
+		if !claimed {
+			ic.replay(c, fingerprint, entry)
+			return
+		}
+
+		recorder := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		stored := false
+		defer func() {
+			if !stored {
+				ic.release(cacheKey, entry)
+			}
+		}()
+
+		c.Next()
+
+		if recorder.Status() < http.StatusInternalServerError {
+			ic.store(entry, recorder.Status(), recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+			stored = true
+		}
+	}
+}
+
+// claim records a new in-progress entry for key and returns it with true, or returns
+// the live entry already holding key with false
+func (ic *IdempotencyCache) claim(key string, fingerprint [sha256.Size]byte, now time.Time) (*idempotentResponse, bool) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	if entry, ok := ic.entries[key]; ok && (!entry.done || now.Before(entry.expiresAt)) {
+		return entry, false
+	}
+	if len(ic.entries) >= maxTrackedIdempotencyKey {
+		ic.sweep(now)
+	}
+
+	entry := &idempotentResponse{fingerprint: fingerprint}
+	ic.entries[key] = entry
+	return entry, true
+}
+
+// replay answers a repeated key: the stored response, or a conflict when the first
+// request is still running or the bodies differ
+func (ic *IdempotencyCache) replay(c *gin.Context, fingerprint [sha256.Size]byte, existing *idempotentResponse) {
+	ic.mu.Lock()
+	done := existing.done
+	ic.mu.Unlock()
+
+	switch {
+	case existing.fingerprint != fingerprint:
+		respondError(c, http.StatusUnprocessableEntity, models.ErrCodeIdempotencyKeyReused, models.BasicResponse{
+			Status:  false,
+			Message: "Idempotency-Key was already used with a different request body",
+		})
+	case !done:
+		respondError(c, http.StatusConflict, models.ErrCodeIdempotencyInProgress, models.BasicResponse{
+			Status:  false,
+			Message: "A request with this Idempotency-Key is still being processed",
+		})
+	default:
+		metrics.IdempotentReplays.Inc()
+		c.Header("Idempotent-Replayed", "true")
+		c.Data(existing.status, existing.contentType, existing.body)
+	}
+	c.Abort()
+}
+
+// store completes entry with the response to replay until the TTL passes
+func (ic *IdempotencyCache) store(entry *idempotentResponse, status int, contentType string, body []byte) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	entry.status = status
+	entry.contentType = contentType
+	entry.body = body
+	entry.expiresAt = time.Now().Add(ic.ttl)
+	entry.done = true
+}
+
+// release forgets an entry whose request failed, so a retry runs it again
+func (ic *IdempotencyCache) release(key string, entry *idempotentResponse) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	if ic.entries[key] == entry {
+		delete(ic.entries, key)
+	}
+}
+
+// sweep drops expired entries; callers hold ic.mu
+func (ic *IdempotencyCache) sweep(now time.Time) {
+	for key, entry := range ic.entries {
+		if entry.done && !now.Before(entry.expiresAt) {
+			delete(ic.entries, key)
+		}
+	}
+}
+
+// recordingWriter keeps a copy of the response body as it is written
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/models"
+	"github.com/gklps/advisory-node/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const maintenanceMessage = "Service is in maintenance mode: writes are temporarily disabled"
+
+// Maintenance is the service-wide read-only switch, for database migrations and incident
+// response. While it is on, writes are answered with 503 and selection and health checks
+// keep being served. A nil Maintenance is never on.
+type Maintenance struct {
+	enabled atomic.Bool
+}
+
+// NewMaintenance creates the switch, initially on or off
+func NewMaintenance(enabled bool) *Maintenance {
+	m := &Maintenance{}
+	m.enabled.Store(enabled)
+	return m
+}
+
+// Enabled reports whether writes are being refused
+func (m *Maintenance) Enabled() bool {
+	return m != nil && m.enabled.Load()
+}
+
+// SetEnabled turns maintenance mode on or off, returning whether that changed it
+func (m *Maintenance) SetEnabled(enabled bool) bool {
+	return m.enabled.Swap(enabled) != enabled
+}
+
+// Middleware answers writes with 503 while maintenance mode is on. Every request other
+// than GET, HEAD and OPTIONS is a write, except on the routes in allowed (by route path),
+// which are read-only checks or operator endpoints that must keep working.
+func (m *Maintenance) Middleware(allowed ...string) gin.HandlerFunc {
+	skip := make(map[string]bool, len(allowed))
+	for _, path := range allowed {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if !m.Enabled() || !isWriteMethod(c.Request.Method) || skip[c.FullPath()] || c.FullPath() == "" {
+			c.Next()
+			return
+		}
+
+		respondError(c, http.StatusServiceUnavailable, models.ErrCodeMaintenance, models.BasicResponse{
+			Status:  false,
+			Message: maintenanceMessage,
+		})
+		c.Abort()
+	}
+}
+
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// checkMaintenance refuses a gRPC write while maintenance mode is on
+func (h *QuorumHandler) checkMaintenance() error {
+	if h.config.Maintenance.Enabled() {
+		return status.Error(codes.Unavailable, maintenanceMessage)
+	}
+	return nil
+}
+
+// RequireAdmin guards /api/admin: when an admin token is configured, requests must
+// carry it as "Authorization: Bearer <token>". With API keys enabled, an API key with
+// the admin scope is accepted instead.
+func (h *QuorumHandler) RequireAdmin(c *gin.Context) {
+	token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	validToken := found && h.config.AdminToken != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(h.config.AdminToken)) == 1
+
+	switch {
+	case validToken, h.config.AdminToken == "" && !h.config.APIKeys:
+		c.Next()
+	case h.config.APIKeys:
+		if h.authorizeAPIKey(c, storage.ScopeAdmin) {
+			c.Next()
+		}
+	default:
+		respondError(c, http.StatusUnauthorized, models.ErrCodeUnauthorized, models.BasicResponse{
+			Status:  false,
+			Message: "A valid admin token is required",
+		})
+		c.Abort()
+	}
+}
+
+// requireAdminCredential refuses an operator endpoint with 503 unless an admin token or
+// API keys are configured, since RequireAdmin lets every request through without either.
+// It reports whether the request may proceed.
+func (h *QuorumHandler) requireAdminCredential(c *gin.Context, endpoint string) bool {
+	if h.config.AdminToken != "" || h.config.APIKeys {
+		return true
+	}
+	respondError(c, http.StatusServiceUnavailable, models.ErrCodeInternal, models.BasicResponse{
+		Status:  false,
+		Message: endpoint + " requires an admin token or API keys to be configured",
+	})
+	return false
+}
+
+// SetMaintenance handles POST /api/admin/maintenance
+// Turns read-only maintenance mode on or off. It is refused unless an admin token or API
+// keys are configured, since it stops every quorum from registering and heartbeating.
+func (h *QuorumHandler) SetMaintenance(c *gin.Context) {
+	if (h.config.AdminToken == "" && !h.config.APIKeys) || h.config.Maintenance == nil {
+		respondError(c, http.StatusServiceUnavailable, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Maintenance toggle requires an admin token or API keys to be configured",
+		})
+		return
+	}
+
+	var req models.MaintenanceRequest
+	if err := h.bindJSON(c, &req); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	message := "Maintenance mode disabled: writes are accepted"
+	if *req.Enabled {
+		message = "Maintenance mode enabled: writes are refused"
+	}
+	if h.config.Maintenance.SetEnabled(*req.Enabled) {
+		log.Printf("🚧 %s\n", message)
+	}
+
+	respond(c, http.StatusOK, models.BasicResponse{
+		Status:  true,
+		Message: message,
+	})
+}
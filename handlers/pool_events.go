@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/models"
+)
+
+// poolEventsPingInterval is how often an idle event stream sends a keep-alive comment,
+// so proxies don't close it and disconnected clients are noticed
+const poolEventsPingInterval = 15 * time.Second
+
+// PoolEvents handles GET /api/quorum/events
+// Streams quorum pool changes as Server-Sent Events, one per registration,
+// unregistration, staleness or availability change, named after the event type
+func (h *QuorumHandler) PoolEvents(c *gin.Context) {
+	stream, unsubscribe, ok := h.config.Events.Subscribe()
+	if !ok {
+		respondError(c, http.StatusServiceUnavailable, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Event stream is not available",
+		})
+		return
+	}
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Stop nginx buffering the stream
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ping := time.NewTicker(poolEventsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case event, open := <-stream:
+			if !open {
+				// Dropped for falling behind, or shutting down
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(c.Writer, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+		case <-ping.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+		case <-c.Request.Context().Done():
+			return
+		}
+		c.Writer.Flush()
+	}
+}
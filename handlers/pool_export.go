@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/models"
+	"github.com/gklps/advisory-node/storage"
+)
+
+// Optional sections of a pool export, selected with ?include=
+const (
+	exportStats          = "stats"
+	exportBalanceHistory = "balance_history"
+)
+
+// ExportPool handles GET /api/admin/export
+// Dumps every registered quorum as a JSON document POST /api/admin/import restores, with
+// ?include=stats,balance_history adding those sections. Like the import, it is refused
+// unless an admin token or API keys are configured.
+func (h *QuorumHandler) ExportPool(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok || !h.requireAdminCredential(c, "Pool export") {
+		return
+	}
+
+	var includeStats, includeHistory bool
+	if include := c.Query("include"); include != "" {
+		for _, section := range strings.Split(include, ",") {
+			switch strings.TrimSpace(section) {
+			case exportStats:
+				includeStats = true
+			case exportBalanceHistory:
+				includeHistory = true
+			default:
+				respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+					Status:  false,
+					Message: fmt.Sprintf("Invalid include %q. Use %s and/or %s", section, exportStats, exportBalanceHistory),
+				})
+				return
+			}
+		}
+	}
+
+	export, err := ext.ExportPool(includeStats, includeHistory)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to export pool: " + err.Error(),
+		})
+		return
+	}
+
+	// The document is a file to keep, so it is never wrapped in a response envelope
+	filename := "advisory-pool-" + export.ExportedAt.UTC().Format("20060102T150405Z") + ".json"
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	WriteJSON(c, http.StatusOK, export)
+}
+
+// ImportPool handles POST /api/admin/import?mode=merge|replace
+// Restores a document from GET /api/admin/export in one transaction. Every quorum is
+// validated as a registration first, and any invalid entry rejects the whole document.
+// It is refused unless an admin token or API keys are configured, since a replace
+// import can empty the pool.
+func (h *QuorumHandler) ImportPool(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok || !h.requireAdminCredential(c, "Pool import") {
+		return
+	}
+
+	mode := c.DefaultQuery("mode", storage.ImportMerge)
+	if mode != storage.ImportMerge && mode != storage.ImportReplace {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: fmt.Sprintf("Invalid mode. Use %s or %s", storage.ImportMerge, storage.ImportReplace),
+		})
+		return
+	}
+
+	var export models.PoolExport
+	if err := h.bindJSON(c, &export); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid export document: " + err.Error(),
+		})
+		return
+	}
+	if paramErr := h.validatePoolExport(&export); paramErr != nil {
+		respondError(c, http.StatusBadRequest, paramErr.Code, models.BasicResponse{
+			Status:  false,
+			Message: paramErr.Message,
+		})
+		return
+	}
+
+	result, err := ext.ImportPool(&export, mode)
+	if errors.Is(err, storage.ErrTombstoned) {
+		respondError(c, http.StatusForbidden, models.ErrCodeTombstoned, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to import pool: " + err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to import pool: " + err.Error(),
+		})
+		return
+	}
+	h.backoff.poolGrew()
+
+	summary := models.PoolImportSummary{
+		Status: true,
+		Message: fmt.Sprintf("Imported %d quorums (%d created, %d updated), removed %d",
+			result.Created+result.Updated, result.Created, result.Updated, result.Removed),
+		Mode:           mode,
+		Created:        result.Created,
+		Updated:        result.Updated,
+		Removed:        result.Removed,
+		Stats:          result.Stats,
+		BalanceChanges: result.BalanceChanges,
+	}
+	log.Printf("📥 Pool import (%s): %s\n", mode, summary.Message)
+	respond(c, http.StatusOK, summary)
+}
+
+// validatePoolExport checks an export document before it is imported, normalizing each
+// quorum as registration does. Errors name the offending entry.
+func (h *QuorumHandler) validatePoolExport(export *models.PoolExport) *paramError {
+	if export.FormatVersion != storage.PoolExportVersion {
+		return &paramError{models.ErrCodeInvalidRequest, fmt.Sprintf("Unsupported format_version %d, expected %d", export.FormatVersion, storage.PoolExportVersion)}
+	}
+
+	seen := make(map[string]bool, len(export.Quorums))
+	for i := range export.Quorums {
+		q := &export.Quorums[i]
+		entry := fmt.Sprintf("quorums[%d]", i)
+		if paramErr := h.validateRegistration(&q.QuorumRegistrationRequest); paramErr != nil {
+			return &paramError{paramErr.Code, entry + ": " + paramErr.Message}
+		}
+		if seen[q.DID] {
+			return &paramError{models.ErrCodeInvalidRequest, fmt.Sprintf("%s: duplicate DID %s", entry, q.DID)}
+		}
+		seen[q.DID] = true
+		if q.AssignmentCount < 0 {
+			return &paramError{models.ErrCodeInvalidRequest, entry + ": assignment_count cannot be negative"}
+		}
+		if q.RegistrationTime.IsZero() {
+			q.RegistrationTime = time.Now()
+		}
+	}
+
+	withStats := make(map[string]bool, len(export.Stats))
+	for i, usage := range export.Stats {
+		if !isValidDID(usage.DID) {
+			return &paramError{models.ErrCodeInvalidDID, fmt.Sprintf("stats[%d]: invalid DID", i)}
+		}
+		if withStats[usage.DID] {
+			return &paramError{models.ErrCodeInvalidRequest, fmt.Sprintf("stats[%d]: duplicate DID %s", i, usage.DID)}
+		}
+		withStats[usage.DID] = true
+	}
+	for i, change := range export.BalanceHistory {
+		if !isValidDID(change.DID) {
+			return &paramError{models.ErrCodeInvalidDID, fmt.Sprintf("balance_history[%d]: invalid DID", i)}
+		}
+	}
+	return nil
+}
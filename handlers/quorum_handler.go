@@ -1,269 +1,2630 @@
 package handlers
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gklps/advisory-node/attestation"
+	"github.com/gklps/advisory-node/events"
+	"github.com/gklps/advisory-node/metrics"
 	"github.com/gklps/advisory-node/models"
 	"github.com/gklps/advisory-node/storage"
+	"github.com/gklps/advisory-node/webhooks"
 )
 
-// QuorumHandler handles all quorum-related API endpoints
+// QuorumHandler handles the quorum API on any storage.Store. The core endpoints
+// (registration, availability, selection, heartbeats, unregistration, lookups and
+// health) work on every store; the rest need it to implement storage.ExtendedStore as
+// well, and answer 501 NOT_IMPLEMENTED when it doesn't.
 type QuorumHandler struct {
-	store *storage.MemoryStore
+	store   storage.Store
+	ext     storage.ExtendedStore // The store's full API, nil when it only provides Store
+	config  HandlerConfig
+	backoff *selectionBackoff
+	tokens  map[string]bool // Token names registrations may declare
 }
 
+// HandlerConfig holds request-handling options for the quorum handler
+type HandlerConfig struct {
+	StrictJSON         bool                // Reject request bodies containing unknown JSON fields
+	MaxTokensPerQuorum int                 // Maximum supported_tokens entries per registration (0 uses the default)
+	Signer             *attestation.Signer // Signs eligibility attestations (nil disables the endpoint)
+	RequireSignatures  bool                // Reject unsigned requests from quorums without a public key
+	RelaxedPeerIDs     bool                // Accept any short base58 peer ID rather than libp2p ones only (test environments)
+	CustomTokens       []string            // Token names accepted in supported_tokens besides KnownTokens
+
+	// Consecutive failed selections with the same parameters after which a client is
+	// answered with 429 (0 disables), and the longest Retry-After it is given (0 uses
+	// DefaultSelectionBackoffMax)
+	SelectionBackoffFailures int
+	SelectionBackoffMax      time.Duration
+
+	Webhooks            *webhooks.Publisher // Notifies subscribers of registrations and unregistrations (nil disables)
+	WebhookAllowPrivate bool                // Accept webhook URLs on loopback, link-local and private hosts
+	Events              *events.Broadcaster // Feeds the pool change stream (nil disables it)
+
+	Maintenance *Maintenance // Read-only switch checked by gRPC writes (nil is never on)
+	AdminToken  string       // Bearer token required on /api/admin (empty leaves them open, but disables the maintenance toggle and availability switch)
+
+	// Require an X-API-Key with the scope each route group demands (see RequireScope),
+	// except on reads when APIKeysPublicReads is set
+	APIKeys            bool
+	APIKeysPublicReads bool
+}
+
+// DefaultMaxTokensPerQuorum bounds supported_tokens when no limit is configured
+const DefaultMaxTokensPerQuorum = 16
+
 // NewQuorumHandler creates a new quorum handler
-func NewQuorumHandler(store *storage.MemoryStore) *QuorumHandler {
+func NewQuorumHandler(store storage.Store) *QuorumHandler {
+	return NewQuorumHandlerWithConfig(store, HandlerConfig{})
+}
+
+// NewQuorumHandlerWithConfig creates a quorum handler with custom options
+func NewQuorumHandlerWithConfig(store storage.Store, config HandlerConfig) *QuorumHandler {
+	if config.MaxTokensPerQuorum <= 0 {
+		config.MaxTokensPerQuorum = DefaultMaxTokensPerQuorum
+	}
+
+	tokens := make(map[string]bool)
+	for _, token := range append(KnownTokens, config.CustomTokens...) {
+		if token = normalizeTokenName(token); token != "" {
+			tokens[token] = true
+		}
+	}
+
+	ext, _ := store.(storage.ExtendedStore)
 	return &QuorumHandler{
-		store: store,
+		store:   store,
+		ext:     ext,
+		config:  config,
+		backoff: newSelectionBackoff(config.SelectionBackoffFailures, config.SelectionBackoffMax),
+		tokens:  tokens,
+	}
+}
+
+// extended returns the store's full API. It responds with 501 and returns false when the
+// store only provides the core Store methods.
+func (h *QuorumHandler) extended(c *gin.Context) (storage.ExtendedStore, bool) {
+	if h.ext == nil {
+		respondError(c, http.StatusNotImplemented, models.ErrCodeNotImplemented, models.BasicResponse{
+			Status:  false,
+			Message: "Not supported by this store; use a database store",
+		})
+		return nil, false
+	}
+	return h.ext, true
+}
+
+// hasTier reports whether tier is one of the store's collateral tiers. Stores without
+// tiers don't select by them, so they accept any name.
+func (h *QuorumHandler) hasTier(tier string) bool {
+	return h.ext == nil || h.ext.HasTier(tier)
+}
+
+// publicKey returns the public key registered for did, or "" when there is none or
+// the store keeps no keys
+func (h *QuorumHandler) publicKey(did string) (string, error) {
+	if h.ext == nil {
+		return "", nil
 	}
+	return h.ext.GetPublicKey(did)
+}
+
+// checkRegistrationPolicy applies the store's tombstone policy to a registration.
+// Stores without tombstones have none to fail.
+func (h *QuorumHandler) checkRegistrationPolicy(req *models.QuorumRegistrationRequest) error {
+	if h.ext == nil {
+		return nil
+	}
+	return h.ext.CheckRegistrationPolicy(req)
+}
+
+// bindJSON binds the request body, rejecting unknown fields when strict mode is enabled
+func (h *QuorumHandler) bindJSON(c *gin.Context, obj interface{}) error {
+	if h.config.StrictJSON {
+		return c.ShouldBindWith(obj, strictJSONBinding{})
+	}
+	return c.ShouldBindJSON(obj)
 }
 
 // RegisterQuorum handles POST /api/quorum/register
 func (h *QuorumHandler) RegisterQuorum(c *gin.Context) {
+	metrics.RegisterRequests.Inc()
+
+	req, ok := h.bindRegistration(c)
+	if !ok {
+		return
+	}
+
+	// Register the quorum
+	if err := h.store.RegisterQuorum(req); err != nil {
+		respondRegisterError(c, err, "Failed to register quorum")
+		return
+	}
+	h.backoff.poolGrew()
+	h.publishRegistered(req)
+
+	respond(c, http.StatusOK, models.BasicResponse{
+		Status:  true,
+		Message: fmt.Sprintf("Quorum registered successfully with balance: %.4f", req.Balance),
+	})
+}
+
+// OnboardQuorum handles POST /api/quorum/onboard
+// Registers a quorum with its full profile and confirms its availability in one
+// transaction, so a starting node is selectable after a single call
+func (h *QuorumHandler) OnboardQuorum(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	req, ok := h.bindRegistration(c)
+	if !ok {
+		return
+	}
+
+	quorum, err := ext.OnboardQuorum(req)
+	if err != nil {
+		respondRegisterError(c, err, "Failed to onboard quorum")
+		return
+	}
+	h.backoff.poolGrew()
+	h.publishRegistered(req)
+
+	respond(c, http.StatusOK, gin.H{
+		"status":  true,
+		"message": fmt.Sprintf("Quorum onboarded and available with balance: %.4f", quorum.Balance),
+		"quorum":  quorum,
+	})
+}
+
+// Bulk import limits: the longest accepted NDJSON line, and how many line errors the
+// summary lists before truncating
+const (
+	maxImportLineBytes = 64 * 1024
+	maxImportErrors    = 100
+)
+
+// ImportQuorums handles POST /api/quorum/import
+// Registers quorums from an NDJSON upload of registration records, one per line. Lines
+// are read and registered one at a time, so uploads of any size use constant memory.
+// With ?dry_run=true every line is validated but nothing is written.
+func (h *QuorumHandler) ImportQuorums(c *gin.Context) {
+	dryRun := false
+	if dryRunStr := c.Query("dry_run"); dryRunStr != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(dryRunStr); err != nil {
+			respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+				Status:  false,
+				Message: "Invalid dry_run. Use true or false",
+			})
+			return
+		}
+	}
+
+	summary := models.ImportSummary{Status: true, DryRun: dryRun}
+	fail := func(line int, did, code, message string) {
+		summary.Failed++
+		if len(summary.Errors) >= maxImportErrors {
+			summary.ErrorsTruncated = true
+			return
+		}
+		summary.Errors = append(summary.Errors, models.ImportLineError{Line: line, DID: did, Code: code, Message: message})
+	}
+
+	var registered []*models.QuorumRegistrationRequest
+	scanner := bufio.NewScanner(c.Request.Body)
+	scanner.Buffer(make([]byte, 0, 4096), maxImportLineBytes)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		summary.Total++
+
+		var req models.QuorumRegistrationRequest
+		if err := h.bindBody(line, &req); err != nil {
+			fail(lineNo, req.DID, models.ErrCodeInvalidRequest, "Invalid record: "+err.Error())
+			continue
+		}
+		if paramErr := h.validateRegistration(&req); paramErr != nil {
+			fail(lineNo, req.DID, paramErr.Code, paramErr.Message)
+			continue
+		}
+
+		// Lines can't carry a request signature, so keyed quorums must use /register
+		if paramErr := h.checkUnsignedRegistration(&req); paramErr != nil {
+			fail(lineNo, req.DID, paramErr.Code, paramErr.Message)
+			continue
+		}
+
+		if !dryRun {
+			if err := h.store.RegisterQuorum(&req); err != nil {
+				code := models.ErrCodeInternal
+				if errors.Is(err, storage.ErrTombstoned) {
+					code = models.ErrCodeTombstoned
+				}
+				fail(lineNo, req.DID, code, err.Error())
+				continue
+			}
+			registered = append(registered, &req)
+		}
+		summary.Succeeded++
+	}
+
+	verb := "Imported"
+	if dryRun {
+		verb = "Validated"
+	}
+	summary.Message = fmt.Sprintf("%s %d of %d quorums, %d failed", verb, summary.Succeeded, summary.Total, summary.Failed)
+	if !dryRun && summary.Succeeded > 0 {
+		h.backoff.poolGrew()
+		h.publishRegistered(registered...)
+	}
+
+	// A read error (e.g. an over-long line) stops the import; earlier lines stay registered
+	if err := scanner.Err(); err != nil {
+		summary.Status = false
+		summary.Message += fmt.Sprintf("; stopped after line %d: %v", lineNo, err)
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, summary)
+		return
+	}
+
+	respond(c, http.StatusOK, summary)
+}
+
+// maxBatchRegistrations bounds the entries accepted by one register-batch request
+const maxBatchRegistrations = 500
+
+// RegisterQuorumsBatch handles POST /api/quorum/register-batch
+// Registers an array of quorums in one transaction, for nodes hosting many DIDs. Each
+// entry is validated and registered on its own and reported in the results, so one bad
+// DID doesn't fail the rest of the batch.
+func (h *QuorumHandler) RegisterQuorumsBatch(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	metrics.RegisterRequests.Inc()
+
+	var entries []json.RawMessage
+	if err := h.bindJSON(c, &entries); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: expected an array of registrations: " + err.Error(),
+		})
+		return
+	}
+	if len(entries) == 0 || len(entries) > maxBatchRegistrations {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: fmt.Sprintf("Batch must contain between 1 and %d registrations, got %d", maxBatchRegistrations, len(entries)),
+		})
+		return
+	}
+
+	response := models.BatchRegistrationResponse{
+		Status:  true,
+		Total:   len(entries),
+		Results: make([]models.BatchRegistrationResult, len(entries)),
+	}
+
+	// Entries that pass validation go to the store together; indexes maps them back
+	valid := make([]*models.QuorumRegistrationRequest, 0, len(entries))
+	indexes := make([]int, 0, len(entries))
+	for i, entry := range entries {
+		var req models.QuorumRegistrationRequest
+		response.Results[i] = models.BatchRegistrationResult{Index: i}
+
+		var paramErr *paramError
+		if err := h.bindBody(entry, &req); err != nil {
+			paramErr = &paramError{models.ErrCodeInvalidRequest, "Invalid registration: " + err.Error()}
+		} else if paramErr = h.validateRegistration(&req); paramErr == nil {
+			// A batch can't carry per-quorum signatures, so keyed quorums must use /register
+			paramErr = h.checkUnsignedRegistration(&req)
+		}
+
+		response.Results[i].DID = req.DID
+		if paramErr != nil {
+			response.Results[i].Code = paramErr.Code
+			response.Results[i].Message = paramErr.Message
+			continue
+		}
+		valid = append(valid, &req)
+		indexes = append(indexes, i)
+	}
+
+	if len(valid) > 0 {
+		results, err := ext.RegisterQuorumsBatch(valid)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+				Status:  false,
+				Message: "Failed to register batch: " + err.Error(),
+			})
+			return
+		}
+
+		for j, result := range results {
+			entry := &response.Results[indexes[j]]
+			switch {
+			case result.Err == nil:
+				entry.Status = true
+				entry.Message = fmt.Sprintf("Quorum registered successfully with balance: %.4f", valid[j].Balance)
+			case errors.Is(result.Err, storage.ErrTombstoned):
+				entry.Code = models.ErrCodeTombstoned
+				entry.Message = result.Err.Error()
+			default:
+				entry.Code = models.ErrCodeInternal
+				entry.Message = "Failed to register quorum: " + result.Err.Error()
+			}
+		}
+	}
+
+	registered := make([]*models.QuorumRegistrationRequest, 0, len(valid))
+	for j, i := range indexes {
+		if response.Results[i].Status {
+			registered = append(registered, valid[j])
+		}
+	}
+	for _, result := range response.Results {
+		if result.Status {
+			response.Succeeded++
+		} else {
+			response.Failed++
+		}
+	}
+	response.Message = fmt.Sprintf("Registered %d of %d quorums, %d failed", response.Succeeded, response.Total, response.Failed)
+	if response.Succeeded > 0 {
+		h.backoff.poolGrew()
+		h.publishRegistered(registered...)
+	}
+
+	respond(c, http.StatusOK, response)
+}
+
+// checkUnsignedRegistration rejects a registration that arrives without a request
+// signature (an import line or batch entry) when the quorum would need one: it carries
+// or already has a public key, or signatures are required for every quorum
+func (h *QuorumHandler) checkUnsignedRegistration(req *models.QuorumRegistrationRequest) *paramError {
+	key, err := h.publicKey(req.DID)
+	if err != nil && !errors.Is(err, storage.ErrQuorumNotFound) {
+		return &paramError{models.ErrCodeInternal, err.Error()}
+	}
+	if req.PublicKey != "" || key != "" {
+		return &paramError{models.ErrCodeInvalidSignature, "Quorums with a public_key must register through /api/quorum/register"}
+	}
+	if h.config.RequireSignatures {
+		return &paramError{models.ErrCodeInvalidSignature, "Signed requests are required: register the quorum through /api/quorum/register with a public_key"}
+	}
+	return nil
+}
+
+// bindBody decodes one JSON record, honoring StrictJSON like bindJSON
+func (h *QuorumHandler) bindBody(body []byte, obj interface{}) error {
+	if h.config.StrictJSON {
+		return strictJSONBinding{}.BindBody(body, obj)
+	}
+	return binding.JSON.BindBody(body, obj)
+}
+
+// bindRegistration binds, validates and authenticates a registration body. It responds
+// with 400 (or 401 for a bad signature) and returns false when the request is rejected.
+func (h *QuorumHandler) bindRegistration(c *gin.Context) (*models.QuorumRegistrationRequest, bool) {
+	var req models.QuorumRegistrationRequest
+
+	body, err := h.bindSignedJSON(c, &req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: " + err.Error(),
+		})
+		return nil, false
+	}
+
+	if paramErr := h.validateRegistration(&req); paramErr != nil {
+		respondError(c, http.StatusBadRequest, paramErr.Code, models.BasicResponse{
+			Status:  false,
+			Message: paramErr.Message,
+		})
+		return nil, false
+	}
+
+	if !h.authenticateQuorum(c, req.DID, body, req.PublicKey) {
+		return nil, false
+	}
+
+	return &req, true
+}
+
+// registrationCheck is one named step of registration validation. Checks may normalize
+// the request in place, so they run in order.
+type registrationCheck struct {
+	name  string
+	check func(h *QuorumHandler, req *models.QuorumRegistrationRequest) *paramError
+}
+
+var registrationChecks = []registrationCheck{
+	{"did_format", func(h *QuorumHandler, req *models.QuorumRegistrationRequest) *paramError {
+		if !isValidDID(req.DID) {
+			return &paramError{models.ErrCodeInvalidDID, "Invalid DID format. DID must start with 'bafybmi' and be 59 characters long"}
+		}
+		return nil
+	}},
+	{"peer_id_format", func(h *QuorumHandler, req *models.QuorumRegistrationRequest) *paramError {
+		if message, ok := checkPeerID(req.PeerID, h.config.RelaxedPeerIDs); !ok {
+			return &paramError{models.ErrCodeInvalidPeerID, message}
+		}
+		return nil
+	}},
+	{"public_key", func(h *QuorumHandler, req *models.QuorumRegistrationRequest) *paramError {
+		if req.PublicKey != "" && !isValidPublicKey(req.PublicKey) {
+			return &paramError{models.ErrCodeInvalidPublicKey, "Invalid public_key. Must be a base64-encoded Ed25519 public key"}
+		}
+		return nil
+	}},
+	{"did_type", func(h *QuorumHandler, req *models.QuorumRegistrationRequest) *paramError {
+		// Valid DID types are 0-4, where 4 is lite mode in RubixGo
+		if req.DIDType < 0 || req.DIDType > 4 {
+			return &paramError{models.ErrCodeInvalidDIDType, "Invalid DID type. Must be between 0 and 4"}
+		}
+		return nil
+	}},
+	{"balances", func(h *QuorumHandler, req *models.QuorumRegistrationRequest) *paramError {
+		if req.MinParticipationBalance < 0 {
+			return &paramError{models.ErrCodeInvalidBalance, "min_participation_balance cannot be negative"}
+		}
+		if req.MaxTransactionShare < 0 {
+			return &paramError{models.ErrCodeInvalidBalance, "max_transaction_share cannot be negative"}
+		}
+		return nil
+	}},
+	{"supported_tokens", func(h *QuorumHandler, req *models.QuorumRegistrationRequest) *paramError {
+		// Token filters match stored names exactly, so "rbt " would never be selected
+		tokens, err := normalizeTokens(req.SupportedTokens, h.tokens)
+		if err != nil {
+			return &paramError{models.ErrCodeInvalidToken, "Invalid supported_tokens: " + err.Error()}
+		}
+		req.SupportedTokens = tokens
+
+		// Bound per-row storage and token-filter cost
+		if len(req.SupportedTokens) > h.config.MaxTokensPerQuorum {
+			return &paramError{models.ErrCodeTooManyTokens, fmt.Sprintf("Too many supported tokens: %d (maximum %d)", len(req.SupportedTokens), h.config.MaxTokensPerQuorum)}
+		}
+		return nil
+	}},
+	{"region", func(h *QuorumHandler, req *models.QuorumRegistrationRequest) *paramError {
+		req.Region = normalizeRegion(req.Region)
+		if !isValidRegion(req.Region) {
+			return &paramError{models.ErrCodeInvalidRegion, "Invalid region. Use up to 64 lowercase letters, digits, '-' or '_'"}
+		}
+		return nil
+	}},
+	{"namespace", func(h *QuorumHandler, req *models.QuorumRegistrationRequest) *paramError {
+		req.Namespace = normalizeNamespace(req.Namespace)
+		if !isValidNamespace(req.Namespace) {
+			return &paramError{models.ErrCodeInvalidNamespace, "Invalid namespace. Use up to 64 lowercase letters, digits, '-' or '_'"}
+		}
+		return nil
+	}},
+	{"tier", func(h *QuorumHandler, req *models.QuorumRegistrationRequest) *paramError {
+		req.Tier = normalizeTier(req.Tier)
+		if req.Tier != "" && !h.hasTier(req.Tier) {
+			return &paramError{models.ErrCodeInvalidTier, fmt.Sprintf("Unknown tier %q", req.Tier)}
+		}
+		return nil
+	}},
+	{"metadata", func(h *QuorumHandler, req *models.QuorumRegistrationRequest) *paramError {
+		if err := validateMetadata(req.Metadata); err != nil {
+			return &paramError{models.ErrCodeInvalidMetadata, "Invalid metadata: " + err.Error()}
+		}
+		return nil
+	}},
+	{"capabilities", func(h *QuorumHandler, req *models.QuorumRegistrationRequest) *paramError {
+		capabilities, err := normalizeCapabilities(req.Capabilities)
+		if err != nil {
+			return &paramError{models.ErrCodeInvalidCapability, "Invalid capabilities: " + err.Error()}
+		}
+		req.Capabilities = capabilities
+		return nil
+	}},
+}
+
+// validateRegistration checks a registration request, normalizing supported tokens,
+// region, namespace, tier and capabilities in place. It returns the first failing check.
+func (h *QuorumHandler) validateRegistration(req *models.QuorumRegistrationRequest) *paramError {
+	for _, rc := range registrationChecks {
+		if paramErr := rc.check(h, req); paramErr != nil {
+			return paramErr
+		}
+	}
+	return nil
+}
+
+// ValidateRegistration handles POST /api/quorum/validate
+// Runs every registration check against the payload, plus the tombstone policy, and
+// reports each result without registering anything
+func (h *QuorumHandler) ValidateRegistration(c *gin.Context) {
 	var req models.QuorumRegistrationRequest
+	report := models.RegistrationValidation{Status: true, Valid: true}
+
+	if err := h.bindJSON(c, &req); err != nil {
+		report.Valid = false
+		report.Message = "Registration would be rejected"
+		report.Checks = []models.ValidationCheck{{
+			Check:   "request_format",
+			Code:    models.ErrCodeInvalidRequest,
+			Message: "Invalid request format: " + err.Error(),
+		}}
+		respond(c, http.StatusOK, report)
+		return
+	}
+
+	report.Checks = append(report.Checks, models.ValidationCheck{Check: "request_format", Passed: true})
+	for _, rc := range registrationChecks {
+		result := models.ValidationCheck{Check: rc.name, Passed: true}
+		if paramErr := rc.check(h, &req); paramErr != nil {
+			result = models.ValidationCheck{Check: rc.name, Code: paramErr.Code, Message: paramErr.Message}
+			report.Valid = false
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	policy := models.ValidationCheck{Check: "tombstone", Passed: true}
+	if err := h.checkRegistrationPolicy(&req); err != nil {
+		policy = models.ValidationCheck{Check: "tombstone", Code: models.ErrCodeInternal, Message: err.Error()}
+		if errors.Is(err, storage.ErrTombstoned) {
+			policy.Code = models.ErrCodeTombstoned
+		}
+		report.Valid = false
+	}
+	report.Checks = append(report.Checks, policy)
+
+	report.Message = "Registration would be accepted"
+	if !report.Valid {
+		report.Message = "Registration would be rejected"
+	} else {
+		report.Normalized = &req
+	}
+	respond(c, http.StatusOK, report)
+}
+
+// respondRegisterError reports a failed registration; tombstoned DIDs get 403
+func respondRegisterError(c *gin.Context, err error, action string) {
+	if errors.Is(err, storage.ErrTombstoned) {
+		respondError(c, http.StatusForbidden, models.ErrCodeTombstoned, models.BasicResponse{
+			Status:  false,
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, storage.ErrQuorumDisabled) {
+		respondQuorumError(c, err, action)
+		return
+	}
+	respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+		Status:  false,
+		Message: action + ": " + err.Error(),
+	})
+}
+
+// GetAvailableQuorums handles GET /api/quorum/available
+func (h *QuorumHandler) GetAvailableQuorums(c *gin.Context) {
+	req, paramErr := h.parseSelection(c)
+	if paramErr != nil {
+		respondError(c, http.StatusBadRequest, paramErr.Code, models.QuorumListResponse{
+			Status:  false,
+			Message: paramErr.Message,
+			Quorums: nil,
+		})
+		return
+	}
+
+	// ?verbose=true adds each quorum's DID, balance, assignment count and DID type
+	verbose := false
+	if verboseStr := c.Query("verbose"); verboseStr != "" {
+		var err error
+		if verbose, err = strconv.ParseBool(verboseStr); err != nil {
+			respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.QuorumListResponse{
+				Status:  false,
+				Message: "Invalid verbose. Use true or false",
+				Quorums: nil,
+			})
+			return
+		}
+	}
+	if verbose {
+		if _, ok := h.extended(c); !ok {
+			return
+		}
+	}
+	if paramErr := h.checkSelectionFilters(req); paramErr != nil {
+		respondError(c, http.StatusNotImplemented, paramErr.Code, models.QuorumListResponse{
+			Status:  false,
+			Message: paramErr.Message,
+			Quorums: nil,
+		})
+		return
+	}
+
+	// Clients that keep failing with the same parameters wait out their backoff
+	// without reaching the store
+	client, params := c.ClientIP(), selectionKey(req)
+	if wait, backedOff := h.backoff.retryAfter(client, params); backedOff {
+		metrics.AvailableRequests.WithLabelValues("throttled").Inc()
+		setRetryAfter(c, wait)
+		respondError(c, http.StatusTooManyRequests, models.ErrCodeSelectionBackoff, models.QuorumListResponse{
+			Status:         false,
+			Message:        fmt.Sprintf("Repeated selections with these parameters failed; retry after %s", wait.Round(time.Second)),
+			Quorums:        nil,
+			RequestedCount: req.Count,
+		})
+		return
+	}
+
+	// Calculate required balance (transaction amount divided by number of quorums)
+	requiredBalance := h.store.RequiredBalance(req.TransactionAmount, req.Count)
+
+	// Get available quorums with balance validation and token filtering
+	selection, err := h.selectQuorums(req, requiredBalance)
+	switch {
+	case err == nil:
+		metrics.AvailableRequests.WithLabelValues("success").Inc()
+		h.backoff.succeed(client, params)
+	case errors.Is(err, storage.ErrAssignmentNotRecorded):
+		metrics.AvailableRequests.WithLabelValues("error").Inc()
+	default:
+		// Pools below their minimum size count as insufficient too
+		metrics.AvailableRequests.WithLabelValues("insufficient").Inc()
+		if wait := h.backoff.fail(client, params); wait > 0 {
+			setRetryAfter(c, wait)
+		}
+	}
+
+	var poolErr *storage.PoolNotReadyError
+	if errors.As(err, &poolErr) {
+		respondError(c, http.StatusServiceUnavailable, models.ErrCodePoolNotReady, models.QuorumListResponse{
+			Status:         false,
+			Message:        "Selection unavailable: " + poolErr.Error(),
+			Quorums:        nil,
+			RequestedCount: req.Count,
+		})
+		return
+	}
+	if errors.Is(err, storage.ErrAssignmentNotRecorded) {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.QuorumListResponse{
+			Status:         false,
+			Message:        "Selection failed: " + err.Error(),
+			Quorums:        nil,
+			RequestedCount: req.Count,
+		})
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusServiceUnavailable, models.ErrCodeInsufficientQuorums, models.QuorumListResponse{
+			Status:         false,
+			Message:        fmt.Sprintf("Not enough quorums with required balance (%.4f RBT): %v", h.effectiveRequiredBalance(requiredBalance), err),
+			Quorums:        nil,
+			RequestedCount: req.Count,
+		})
+		return
+	}
+
+	quorums := selection.Quorums
+	message := selectionMessage(req, selection, requiredBalance)
+
+	if verbose {
+		respond(c, http.StatusOK, models.VerboseQuorumListResponse{
+			Status:         true,
+			Message:        message,
+			Quorums:        selection.Selected,
+			TransactionID:  selection.TransactionID,
+			RequestedCount: req.Count,
+			DeliveredCount: len(quorums),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, models.QuorumListResponse{
+		Status:         true,
+		Message:        message,
+		Quorums:        quorums,
+		TransactionID:  selection.TransactionID,
+		RequestedCount: req.Count,
+		DeliveredCount: len(quorums),
+	})
+}
+
+// checkSelectionFilters refuses selection parameters that stores without the full API
+// can't apply, rather than selecting without them and reporting them as met
+func (h *QuorumHandler) checkSelectionFilters(req models.QuorumListRequest) *paramError {
+	if h.ext != nil {
+		return nil
+	}
+
+	var unsupported []string
+	if req.MinRegions > 1 {
+		unsupported = append(unsupported, "min_regions")
+	}
+	if req.Region != "" {
+		unsupported = append(unsupported, "region")
+	}
+	if len(req.Requires) > 0 {
+		unsupported = append(unsupported, "requires")
+	}
+	if req.Namespace != models.DefaultNamespace {
+		unsupported = append(unsupported, "namespace")
+	}
+	if req.MinTier != "" {
+		unsupported = append(unsupported, "min_tier")
+	}
+	if req.MinSuccessfulTxns > 0 {
+		unsupported = append(unsupported, "min_successful_txns")
+	}
+	if req.TransactionID != "" {
+		unsupported = append(unsupported, "transaction_id")
+	}
+	if len(unsupported) == 0 {
+		return nil
+	}
+	return &paramError{models.ErrCodeNotImplemented,
+		fmt.Sprintf("%s not supported by this store; use a database store", strings.Join(unsupported, ", "))}
+}
+
+// selectQuorums selects and assigns quorums for req. Stores without the full API select
+// with Store.GetAvailableQuorums, which applies count, last_char_tid, ft_name, did_type
+// and the balance check; checkSelectionFilters refuses the other filters for them.
+func (h *QuorumHandler) selectQuorums(req models.QuorumListRequest, requiredBalance float64) (*storage.SelectionResult, error) {
+	if h.ext != nil {
+		return h.ext.SelectQuorums(req)
+	}
+
+	quorums, err := h.store.GetAvailableQuorums(req.Count, req.LastCharTID, req.TransactionAmount, req.FTName, req.DIDType)
+	if err != nil {
+		return nil, err
+	}
+	return &storage.SelectionResult{
+		RequiredBalance:          requiredBalance,
+		EffectiveRequiredBalance: requiredBalance,
+		SafetyMultiplier:         1,
+		Quorums:                  quorums,
+	}, nil
+}
+
+// effectiveRequiredBalance is the balance the store's check demands for a share of
+// requiredBalance, which only stores with minimum quorum balances raise
+func (h *QuorumHandler) effectiveRequiredBalance(requiredBalance float64) float64 {
+	if h.ext == nil {
+		return requiredBalance
+	}
+	return h.ext.EffectiveRequiredBalance(requiredBalance)
+}
+
+// selectionMessage describes a successful selection and the filters it satisfied. Every
+// filter in req was applied: checkSelectionFilters refuses those the store can't apply.
+func selectionMessage(req models.QuorumListRequest, selection *storage.SelectionResult, requiredBalance float64) string {
+	// Create appropriate message based on token type
+	message := fmt.Sprintf("Found %d quorums with minimum balance of %.4f RBT", len(selection.Quorums), selection.EffectiveRequiredBalance)
+	if selection.EffectiveRequiredBalance != requiredBalance {
+		message += fmt.Sprintf(" (minimum quorum balance; share is %.4f RBT)", requiredBalance)
+	}
+	if req.FTName == "TRI" {
+		message = fmt.Sprintf("Found %d TRI-compatible quorums (consistent set)", len(selection.Quorums))
+	} else if req.FTName != "" {
+		message = fmt.Sprintf("Found %d quorums supporting %s token", len(selection.Quorums), req.FTName)
+	}
+	if req.Namespace != models.DefaultNamespace {
+		message += fmt.Sprintf(" in namespace %s", req.Namespace)
+	}
+	if req.MinRegions > 1 {
+		message += fmt.Sprintf(" across at least %d regions", req.MinRegions)
+	}
+	if req.Region != "" {
+		local := 0
+		for _, q := range selection.Selected {
+			if q.Region == req.Region {
+				local++
+			}
+		}
+		message += fmt.Sprintf(" (%d in preferred region %s)", local, req.Region)
+	}
+	if len(req.Requires) > 0 {
+		message += fmt.Sprintf(" with capabilities [%s]", strings.Join(req.Requires, ", "))
+	}
+	if req.MinTier != "" {
+		message += fmt.Sprintf(" at tier %s or higher", req.MinTier)
+	}
+	if req.MinSuccessfulTxns > 0 {
+		message += fmt.Sprintf(" with at least %d successful transactions", req.MinSuccessfulTxns)
+	}
+	if req.DIDType != nil {
+		message += fmt.Sprintf(" of DID type %d", *req.DIDType)
+	}
+	if selection.SafetyMultiplier != 1 {
+		message += fmt.Sprintf(" (balance safety multiplier %.2fx active)", selection.SafetyMultiplier)
+	}
+	return message
+}
+
+// CountAvailableQuorums handles GET /api/quorum/available/count
+// Counts the quorums /available would choose from for the same parameters, without
+// assigning them or recording a transaction, for dry-run transaction planning
+func (h *QuorumHandler) CountAvailableQuorums(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	req, paramErr := h.parseSelection(c)
+	if paramErr != nil {
+		respondError(c, http.StatusBadRequest, paramErr.Code, models.BasicResponse{
+			Status:  false,
+			Message: paramErr.Message,
+		})
+		return
+	}
+
+	eligible, requiredBalance, err := ext.CountEligible(req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to count eligible quorums: " + err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, models.EligibleCountResponse{
+		Eligible:        eligible,
+		RequiredBalance: requiredBalance,
+	})
+}
+
+// DiagnoseAvailability handles GET /api/quorum/diagnose-availability
+// Runs the selection filters read-only and reports how many quorums pass each stage
+func (h *QuorumHandler) DiagnoseAvailability(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	req, paramErr := h.parseSelection(c)
+	if paramErr != nil {
+		respondError(c, http.StatusBadRequest, paramErr.Code, models.BasicResponse{
+			Status:  false,
+			Message: paramErr.Message,
+		})
+		return
+	}
+
+	diagnosis, err := ext.DiagnoseAvailability(req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to diagnose availability: " + err.Error(),
+		})
+		return
+	}
+
+	message := fmt.Sprintf("%d of %d quorums eligible, %d needed", diagnosis.EligibleCount, diagnosis.TotalQuorums, diagnosis.RequestedCount)
+
+	respond(c, http.StatusOK, gin.H{
+		"status":    true,
+		"message":   message,
+		"diagnosis": diagnosis,
+	})
+}
+
+// ExplainSelection handles GET /api/quorum/available/explain
+// Dry-runs a selection and reports, per quorum, which gates it passed and the decision
+// selection would reach. Nothing is assigned and no history is recorded.
+func (h *QuorumHandler) ExplainSelection(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	req, paramErr := h.parseSelection(c)
+	if paramErr != nil {
+		respondError(c, http.StatusBadRequest, paramErr.Code, models.BasicResponse{
+			Status:  false,
+			Message: paramErr.Message,
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 1000 {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "limit must be an integer between 1 and 1000",
+		})
+		return
+	}
+
+	explanation, err := ext.ExplainSelection(req, limit)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to explain selection: " + err.Error(),
+		})
+		return
+	}
+
+	message := fmt.Sprintf("%d of %d quorums eligible, %d would be selected", explanation.EligibleCount, explanation.TotalQuorums, explanation.SelectedCount)
+	if explanation.Error != "" {
+		message = "Selection would fail: " + explanation.Error
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"status":      true,
+		"message":     message,
+		"explanation": explanation,
+	})
+}
+
+// GetNearEligible handles GET /api/quorum/near-eligible
+// Lists quorums that would become eligible with a top-up of at most tolerance
+func (h *QuorumHandler) GetNearEligible(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	req, paramErr := h.parseSelection(c)
+	if paramErr != nil {
+		respondError(c, http.StatusBadRequest, paramErr.Code, models.BasicResponse{
+			Status:  false,
+			Message: paramErr.Message,
+		})
+		return
+	}
+
+	tolerance, err := strconv.ParseFloat(c.Query("tolerance"), 64)
+	if err != nil || tolerance <= 0 {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidAmount, models.BasicResponse{
+			Status:  false,
+			Message: "tolerance must be provided and greater than 0",
+		})
+		return
+	}
+
+	quorums, err := ext.GetNearEligible(req, tolerance)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to find near-eligible quorums: " + err.Error(),
+		})
+		return
+	}
+
+	// Per-quorum required_balance and shortfall include the safety multiplier
+	respond(c, http.StatusOK, gin.H{
+		"status":            true,
+		"message":           fmt.Sprintf("Found %d quorums within %.4f RBT of eligibility", len(quorums), tolerance),
+		"required_balance":  h.store.RequiredBalance(req.TransactionAmount, req.Count),
+		"safety_multiplier": ext.BalanceSafetyMultiplier(),
+		"quorums":           quorums,
+	})
+}
+
+// GetEligibilityForecast handles GET /api/quorum/eligibility-forecast
+// Estimates how many more eligible quorums the request needs and, from the recent pool
+// growth rate, roughly when they will be there
+func (h *QuorumHandler) GetEligibilityForecast(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	req, paramErr := h.parseSelection(c)
+	if paramErr != nil {
+		respondError(c, http.StatusBadRequest, paramErr.Code, models.BasicResponse{
+			Status:  false,
+			Message: paramErr.Message,
+		})
+		return
+	}
+
+	window := storage.DefaultForecastWindow
+	if hoursStr := c.Query("window_hours"); hoursStr != "" {
+		hours, err := strconv.Atoi(hoursStr)
+		if err != nil || hours <= 0 || time.Duration(hours)*time.Hour > storage.MaxForecastWindow {
+			respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+				Status:  false,
+				Message: fmt.Sprintf("window_hours must be between 1 and %d", int(storage.MaxForecastWindow.Hours())),
+			})
+			return
+		}
+		window = time.Duration(hours) * time.Hour
+	}
+
+	forecast, err := ext.ForecastEligibility(req, window)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to forecast eligibility: " + err.Error(),
+		})
+		return
+	}
+
+	var message string
+	switch {
+	case forecast.Needed == 0:
+		message = fmt.Sprintf("Satisfiable now: %d quorums eligible, %d needed", forecast.EligibleCount, forecast.RequestedCount)
+	case forecast.ETASeconds != nil:
+		message = fmt.Sprintf("%d more eligible quorums needed, expected in about %s",
+			forecast.Needed, (time.Duration(*forecast.ETASeconds) * time.Second).Round(time.Minute))
+	default:
+		message = fmt.Sprintf("%d more eligible quorums needed; the pool has not grown over the last %.0fh",
+			forecast.Needed, forecast.WindowHours)
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"status":   true,
+		"message":  message,
+		"forecast": forecast,
+	})
+}
+
+// GetAttestation handles GET /api/quorum/attestation
+// Returns the current selection-eligible set signed with the server key, for audit records
+func (h *QuorumHandler) GetAttestation(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	if h.config.Signer == nil {
+		respondError(c, http.StatusServiceUnavailable, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Attestation signing is not configured",
+		})
+		return
+	}
+
+	req, paramErr := h.parseSelection(c)
+	if paramErr != nil {
+		respondError(c, http.StatusBadRequest, paramErr.Code, models.BasicResponse{
+			Status:  false,
+			Message: paramErr.Message,
+		})
+		return
+	}
+
+	quorums, requiredBalance, err := ext.EligibleQuorums(req)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to load eligible quorums: " + err.Error(),
+		})
+		return
+	}
+
+	signed, err := h.config.Signer.Sign(models.EligibilityAttestation{
+		TransactionAmount: req.TransactionAmount,
+		Count:             req.Count,
+		RequiredBalance:   requiredBalance,
+		Quorums:           quorums,
+		Timestamp:         time.Now().UTC(),
+	})
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to sign attestation: " + err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"status":      true,
+		"message":     fmt.Sprintf("%d eligible quorums attested", len(quorums)),
+		"attestation": signed,
+	})
+}
+
+// ReplaceQuorum handles POST /api/quorum/replace
+// Returns one qualified quorum to stand in for a failed member of an assigned set
+func (h *QuorumHandler) ReplaceQuorum(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	var req models.QuorumReplaceRequest
+
+	if err := h.bindJSON(c, &req); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if !isValidDID(req.FailedDID) {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidDID, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid DID format for failed_did",
+		})
+		return
+	}
+
+	replacement, err := ext.SelectReplacement(&req)
+	if errors.Is(err, storage.ErrTransactionNotFound) {
+		respondError(c, http.StatusNotFound, models.ErrCodeTransactionNotFound, models.BasicResponse{
+			Status:  false,
+			Message: "Transaction not found: " + req.TransactionID,
+		})
+		return
+	}
+	if errors.Is(err, storage.ErrTokenMismatch) {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidToken, models.BasicResponse{
+			Status:  false,
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusServiceUnavailable, models.ErrCodeInsufficientQuorums, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to select replacement quorum: " + err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"status":         true,
+		"message":        fmt.Sprintf("Replacement quorum selected for %s", req.FailedDID),
+		"transaction_id": req.TransactionID,
+		"quorum":         replacement,
+	})
+}
+
+// ReserveQuorums handles POST /api/quorum/reserve
+// Holds an assigned set for a transaction so concurrent selections cannot hand the same
+// quorums to another transaction
+func (h *QuorumHandler) ReserveQuorums(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	var req models.QuorumReserveRequest
+
+	if err := h.bindJSON(c, &req); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if len(req.DIDs) == 0 {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "dids must list at least one quorum",
+		})
+		return
+	}
+	seen := make(map[string]bool, len(req.DIDs))
+	for _, did := range req.DIDs {
+		if !isValidDID(did) {
+			respondError(c, http.StatusBadRequest, models.ErrCodeInvalidDID, models.BasicResponse{
+				Status:  false,
+				Message: "Invalid DID format: " + did,
+			})
+			return
+		}
+		if seen[did] {
+			respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+				Status:  false,
+				Message: "Duplicate DID in dids: " + did,
+			})
+			return
+		}
+		seen[did] = true
+	}
+
+	if req.TransactionAmount <= 0 {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidAmount, models.BasicResponse{
+			Status:  false,
+			Message: "Transaction amount must be greater than 0",
+		})
+		return
+	}
+
+	ttl := storage.DefaultReservationTTL
+	if req.TTLSeconds != 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+	if ttl <= 0 || ttl > storage.MaxReservationTTL {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: fmt.Sprintf("ttl_seconds must be between 1 and %d", int(storage.MaxReservationTTL.Seconds())),
+		})
+		return
+	}
+
+	expiresAt, err := ext.ReserveQuorums(req.TransactionID, req.DIDs, req.TransactionAmount, ttl)
+	switch {
+	case errors.Is(err, storage.ErrQuorumNotFound):
+		respondError(c, http.StatusNotFound, models.ErrCodeQuorumNotFound, models.BasicResponse{
+			Status:  false,
+			Message: err.Error(),
+		})
+		return
+	case errors.Is(err, storage.ErrQuorumReserved):
+		respondError(c, http.StatusConflict, models.ErrCodeQuorumReserved, models.BasicResponse{
+			Status:  false,
+			Message: err.Error(),
+		})
+		return
+	case errors.Is(err, storage.ErrQuorumDisabled), errors.Is(err, storage.ErrQuorumUnavailable),
+		errors.Is(err, storage.ErrInsufficientBalance):
+		respondError(c, http.StatusConflict, models.ErrCodeInsufficientQuorums, models.BasicResponse{
+			Status:  false,
+			Message: err.Error(),
+		})
+		return
+	case err != nil:
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to reserve quorums: " + err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"status":         true,
+		"message":        fmt.Sprintf("Reserved %d quorums for %s", len(req.DIDs), req.TransactionID),
+		"transaction_id": req.TransactionID,
+		"expires_at":     expiresAt.UTC(),
+	})
+}
+
+// ReleaseQuorums handles POST /api/quorum/release
+// Frees the quorums a transaction reserved once it completes or fails
+func (h *QuorumHandler) ReleaseQuorums(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	var req models.QuorumReleaseRequest
+
+	if err := h.bindJSON(c, &req); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	released, err := ext.ReleaseQuorums(req.TransactionID)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to release quorums: " + err.Error(),
+		})
+		return
+	}
+	if released == 0 {
+		respondError(c, http.StatusNotFound, models.ErrCodeTransactionNotFound, models.BasicResponse{
+			Status:  false,
+			Message: "No reservation held for transaction: " + req.TransactionID,
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"status":         true,
+		"message":        fmt.Sprintf("Released %d quorums", released),
+		"transaction_id": req.TransactionID,
+		"released":       released,
+	})
+}
+
+// CompleteTransaction handles POST /api/quorum/transaction/complete
+// Records whether a transaction succeeded or failed, optionally taking back the
+// assignments of a failed one so its quorums are not penalized in load balancing
+func (h *QuorumHandler) CompleteTransaction(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	var req models.TransactionCompleteRequest
+
+	if err := h.bindJSON(c, &req); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	completion, err := ext.CompleteTransaction(req.TransactionID, req.Status,
+		req.Status == storage.TransactionFailed && req.DecrementAssignments)
+	switch {
+	case errors.Is(err, storage.ErrTransactionNotFound):
+		respondError(c, http.StatusNotFound, models.ErrCodeTransactionNotFound, models.BasicResponse{
+			Status:  false,
+			Message: "Transaction not found: " + req.TransactionID,
+		})
+		return
+	case errors.Is(err, storage.ErrTransactionCompleted):
+		respondError(c, http.StatusConflict, models.ErrCodeTransactionComplete, models.BasicResponse{
+			Status:  false,
+			Message: "Transaction already completed: " + req.TransactionID,
+		})
+		return
+	case err != nil:
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to complete transaction: " + err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"status":     true,
+		"message":    fmt.Sprintf("Transaction %s recorded as %s", req.TransactionID, req.Status),
+		"completion": completion,
+	})
+}
+
+// ReserveScheduled handles POST /api/quorum/reserve-scheduled
+// Holds a qualified quorum set out of other selections for a future time window
+func (h *QuorumHandler) ReserveScheduled(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	var req models.ScheduledReservationRequest
+
+	if err := h.bindJSON(c, &req); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if req.TransactionAmount <= 0 {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidAmount, models.BasicResponse{
+			Status:  false,
+			Message: "Transaction amount must be greater than 0",
+		})
+		return
+	}
+	if req.Count < 0 || req.Count > storage.MaxQuorumCount {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: fmt.Sprintf("count must be between 0 and %d", storage.MaxQuorumCount),
+		})
+		return
+	}
+
+	switch {
+	case !req.End.After(req.Start):
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "end must be after start",
+		})
+		return
+	case !req.End.After(time.Now()):
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "end must be in the future",
+		})
+		return
+	case req.End.Sub(req.Start) > storage.MaxReservationWindow:
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: fmt.Sprintf("Reservation window must not exceed %s", storage.MaxReservationWindow),
+		})
+		return
+	}
+
+	req.Namespace = normalizeNamespace(req.Namespace)
+	if !isValidNamespace(req.Namespace) {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidNamespace, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid namespace. Use up to 64 lowercase letters, digits, '-' or '_'",
+		})
+		return
+	}
+
+	reservation, err := ext.ReserveScheduled(req)
+	if errors.Is(err, storage.ErrQuorumReserved) {
+		// Another reservation booked the same quorums concurrently; a retry picks others
+		respondError(c, http.StatusConflict, models.ErrCodeQuorumReserved, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to reserve quorums: " + err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusServiceUnavailable, models.ErrCodeInsufficientQuorums, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to reserve quorums: " + err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"status":      true,
+		"message":     fmt.Sprintf("Reserved %d quorums until %s", len(reservation.Quorums), reservation.End.Format(time.RFC3339)),
+		"reservation": reservation,
+	})
+}
+
+// UpdateQuorumBalance handles PUT /api/quorum/balance
+func (h *QuorumHandler) UpdateQuorumBalance(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		DID     string  `json:"did" binding:"required"`
+		Balance float64 `json:"balance" binding:"required"`
+	}
+
+	body, err := h.bindSignedJSON(c, &req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if !isValidDID(req.DID) {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidDID, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid DID format",
+		})
+		return
+	}
+
+	if req.Balance < 0 {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidBalance, models.BasicResponse{
+			Status:  false,
+			Message: "Balance cannot be negative",
+		})
+		return
+	}
+
+	if !h.authenticateQuorum(c, req.DID, body, "") {
+		return
+	}
+
+	if err := ext.UpdateQuorumBalance(req.DID, req.Balance); err != nil {
+		respondQuorumError(c, err, "Failed to update balance")
+		return
+	}
+	h.backoff.poolGrew()
+
+	respond(c, http.StatusOK, models.BasicResponse{
+		Status:  true,
+		Message: fmt.Sprintf("Balance updated to %.4f RBT", req.Balance),
+	})
+}
+
+// ConfirmAvailability handles POST /api/quorum/confirm-availability
+func (h *QuorumHandler) ConfirmAvailability(c *gin.Context) {
+	var req models.ConfirmAvailabilityRequest
+
+	if err := h.bindJSON(c, &req); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if !isValidDID(req.DID) {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidDID, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid DID format",
+		})
+		return
+	}
+
+	if err := h.store.ConfirmAvailability(req.DID); err != nil {
+		respondQuorumError(c, err, "Failed to confirm availability")
+		return
+	}
+	h.backoff.poolGrew()
+
+	respond(c, http.StatusOK, models.BasicResponse{
+		Status:  true,
+		Message: "Availability confirmed",
+	})
+}
+
+// SetAvailability handles PUT /api/admin/availability
+// Lets an operator pull a misbehaving quorum out of rotation without unregistering it,
+// and put it back; heartbeats and re-registration don't re-enable a disabled quorum. It
+// is refused unless an admin token or API keys are configured, since it would otherwise
+// let anyone disable any quorum.
+func (h *QuorumHandler) SetAvailability(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok || !h.requireAdminCredential(c, "Setting quorum availability") {
+		return
+	}
+
+	var req models.SetAvailabilityRequest
+
+	if err := h.bindJSON(c, &req); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if !isValidDID(req.DID) {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidDID, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid DID format",
+		})
+		return
+	}
+
+	if err := ext.SetAvailability(req.DID, *req.Available); err != nil {
+		respondQuorumError(c, err, "Failed to set availability")
+		return
+	}
+
+	message := "Quorum disabled until an operator re-enables it"
+	if *req.Available {
+		h.backoff.poolGrew()
+		message = "Quorum re-enabled"
+		log.Printf("✅ Quorum %s re-enabled by operator\n", req.DID)
+	} else {
+		log.Printf("⛔ Quorum %s disabled by operator\n", req.DID)
+	}
+
+	respond(c, http.StatusOK, models.BasicResponse{
+		Status:  true,
+		Message: message,
+	})
+}
+
+// FreezeStaleness handles POST /api/admin/freeze-staleness
+// Keeps quorums selectable through a planned maintenance window by suspending
+// heartbeat staleness checks until the given deadline. The freeze applies to every
+// instance sharing the database, so it is refused unless an admin token or API keys are
+// configured.
+func (h *QuorumHandler) FreezeStaleness(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok || !h.requireAdminCredential(c, "Freezing staleness") {
+		return
+	}
+
+	var req models.FreezeStalenessRequest
+
+	if err := h.bindJSON(c, &req); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	if !req.Until.After(now) {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "until must be in the future",
+		})
+		return
+	}
+	if req.Until.Sub(now) > storage.MaxStalenessFreeze {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: fmt.Sprintf("until must be within %s", storage.MaxStalenessFreeze),
+		})
+		return
+	}
+
+	if err := ext.FreezeStaleness(req.Until); err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to freeze staleness checks: " + err.Error(),
+		})
+		return
+	}
+	log.Printf("❄️  Staleness checks FROZEN until %s - stale quorums stay selectable\n", req.Until.Format(time.RFC3339))
+
+	respond(c, http.StatusOK, models.BasicResponse{
+		Status:  true,
+		Message: "Staleness checks frozen until " + req.Until.Format(time.RFC3339),
+	})
+}
+
+// SetBalanceSafety handles POST /api/admin/balance-safety
+// Makes selection demand extra collateral headroom during suspected attacks or
+// volatility; a multiplier of 1 restores the normal balance check. The multiplier applies
+// to every instance sharing the database, so it is refused unless an admin token or API
+// keys are configured.
+func (h *QuorumHandler) SetBalanceSafety(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok || !h.requireAdminCredential(c, "Setting the balance safety multiplier") {
+		return
+	}
+
+	var req models.BalanceSafetyRequest
+
+	if err := h.bindJSON(c, &req); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if err := storage.ValidateBalanceSafetyMultiplier(req.Multiplier); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := ext.SetBalanceSafetyMultiplier(req.Multiplier); err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to set the balance safety multiplier: " + err.Error(),
+		})
+		return
+	}
+
+	message := fmt.Sprintf("Balance safety multiplier set to %.2fx", req.Multiplier)
+	if req.Multiplier == 1 {
+		message = "Balance safety multiplier cleared"
+	}
+	log.Printf("🛡️  %s\n", message)
+
+	respond(c, http.StatusOK, models.BasicResponse{
+		Status:  true,
+		Message: message,
+	})
+}
+
+// ResetAssignments handles POST /api/admin/reset-assignments
+// Resets the assignment count of the quorum in {"did": ...}, or of every quorum when the
+// body is empty, so operators can rebalance load after adding capacity. It is refused
+// unless an admin token or API keys are configured, since it would otherwise let anyone
+// skew selection.
+func (h *QuorumHandler) ResetAssignments(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok || !h.requireAdminCredential(c, "Resetting assignment counts") {
+		return
+	}
+
+	var req models.ResetAssignmentsRequest
+	if c.Request.ContentLength != 0 {
+		if err := h.bindJSON(c, &req); err != nil {
+			respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+				Status:  false,
+				Message: "Invalid request format: " + err.Error(),
+			})
+			return
+		}
+	}
+	if req.DID != "" && !isValidDID(req.DID) {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidDID, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid DID format",
+		})
+		return
+	}
+
+	reset, err := ext.ResetAssignments(req.DID)
+	if err != nil {
+		respondQuorumError(c, err, "Failed to reset assignment counts")
+		return
+	}
+
+	message := fmt.Sprintf("Reset the assignment counts of %d quorums", reset)
+	if req.DID != "" {
+		message = "Reset the assignment count of " + req.DID
+	}
+	log.Printf("⚖️  %s\n", message)
+
+	respond(c, http.StatusOK, gin.H{
+		"status":  true,
+		"message": message,
+		"reset":   reset,
+	})
+}
+
+// UnregisterQuorum handles DELETE /api/quorum/unregister/:did
+// The quorum is soft-deleted so its history stays attributable; ?hard=true removes the
+// row outright
+func (h *QuorumHandler) UnregisterQuorum(c *gin.Context) {
+	did := c.Param("did")
+
+	if !isValidDID(did) {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidDID, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid DID format",
+		})
+		return
+	}
+
+	hard := false
+	if hardStr := c.Query("hard"); hardStr != "" {
+		var err error
+		if hard, err = strconv.ParseBool(hardStr); err != nil {
+			respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+				Status:  false,
+				Message: "Invalid hard. Use true or false",
+			})
+			return
+		}
+	}
+
+	// A request body carries a signature: deregister with a tombstone
+	if c.Request.ContentLength != 0 {
+		h.unregisterSigned(c, did, hard)
+		return
+	}
+
+	// Without one, only quorums that couldn't have signed may be removed, hard or not
+	key, err := h.publicKey(did)
+	if err != nil && !errors.Is(err, storage.ErrQuorumNotFound) {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to look up public key: " + err.Error(),
+		})
+		return
+	}
+	if key != "" || h.config.RequireSignatures {
+		respondError(c, http.StatusUnauthorized, models.ErrCodeInvalidSignature, models.BasicResponse{
+			Status:  false,
+			Message: "Signed requests are required: send a signed tombstone in the request body",
+		})
+		return
+	}
+
+	if err := h.store.UnregisterQuorum(did, hard); err != nil {
+		respondQuorumError(c, err, "Failed to unregister quorum")
+		return
+	}
+	h.config.Webhooks.Publish(webhooks.EventQuorumUnregistered, webhooks.QuorumEvent{DID: did})
+
+	respond(c, http.StatusOK, models.BasicResponse{
+		Status:  true,
+		Message: "Quorum unregistered successfully",
+	})
+}
+
+// unregisterSigned handles the signed variant of DELETE /api/quorum/unregister/:did
+func (h *QuorumHandler) unregisterSigned(c *gin.Context, did string, hard bool) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	var req models.UnregisterSignature
+
+	if err := h.bindJSON(c, &req); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if err := ext.UnregisterQuorumSigned(did, &req, hard); err != nil {
+		if errors.Is(err, storage.ErrInvalidSignature) {
+			respondError(c, http.StatusForbidden, models.ErrCodeInvalidSignature, models.BasicResponse{
+				Status:  false,
+				Message: err.Error(),
+			})
+			return
+		}
+		respondQuorumError(c, err, "Failed to unregister quorum")
+		return
+	}
+	h.config.Webhooks.Publish(webhooks.EventQuorumUnregistered, webhooks.QuorumEvent{DID: did})
+
+	respond(c, http.StatusOK, models.BasicResponse{
+		Status:  true,
+		Message: "Quorum unregistered with signed tombstone",
+	})
+}
+
+// GetHealth handles GET /api/quorum/health
+func (h *QuorumHandler) GetHealth(c *gin.Context) {
+	namespace, ok := h.namespaceFilter(c)
+	if !ok {
+		return
+	}
+
+	health := h.store.GetHealthStatus()
+	if namespace != "" {
+		ext, ok := h.extended(c)
+		if !ok {
+			return
+		}
+		health = ext.GetNamespaceHealthStatus(namespace)
+	}
+	health.Maintenance = h.config.Maintenance.Enabled()
+	respond(c, http.StatusOK, health)
+}
+
+// namespaceFilter reads the optional ?namespace= scope for listing endpoints, where an
+// absent namespace means every namespace. It responds with 400 and returns false when
+// the namespace is malformed.
+func (h *QuorumHandler) namespaceFilter(c *gin.Context) (string, bool) {
+	namespace := c.Query("namespace")
+	if namespace == "" {
+		return "", true
+	}
+
+	namespace = normalizeNamespace(namespace)
+	if !isValidNamespace(namespace) {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidNamespace, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid namespace. Use up to 64 lowercase letters, digits, '-' or '_'",
+		})
+		return "", false
+	}
+	return namespace, true
+}
+
+// Heartbeat handles POST /api/quorum/heartbeat
+// The body may optionally carry an updated balance, region and metadata, which are
+// applied atomically with the ping so nodes don't need to re-register to refresh them
+func (h *QuorumHandler) Heartbeat(c *gin.Context) {
+	var req models.HeartbeatRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.BasicResponse{
+	body, err := h.bindSignedJSON(c, &req)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
 			Status:  false,
 			Message: "Invalid request format: " + err.Error(),
 		})
 		return
 	}
 
-	// Validate DID format (matching RubixGo validation)
 	if !isValidDID(req.DID) {
-		c.JSON(http.StatusBadRequest, models.BasicResponse{
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidDID, models.BasicResponse{
 			Status:  false,
-			Message: "Invalid DID format. DID must start with 'bafybmi' and be 59 characters long",
+			Message: "Invalid DID format",
+		})
+		return
+	}
+
+	if !h.authenticateQuorum(c, req.DID, body, "") {
+		return
+	}
+
+	if !req.HasDetails() {
+		if err := h.store.UpdateHeartbeat(req.DID); err != nil {
+			respondQuorumError(c, err, "Failed to update heartbeat")
+			return
+		}
+
+		respond(c, http.StatusOK, models.BasicResponse{
+			Status:  true,
+			Message: "Heartbeat updated",
 		})
 		return
 	}
 
-	// Validate DID type (0-4, where 4 is lite mode in RubixGo)
-	if req.DIDType < 0 || req.DIDType > 4 {
-		c.JSON(http.StatusBadRequest, models.BasicResponse{
+	// Updating details along with the ping needs the full API
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	if req.Balance != nil && *req.Balance < 0 {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidBalance, models.BasicResponse{
 			Status:  false,
-			Message: "Invalid DID type. Must be between 0 and 4",
+			Message: "Balance cannot be negative",
 		})
 		return
 	}
 
-	// Register the quorum
-	if err := h.store.RegisterQuorum(&req); err != nil {
-		c.JSON(http.StatusInternalServerError, models.BasicResponse{
+	if req.Region != nil {
+		region := normalizeRegion(*req.Region)
+		if !isValidRegion(region) {
+			respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRegion, models.BasicResponse{
+				Status:  false,
+				Message: "Invalid region. Use up to 64 lowercase letters, digits, '-' or '_'",
+			})
+			return
+		}
+		req.Region = &region
+	}
+
+	if err := validateMetadata(req.Metadata); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidMetadata, models.BasicResponse{
 			Status:  false,
-			Message: "Failed to register quorum: " + err.Error(),
+			Message: "Invalid metadata: " + err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.BasicResponse{
+	if err := ext.UpdateHeartbeatDetails(&req); err != nil {
+		respondQuorumError(c, err, "Failed to update heartbeat")
+		return
+	}
+
+	respond(c, http.StatusOK, models.BasicResponse{
 		Status:  true,
-		Message: "Quorum registered successfully",
+		Message: "Heartbeat and quorum details updated",
 	})
 }
 
-// ConfirmAvailability handles POST /api/quorum/confirm-availability
-func (h *QuorumHandler) ConfirmAvailability(c *gin.Context) {
-	var req models.ConfirmAvailabilityRequest
+// maxBatchHeartbeats bounds the DIDs accepted by one heartbeat-batch request
+const maxBatchHeartbeats = 1000
+
+// HeartbeatBatch handles POST /api/quorum/heartbeat-batch
+// Pings every listed quorum with one update, so a node hosting many DIDs doesn't send a
+// burst of heartbeats. Requests are unsigned, so quorums with a public_key are skipped
+// and reported as keyed; they must keep using /heartbeat.
+func (h *QuorumHandler) HeartbeatBatch(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	var req models.HeartbeatBatchRequest
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.BasicResponse{
+	if err := h.bindJSON(c, &req); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
 			Status:  false,
 			Message: "Invalid request format: " + err.Error(),
 		})
 		return
 	}
+	if len(req.DIDs) == 0 || len(req.DIDs) > maxBatchHeartbeats {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: fmt.Sprintf("Batch must contain between 1 and %d DIDs, got %d", maxBatchHeartbeats, len(req.DIDs)),
+		})
+		return
+	}
+	if h.config.RequireSignatures {
+		respondError(c, http.StatusUnauthorized, models.ErrCodeInvalidSignature, models.BasicResponse{
+			Status:  false,
+			Message: "Signed requests are required: send heartbeats through /api/quorum/heartbeat",
+		})
+		return
+	}
 
-	// Validate DID format
-	if !isValidDID(req.DID) {
-		c.JSON(http.StatusBadRequest, models.BasicResponse{
+	response := models.HeartbeatBatchResponse{
+		Status:  true,
+		Found:   []string{},
+		Missing: []string{},
+	}
+
+	// Duplicates are pinged once; malformed DIDs are reported without a lookup
+	seen := make(map[string]bool, len(req.DIDs))
+	dids := make([]string, 0, len(req.DIDs))
+	for _, did := range req.DIDs {
+		if seen[did] {
+			continue
+		}
+		seen[did] = true
+		if isValidDID(did) {
+			dids = append(dids, did)
+		} else {
+			response.Invalid = append(response.Invalid, did)
+		}
+	}
+
+	if len(dids) > 0 {
+		keyed, err := ext.KeyedDIDs(dids)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+				Status:  false,
+				Message: "Failed to look up public keys: " + err.Error(),
+			})
+			return
+		}
+		response.Keyed = keyed
+		dids = excludeStrings(dids, keyed)
+	}
+
+	if len(dids) > 0 {
+		updated, missing, err := ext.UpdateHeartbeatBatch(dids)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+				Status:  false,
+				Message: "Failed to update heartbeats: " + err.Error(),
+			})
+			return
+		}
+		response.Updated = updated
+		response.Found = excludeStrings(dids, missing)
+		if missing != nil {
+			response.Missing = missing
+		}
+	}
+
+	response.Message = fmt.Sprintf("Updated %d of %d heartbeats, %d DIDs not registered", response.Updated, len(req.DIDs), len(response.Missing))
+	respond(c, http.StatusOK, response)
+}
+
+// GetQuorumInfo handles GET /api/quorum/info/:did
+func (h *QuorumHandler) GetQuorumInfo(c *gin.Context) {
+	did := c.Param("did")
+
+	if !isValidDID(did) {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidDID, models.BasicResponse{
 			Status:  false,
 			Message: "Invalid DID format",
 		})
 		return
 	}
 
-	// Confirm availability
-	if err := h.store.ConfirmAvailability(req.DID); err != nil {
-		c.JSON(http.StatusNotFound, models.BasicResponse{
+	format, paramErr := parseBalanceFormat(c)
+	if paramErr != nil {
+		respondError(c, http.StatusBadRequest, paramErr.Code, models.BasicResponse{
 			Status:  false,
-			Message: "Quorum not found: " + err.Error(),
+			Message: paramErr.Message,
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.BasicResponse{
-		Status:  true,
-		Message: "Availability confirmed",
+	quorum, err := h.store.GetQuorumByDID(did)
+	if err != nil {
+		respondQuorumError(c, err, "Failed to get quorum")
+		return
+	}
+	format.apply(quorum)
+
+	respond(c, http.StatusOK, gin.H{
+		"status":       true,
+		"quorum":       quorum,
+		"balance_unit": format.unit,
 	})
 }
 
-// GetAvailableQuorums handles GET /api/quorum/available
-func (h *QuorumHandler) GetAvailableQuorums(c *gin.Context) {
-	var req models.QuorumListRequest
-
-	// Parse query parameters
-	if countStr := c.Query("count"); countStr != "" {
-		var count int
-		if _, err := fmt.Sscanf(countStr, "%d", &count); err == nil {
-			req.Count = count
-		}
+// GetQuorumStats handles GET /api/quorum/stats/:did
+// Returns how often a quorum has been assigned and the balance share it has backed
+func (h *QuorumHandler) GetQuorumStats(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
 	}
 
-	if req.Count <= 0 {
-		req.Count = 7 // Default to 7 quorums
+	did := c.Param("did")
+
+	if !isValidDID(did) {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidDID, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid DID format",
+		})
+		return
 	}
 
-	// Parse transaction amount
-	if amountStr := c.Query("transaction_amount"); amountStr != "" {
-		if amount, err := strconv.ParseFloat(amountStr, 64); err == nil {
-			req.TransactionAmount = amount
-		}
+	stats, err := ext.GetQuorumStats(did)
+	if err != nil {
+		respondQuorumError(c, err, "Failed to get quorum stats")
+		return
 	}
 
-	// Default transaction amount if not provided
-	if req.TransactionAmount <= 0 {
-		req.TransactionAmount = float64(req.Count) // Default: 1 RBT per quorum
+	respond(c, http.StatusOK, gin.H{
+		"status": true,
+		"stats": models.QuorumUsage{
+			DID:                    did,
+			TotalTransactions:      stats.TotalTransactions,
+			SuccessfulTransactions: stats.SuccessfulTransactions,
+			TotalAmount:            stats.TotalAmount,
+			LastActive:             stats.LastActive,
+		},
+	})
+}
+
+// GetBalanceHistory handles GET /api/quorum/balance-history/:did
+// Returns a quorum's recorded balance changes, newest first, for auditing selections
+func (h *QuorumHandler) GetBalanceHistory(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
 	}
 
-	req.LastCharTID = c.Query("last_char_tid")
-	req.FTName = c.Query("ft_name") // Get token type parameter
+	did := c.Param("did")
 
-	// Parse type parameter
-	if typeStr := c.Query("type"); typeStr != "" {
-		var qtype int
-		if _, err := fmt.Sscanf(typeStr, "%d", &qtype); err == nil {
-			req.Type = qtype
-		}
+	if !isValidDID(did) {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidDID, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid DID format",
+		})
+		return
 	}
 
-	if req.Type == 0 {
-		req.Type = 2 // Default to type 2 (private subnet)
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "limit must be a positive integer",
+		})
+		return
 	}
 
-	// Get available quorums with load balancing and token filtering
-	quorums, err := h.store.GetAvailableQuorums(req.Count, req.LastCharTID, req.TransactionAmount, req.FTName)
+	history, err := ext.GetBalanceHistory(did, limit)
 	if err != nil {
-		c.JSON(http.StatusServiceUnavailable, models.QuorumListResponse{
+		respondQuorumError(c, err, "Failed to get balance history")
+		return
+	}
+
+	changes := make([]models.BalanceChange, 0, len(history))
+	for _, entry := range history {
+		changes = append(changes, models.BalanceChange{
+			OldBalance: entry.OldBalance,
+			NewBalance: entry.NewBalance,
+			Reason:     entry.ChangeReason,
+			Timestamp:  entry.Timestamp,
+		})
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"status":  true,
+		"did":     did,
+		"history": changes,
+		"count":   len(changes),
+	})
+}
+
+// CompareQuorums handles GET /api/quorum/compare?a=<did>&b=<did>
+// Returns both quorums' key metrics in parallel, e.g. to decide which to decommission
+func (h *QuorumHandler) CompareQuorums(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	a, b := c.Query("a"), c.Query("b")
+
+	for _, did := range []string{a, b} {
+		if !isValidDID(did) {
+			respondError(c, http.StatusBadRequest, models.ErrCodeInvalidDID, models.BasicResponse{
+				Status:  false,
+				Message: "Both a and b must be valid DIDs",
+			})
+			return
+		}
+	}
+	if a == b {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
 			Status:  false,
-			Message: "Not enough available quorums: " + err.Error(),
-			Quorums: nil,
+			Message: "a and b must be different quorums",
 		})
 		return
 	}
 
-	// Create appropriate message based on token type
-	message := fmt.Sprintf("Found %d quorums", len(quorums))
-	if req.FTName == "TRI" {
-		message = fmt.Sprintf("Found %d TRI-compatible quorums (consistent set)", len(quorums))
-	} else if req.FTName != "" {
-		message = fmt.Sprintf("Found %d quorums supporting %s token", len(quorums), req.FTName)
+	comparison, err := ext.CompareQuorums(a, b)
+	if errors.Is(err, storage.ErrQuorumNotFound) {
+		respondError(c, http.StatusNotFound, models.ErrCodeQuorumNotFound, models.BasicResponse{
+			Status:  false,
+			Message: err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to compare quorums: " + err.Error(),
+		})
+		return
 	}
 
-	c.JSON(http.StatusOK, models.QuorumListResponse{
-		Status:  true,
-		Message: message,
-		Quorums: quorums,
+	respond(c, http.StatusOK, gin.H{
+		"status":     true,
+		"comparison": comparison,
 	})
 }
 
-// UnregisterQuorum handles DELETE /api/quorum/unregister/:did
-func (h *QuorumHandler) UnregisterQuorum(c *gin.Context) {
+// GetQuorumTimeline handles GET /api/quorum/:did/timeline
+// Returns the quorum's balance, availability and transaction events in chronological order
+func (h *QuorumHandler) GetQuorumTimeline(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
 	did := c.Param("did")
 
 	if !isValidDID(did) {
-		c.JSON(http.StatusBadRequest, models.BasicResponse{
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidDID, models.BasicResponse{
 			Status:  false,
 			Message: "Invalid DID format",
 		})
 		return
 	}
 
-	if err := h.store.UnregisterQuorum(did); err != nil {
-		c.JSON(http.StatusNotFound, models.BasicResponse{
+	var since time.Time
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		parsed, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+				Status:  false,
+				Message: "since must be an RFC3339 timestamp",
+			})
+			return
+		}
+		since = parsed
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
 			Status:  false,
-			Message: "Quorum not found: " + err.Error(),
+			Message: "limit must be a positive integer",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.BasicResponse{
-		Status:  true,
-		Message: "Quorum unregistered successfully",
+	events, err := ext.GetQuorumTimeline(did, since, limit)
+	if err != nil {
+		respondQuorumError(c, err, "Failed to get quorum timeline")
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"status":   true,
+		"did":      did,
+		"timeline": events,
+		"count":    len(events),
 	})
 }
 
-// GetHealth handles GET /api/quorum/health
-func (h *QuorumHandler) GetHealth(c *gin.Context) {
-	health := h.store.GetHealthStatus()
-	c.JSON(http.StatusOK, health)
+// GetPeerDIDs handles GET /api/debug/peers
+// Groups registered DIDs by peer ID to diagnose peers hosting several DIDs
+func (h *QuorumHandler) GetPeerDIDs(c *gin.Context) {
+	peers, err := h.store.GetPeerDIDs()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, gin.H{
+			"status":  false,
+			"message": "Failed to load peer index: " + err.Error(),
+		})
+		return
+	}
+
+	sharedPeers := 0
+	for _, dids := range peers {
+		if len(dids) > 1 {
+			sharedPeers++
+		}
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"status":       true,
+		"peers":        peers,
+		"count":        len(peers),
+		"shared_peers": sharedPeers,
+	})
 }
 
-// Heartbeat handles POST /api/quorum/heartbeat
-func (h *QuorumHandler) Heartbeat(c *gin.Context) {
-	var req struct {
-		DID string `json:"did" binding:"required"`
+// GetPeerIndex handles GET /api/debug/peers on stores keeping a PeerID -> DID index
+// Dumps the store's index
+func (h *QuorumHandler) GetPeerIndex(c *gin.Context) {
+	indexer, ok := h.store.(storage.PeerIndexer)
+	if !ok {
+		respondError(c, http.StatusNotImplemented, models.ErrCodeNotImplemented, models.BasicResponse{
+			Status:  false,
+			Message: "This store keeps no PeerID -> DID index",
+		})
+		return
 	}
+	index := indexer.PeerIndex()
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.BasicResponse{
+	respond(c, http.StatusOK, gin.H{
+		"status": true,
+		"peers":  index,
+		"count":  len(index),
+	})
+}
+
+// GetAllQuorums handles GET /api/quorum/all
+func (h *QuorumHandler) GetAllQuorums(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	namespace, ok := h.namespaceFilter(c)
+	if !ok {
+		return
+	}
+
+	format, paramErr := parseBalanceFormat(c)
+	if paramErr != nil {
+		respondError(c, http.StatusBadRequest, paramErr.Code, gin.H{
+			"status":  false,
+			"message": paramErr.Message,
+		})
+		return
+	}
+
+	quorums, err := ext.GetNamespaceQuorums(namespace)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, gin.H{
+			"status":  false,
+			"message": "Failed to fetch quorums: " + err.Error(),
+		})
+		return
+	}
+	for i := range quorums {
+		format.apply(&quorums[i])
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"status":       true,
+		"quorums":      quorums,
+		"count":        len(quorums),
+		"balance_unit": format.unit,
+	})
+}
+
+// ListQuorums handles GET /api/quorum/list
+// Pages through registered quorums with optional filters and sorting
+func (h *QuorumHandler) ListQuorums(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	namespace, ok := h.namespaceFilter(c)
+	if !ok {
+		return
+	}
+
+	filter, paramErr := parseQuorumListFilter(c)
+	if paramErr != nil {
+		respondError(c, http.StatusBadRequest, paramErr.Code, models.BasicResponse{
 			Status:  false,
-			Message: "Invalid request format: " + err.Error(),
+			Message: paramErr.Message,
 		})
 		return
 	}
+	filter.Namespace = namespace
 
-	if !isValidDID(req.DID) {
-		c.JSON(http.StatusBadRequest, models.BasicResponse{
+	format, paramErr := parseBalanceFormat(c)
+	if paramErr != nil {
+		respondError(c, http.StatusBadRequest, paramErr.Code, models.BasicResponse{
 			Status:  false,
-			Message: "Invalid DID format",
+			Message: paramErr.Message,
 		})
 		return
 	}
 
-	if err := h.store.UpdateHeartbeat(req.DID); err != nil {
-		c.JSON(http.StatusNotFound, models.BasicResponse{
+	page, err := ext.ListQuorums(filter)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, gin.H{
+			"status":  false,
+			"message": "Failed to list quorums: " + err.Error(),
+		})
+		return
+	}
+	for i := range page.Quorums {
+		format.apply(&page.Quorums[i])
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"status":       true,
+		"quorums":      page.Quorums,
+		"count":        len(page.Quorums),
+		"total":        page.Total,
+		"limit":        page.Limit,
+		"offset":       page.Offset,
+		"balance_unit": format.unit,
+	})
+}
+
+// parseQuorumListFilter reads limit (default 100, 0 for all), offset, available,
+// did_type, min_balance, token, sort and order
+func parseQuorumListFilter(c *gin.Context) (storage.QuorumListFilter, *paramError) {
+	var filter storage.QuorumListFilter
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 0 {
+		return filter, &paramError{models.ErrCodeInvalidRequest, "limit must be a non-negative integer"}
+	}
+	filter.Limit = limit
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		return filter, &paramError{models.ErrCodeInvalidRequest, "offset must be a non-negative integer"}
+	}
+	filter.Offset = offset
+
+	if value := c.Query("available"); value != "" {
+		available, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, &paramError{models.ErrCodeInvalidRequest, "available must be true or false"}
+		}
+		filter.Available = &available
+	}
+
+	didType, paramErr := parseDIDTypeFilter(c)
+	if paramErr != nil {
+		return filter, paramErr
+	}
+	filter.DIDType = didType
+
+	if value := c.Query("min_balance"); value != "" {
+		minBalance, err := strconv.ParseFloat(value, 64)
+		if err != nil || minBalance < 0 {
+			return filter, &paramError{models.ErrCodeInvalidBalance, "min_balance must be a non-negative number"}
+		}
+		filter.MinBalance = minBalance
+	}
+
+	filter.Token = c.Query("token")
+
+	filter.SortBy = c.Query("sort")
+	if filter.SortBy != "" && !storage.QuorumSortColumns[filter.SortBy] {
+		return filter, &paramError{models.ErrCodeInvalidRequest, "sort must be one of registration_time, balance, assignment_count or last_ping"}
+	}
+
+	switch c.DefaultQuery("order", "desc") {
+	case "asc":
+		filter.Ascending = true
+	case "desc":
+	default:
+		return filter, &paramError{models.ErrCodeInvalidRequest, "order must be asc or desc"}
+	}
+
+	return filter, nil
+}
+
+// GetRecentFailures handles GET /api/quorum/recent-failures
+// Lists this instance's most recent failed selections, newest first
+func (h *QuorumHandler) GetRecentFailures(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
 			Status:  false,
-			Message: "Quorum not found: " + err.Error(),
+			Message: "Invalid limit. Must be a positive integer",
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.BasicResponse{
-		Status:  true,
-		Message: "Heartbeat updated",
+	failures := ext.RecentSelectionFailures(limit)
+
+	respond(c, http.StatusOK, gin.H{
+		"status":   true,
+		"count":    len(failures),
+		"failures": failures,
 	})
 }
 
-// GetQuorumInfo handles GET /api/quorum/info/:did
-func (h *QuorumHandler) GetQuorumInfo(c *gin.Context) {
-	did := c.Param("did")
+// GetTransactionHistory handles GET /api/quorum/transactions
+func (h *QuorumHandler) GetTransactionHistory(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok {
+		return
+	}
 
-	if !isValidDID(did) {
-		c.JSON(http.StatusBadRequest, models.BasicResponse{
+	filter, paramErr := parseHistoryFilter(c)
+	if paramErr != nil {
+		respondError(c, http.StatusBadRequest, paramErr.Code, models.BasicResponse{
 			Status:  false,
-			Message: "Invalid DID format",
+			Message: paramErr.Message,
 		})
 		return
 	}
 
-	quorum, err := h.store.GetQuorumByDID(did)
+	page, err := ext.GetTransactionHistory(filter)
 	if err != nil {
-		c.JSON(http.StatusNotFound, models.BasicResponse{
-			Status:  false,
-			Message: "Quorum not found: " + err.Error(),
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, gin.H{
+			"status":  false,
+			"message": "Failed to get transaction history: " + err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status": true,
-		"quorum": quorum,
+	records := make([]models.TransactionRecord, 0, len(page.History))
+	for _, entry := range page.History {
+		records = append(records, models.TransactionRecord{
+			TransactionID:     entry.TransactionID,
+			TransactionAmount: entry.TransactionAmount,
+			QuorumDIDs:        entry.AssignedDIDs(),
+			RequiredBalance:   entry.RequiredBalance,
+			Namespace:         entry.Namespace,
+			Timestamp:         entry.Timestamp.UTC(),
+			Status:            entry.Status,
+			CompletedAt:       entry.CompletedAt,
+		})
+	}
+
+	respond(c, http.StatusOK, transactionHistoryResponse{
+		Status:  true,
+		Total:   page.Total,
+		Limit:   page.Limit,
+		Offset:  page.Offset,
+		History: records,
 	})
 }
 
+// transactionHistoryResponse is the GET /api/quorum/transactions body: one page of
+// records and the total matching the filters
+type transactionHistoryResponse struct {
+	Status  bool                       `json:"status"`
+	Total   int64                      `json:"total"`
+	Limit   int                        `json:"limit"`
+	Offset  int                        `json:"offset"`
+	History []models.TransactionRecord `json:"history"`
+}
+
+// parseHistoryFilter reads limit (default 100, 0 for all), offset, from/to (RFC3339)
+// and min_amount/max_amount
+func parseHistoryFilter(c *gin.Context) (storage.TransactionHistoryFilter, *paramError) {
+	var filter storage.TransactionHistoryFilter
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 0 {
+		return filter, &paramError{models.ErrCodeInvalidRequest, "limit must be a non-negative integer"}
+	}
+	filter.Limit = limit
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		return filter, &paramError{models.ErrCodeInvalidRequest, "offset must be a non-negative integer"}
+	}
+	filter.Offset = offset
+
+	for _, bound := range []struct {
+		name   string
+		target *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}} {
+		if value := c.Query(bound.name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return filter, &paramError{models.ErrCodeInvalidRequest, bound.name + " must be an RFC3339 timestamp"}
+			}
+			*bound.target = parsed
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.To.After(filter.From) {
+		return filter, &paramError{models.ErrCodeInvalidRequest, "to must be after from"}
+	}
+
+	for _, bound := range []struct {
+		name   string
+		target *float64
+	}{{"min_amount", &filter.MinAmount}, {"max_amount", &filter.MaxAmount}} {
+		if value := c.Query(bound.name); value != "" {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil || parsed < 0 {
+				return filter, &paramError{models.ErrCodeInvalidAmount, bound.name + " must be a non-negative number"}
+			}
+			*bound.target = parsed
+		}
+	}
+	if filter.MaxAmount > 0 && filter.MinAmount > filter.MaxAmount {
+		return filter, &paramError{models.ErrCodeInvalidAmount, "min_amount must not exceed max_amount"}
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,1097 @@
+package handlers
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/attestation"
+	"github.com/gklps/advisory-node/models"
+	"github.com/gklps/advisory-node/storage"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// testDID returns a valid DID ending in suffix
+func testDID(suffix string) string {
+	return "bafybmi" + strings.Repeat("a", 52-len(suffix)) + suffix
+}
+
+// newTestStore opens a migrated SQLite store in a temporary directory
+func newTestStore(t *testing.T, config storage.DBConfig) *storage.DBStore {
+	t.Helper()
+	config.Type = "sqlite"
+	config.Database = filepath.Join(t.TempDir(), "advisory.db")
+	config.AutoMigrate = true
+
+	store, err := storage.NewDBStore(config)
+	if err != nil {
+		t.Fatalf("NewDBStore: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+// newTestHandler creates a handler on store accepting the short peer IDs tests use
+func newTestHandler(store storage.Store, config HandlerConfig) *QuorumHandler {
+	config.RelaxedPeerIDs = true
+	return NewQuorumHandlerWithConfig(store, config)
+}
+
+// registerTestQuorum registers a DID type 4 quorum holding balance, with its peer ID
+// derived from suffix
+func registerTestQuorum(t *testing.T, store storage.Store, suffix string, balance float64, modify ...func(*models.QuorumRegistrationRequest)) string {
+	t.Helper()
+	req := &models.QuorumRegistrationRequest{
+		DID:     testDID(suffix),
+		PeerID:  "peer" + suffix,
+		Balance: balance,
+		DIDType: 4,
+	}
+	for _, m := range modify {
+		m(req)
+	}
+	if err := store.RegisterQuorum(req); err != nil {
+		t.Fatalf("RegisterQuorum(%s): %v", suffix, err)
+	}
+	return req.DID
+}
+
+// serve sends a request to router and returns the recorded response. headers are
+// name, value pairs.
+func serve(router http.Handler, method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// decodeBody unmarshals a JSON response body into a generic map
+func decodeBody(t *testing.T, w *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding %q: %v", w.Body.String(), err)
+	}
+	return body
+}
+
+func TestHeartbeatRegionUpdateReachesSelection(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	h := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.POST("/api/quorum/heartbeat", h.Heartbeat)
+	router.GET("/api/quorum/available", h.GetAvailableQuorums)
+
+	inUSEast := func(req *models.QuorumRegistrationRequest) { req.Region = "us-east" }
+	registerTestQuorum(t, store, "1", 100, inUSEast)
+	registerTestQuorum(t, store, "2", 100, inUSEast)
+	moved := registerTestQuorum(t, store, "3", 100, inUSEast)
+
+	w := serve(router, http.MethodPost, "/api/quorum/heartbeat", `{"did":"`+moved+`","region":"EU-West"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("heartbeat status = %d, body %s", w.Code, w.Body)
+	}
+
+	w = serve(router, http.MethodGet, "/api/quorum/available?count=1&transaction_amount=1&region=eu-west&verbose=true", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("available status = %d, body %s", w.Code, w.Body)
+	}
+	var resp models.VerboseQuorumListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Quorums) != 1 || resp.Quorums[0].DID != moved || resp.Quorums[0].Region != "eu-west" {
+		t.Errorf("selection preferring eu-west = %+v, want %s in eu-west", resp.Quorums, moved)
+	}
+
+	quorum, err := store.GetQuorumByDID(moved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quorum.Region != "eu-west" {
+		t.Errorf("stored region = %q, want eu-west", quorum.Region)
+	}
+}
+
+func TestRegisterRejectsTooManyTokens(t *testing.T) {
+	custom := make([]string, 20)
+	for i := range custom {
+		custom[i] = fmt.Sprintf("T%d", i)
+	}
+	register := func(t *testing.T, config HandlerConfig, tokens []string) *httptest.ResponseRecorder {
+		t.Helper()
+		store := newTestStore(t, storage.DBConfig{})
+		config.CustomTokens = custom
+		router := gin.New()
+		router.POST("/api/quorum/register", newTestHandler(store, config).RegisterQuorum)
+
+		body, _ := json.Marshal(models.QuorumRegistrationRequest{
+			DID: testDID("1"), PeerID: "peer1", DIDType: 4, Balance: 10, SupportedTokens: tokens,
+		})
+		w := serve(router, http.MethodPost, "/api/quorum/register", string(body), "Accept", mediaTypeV2)
+
+		_, err := store.GetQuorumByDID(testDID("1"))
+		if registered := err == nil; registered != (w.Code == http.StatusOK) {
+			t.Errorf("status %d but registered = %v", w.Code, registered)
+		}
+		return w
+	}
+
+	tests := []struct {
+		name   string
+		limit  int
+		tokens []string
+		want   int
+	}{
+		{"at the limit", 3, custom[:3], http.StatusOK},
+		{"over the limit", 3, custom[:4], http.StatusBadRequest},
+		{"duplicates count once", 3, []string{"rbt", "RBT", "t1", "T1 ", "TRI"}, http.StatusOK},
+		{"over the default limit", 0, custom[:DefaultMaxTokensPerQuorum+1], http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := register(t, HandlerConfig{MaxTokensPerQuorum: tt.limit}, tt.tokens)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d; body %s", w.Code, tt.want, w.Body)
+			}
+			if tt.want == http.StatusBadRequest {
+				if code := decodeBody(t, w)["error_code"]; code != models.ErrCodeTooManyTokens {
+					t.Errorf("error_code = %v, want %s", code, models.ErrCodeTooManyTokens)
+				}
+			}
+		})
+	}
+}
+
+func TestMalformedAndUnknownDIDs(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	// The availability switch is refused outright without an admin credential
+	h := newTestHandler(store, HandlerConfig{AdminToken: "admin-secret"})
+	router := gin.New()
+	router.GET("/api/quorum/info/:did", h.GetQuorumInfo)
+	router.GET("/api/quorum/stats/:did", h.GetQuorumStats)
+	router.GET("/api/quorum/balance-history/:did", h.GetBalanceHistory)
+	router.GET("/api/quorum/:did/timeline", h.GetQuorumTimeline)
+	router.GET("/api/quorum/compare", h.CompareQuorums)
+	router.DELETE("/api/quorum/unregister/:did", h.UnregisterQuorum)
+	router.PUT("/api/quorum/balance", h.UpdateQuorumBalance)
+	router.POST("/api/quorum/heartbeat", h.Heartbeat)
+	router.POST("/api/quorum/confirm-availability", h.ConfirmAvailability)
+	router.PUT("/api/admin/availability", h.SetAvailability)
+	router.POST("/api/admin/reset-assignments", h.ResetAssignments)
+
+	known := registerTestQuorum(t, store, "1", 100)
+	endpoints := []struct {
+		method string
+		path   func(did string) string
+		body   func(did string) string
+	}{
+		{http.MethodGet, func(did string) string { return "/api/quorum/info/" + did }, nil},
+		{http.MethodGet, func(did string) string { return "/api/quorum/stats/" + did }, nil},
+		{http.MethodGet, func(did string) string { return "/api/quorum/balance-history/" + did }, nil},
+		{http.MethodGet, func(did string) string { return "/api/quorum/" + did + "/timeline" }, nil},
+		{http.MethodGet, func(did string) string { return "/api/quorum/compare?a=" + known + "&b=" + did }, nil},
+		{http.MethodDelete, func(did string) string { return "/api/quorum/unregister/" + did }, nil},
+		{http.MethodPut, func(string) string { return "/api/quorum/balance" }, func(did string) string {
+			return `{"did":"` + did + `","balance":50}`
+		}},
+		{http.MethodPost, func(string) string { return "/api/quorum/heartbeat" }, func(did string) string {
+			return `{"did":"` + did + `"}`
+		}},
+		{http.MethodPost, func(string) string { return "/api/quorum/confirm-availability" }, func(did string) string {
+			return `{"did":"` + did + `"}`
+		}},
+		{http.MethodPut, func(string) string { return "/api/admin/availability" }, func(did string) string {
+			return `{"did":"` + did + `","available":false}`
+		}},
+		{http.MethodPost, func(string) string { return "/api/admin/reset-assignments" }, func(did string) string {
+			return `{"did":"` + did + `"}`
+		}},
+	}
+
+	cases := []struct {
+		name     string
+		did      string
+		wantCode int
+		wantErr  string
+	}{
+		{"malformed", "bafybmi-not-a-did", http.StatusBadRequest, models.ErrCodeInvalidDID},
+		{"unknown", testDID("9"), http.StatusNotFound, models.ErrCodeQuorumNotFound},
+	}
+	for _, endpoint := range endpoints {
+		for _, tc := range cases {
+			path := endpoint.path(tc.did)
+			t.Run(endpoint.method+" "+endpoint.path("DID")+" "+tc.name, func(t *testing.T) {
+				body := ""
+				if endpoint.body != nil {
+					body = endpoint.body(tc.did)
+				}
+				w := serve(router, endpoint.method, path, body, "Accept", mediaTypeV2)
+				if w.Code != tc.wantCode {
+					t.Fatalf("status = %d, want %d; body %s", w.Code, tc.wantCode, w.Body)
+				}
+				if code := decodeBody(t, w)["error_code"]; code != tc.wantErr {
+					t.Errorf("error_code = %v, want %s", code, tc.wantErr)
+				}
+			})
+		}
+	}
+
+	// None of the requests touched the registered quorum
+	quorum, err := store.GetQuorumByDID(known)
+	if err != nil || !quorum.Available || quorum.Balance != 100 {
+		t.Errorf("registered quorum = %+v (%v), want it unchanged", quorum, err)
+	}
+}
+
+func TestSetAvailabilityRequiresAdmin(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	did := registerTestQuorum(t, store, "1", 100)
+	body := `{"did":"` + did + `","available":false}`
+
+	// Without an admin token or API keys, RequireAdmin is open, so the switch refuses itself
+	open := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.PUT("/api/admin/availability", open.RequireAdmin, open.SetAvailability)
+	if w := serve(router, http.MethodPut, "/api/admin/availability", body); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("without admin credentials: status = %d, want 503; body %s", w.Code, w.Body)
+	}
+
+	guarded := newTestHandler(store, HandlerConfig{AdminToken: "admin-secret"})
+	router = gin.New()
+	router.PUT("/api/admin/availability", guarded.RequireAdmin, guarded.SetAvailability)
+	if w := serve(router, http.MethodPut, "/api/admin/availability", body); w.Code != http.StatusUnauthorized {
+		t.Errorf("without a token: status = %d, want 401; body %s", w.Code, w.Body)
+	}
+	if quorum, err := store.GetQuorumByDID(did); err != nil || !quorum.Available {
+		t.Fatalf("quorum = %+v (%v), want it still available", quorum, err)
+	}
+
+	w := serve(router, http.MethodPut, "/api/admin/availability", body, "Authorization", "Bearer admin-secret")
+	if w.Code != http.StatusOK {
+		t.Fatalf("with the token: status = %d, body %s", w.Code, w.Body)
+	}
+	if quorum, err := store.GetQuorumByDID(did); err != nil || quorum.Available {
+		t.Errorf("quorum = %+v (%v), want it disabled", quorum, err)
+	}
+}
+
+func TestAttestationSignatureValidates(t *testing.T) {
+	signer, err := attestation.GenerateSigner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The floor raises the 10 share, and the attestation reports the raised balance
+	store := newTestStore(t, storage.DBConfig{MinQuorumBalance: 15})
+	h := newTestHandler(store, HandlerConfig{Signer: signer})
+	router := gin.New()
+	router.GET("/api/quorum/attestation", h.GetAttestation)
+
+	registerTestQuorum(t, store, "1", 100)
+	registerTestQuorum(t, store, "2", 100)
+	registerTestQuorum(t, store, "3", 1)
+
+	w := serve(router, http.MethodGet, "/api/quorum/attestation?count=2&transaction_amount=20", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body)
+	}
+	var resp struct {
+		Attestation models.SignedAttestation `json:"attestation"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	signed := resp.Attestation
+
+	// Verify against the key the server was configured with, not the one it reports
+	publicKey, _ := base64.StdEncoding.DecodeString(signer.PublicKey())
+	payload, _ := base64.StdEncoding.DecodeString(signed.Payload)
+	signature, _ := base64.StdEncoding.DecodeString(signed.Signature)
+	if !ed25519.Verify(publicKey, payload, signature) {
+		t.Fatal("attestation signature doesn't verify against the server's public key")
+	}
+
+	var attested models.EligibilityAttestation
+	if err := json.Unmarshal(payload, &attested); err != nil {
+		t.Fatal(err)
+	}
+	if len(attested.Quorums) != 2 || attested.RequiredBalance != 15 || attested.Timestamp.IsZero() {
+		t.Errorf("signed payload = %+v, want the two quorums holding the 15 floor", attested)
+	}
+	if len(signed.Attestation.Quorums) != len(attested.Quorums) || signed.Attestation.Quorums[0] != attested.Quorums[0] {
+		t.Errorf("attestation %+v differs from the signed payload %+v", signed.Attestation, attested)
+	}
+}
+
+func TestTombstonedDIDRejectsUnsignedRegistration(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	h := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.POST("/api/quorum/register", h.RegisterQuorum)
+	router.DELETE("/api/quorum/unregister/:did", h.UnregisterQuorum)
+
+	did := registerTestQuorum(t, store, "1", 100)
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedAt := time.Now().Unix()
+	body, _ := json.Marshal(models.UnregisterSignature{
+		PublicKey: base64.StdEncoding.EncodeToString(public),
+		SignedAt:  signedAt,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(storage.SignedMessage("unregister", did, signedAt)))),
+	})
+	w := serve(router, http.MethodDelete, "/api/quorum/unregister/"+did, string(body))
+	if w.Code != http.StatusOK {
+		t.Fatalf("signed unregister status = %d, body %s", w.Code, w.Body)
+	}
+
+	w = serve(router, http.MethodPost, "/api/quorum/register",
+		`{"did":"`+did+`","peer_id":"peer1","did_type":4,"balance":100}`, "Accept", mediaTypeV2)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("unsigned re-registration status = %d, want 403; body %s", w.Code, w.Body)
+	}
+	if code := decodeBody(t, w)["error_code"]; code != models.ErrCodeTombstoned {
+		t.Errorf("error_code = %v, want %s", code, models.ErrCodeTombstoned)
+	}
+}
+
+func TestUnsignedUnregisterNeedsNoKey(t *testing.T) {
+	public, _, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withKey := func(req *models.QuorumRegistrationRequest) {
+		req.PublicKey = base64.StdEncoding.EncodeToString(public)
+	}
+
+	cases := []struct {
+		name     string
+		config   HandlerConfig
+		modify   []func(*models.QuorumRegistrationRequest)
+		query    string
+		wantCode int
+	}{
+		{"no key", HandlerConfig{}, nil, "", http.StatusOK},
+		{"public key", HandlerConfig{}, []func(*models.QuorumRegistrationRequest){withKey}, "", http.StatusUnauthorized},
+		{"public key, hard", HandlerConfig{}, []func(*models.QuorumRegistrationRequest){withKey}, "?hard=true", http.StatusUnauthorized},
+		{"signatures required", HandlerConfig{RequireSignatures: true}, nil, "", http.StatusUnauthorized},
+		{"signatures required, hard", HandlerConfig{RequireSignatures: true}, nil, "?hard=true", http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := newTestStore(t, storage.DBConfig{})
+			router := gin.New()
+			router.DELETE("/api/quorum/unregister/:did", newTestHandler(store, tc.config).UnregisterQuorum)
+
+			did := registerTestQuorum(t, store, "1", 100, tc.modify...)
+			w := serve(router, http.MethodDelete, "/api/quorum/unregister/"+did+tc.query, "", "Accept", mediaTypeV2)
+			if w.Code != tc.wantCode {
+				t.Fatalf("unsigned unregister status = %d, want %d; body %s", w.Code, tc.wantCode, w.Body)
+			}
+			if tc.wantCode == http.StatusOK {
+				return
+			}
+			if code := decodeBody(t, w)["error_code"]; code != models.ErrCodeInvalidSignature {
+				t.Errorf("error_code = %v, want %s", code, models.ErrCodeInvalidSignature)
+			}
+			if _, err := store.GetQuorumByDID(did); err != nil {
+				t.Errorf("quorum gone after a rejected unregister: %v", err)
+			}
+		})
+	}
+}
+
+func TestDebugPeersOnBothBackends(t *testing.T) {
+	onPeerA := func(req *models.QuorumRegistrationRequest) { req.PeerID = "peerA" }
+
+	memory := storage.NewMemoryStore()
+	first := registerTestQuorum(t, memory, "1", 100)
+	second := registerTestQuorum(t, memory, "2", 100)
+	router := gin.New()
+	router.GET("/api/debug/peers", newTestHandler(memory, HandlerConfig{}).GetPeerIndex)
+	w := serve(router, http.MethodGet, "/api/debug/peers", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("memory store status = %d, body %s", w.Code, w.Body)
+	}
+	var index struct {
+		Peers map[string]string `json:"peers"`
+		Count int               `json:"count"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &index); err != nil {
+		t.Fatal(err)
+	}
+	if index.Count != 2 || index.Peers["peer1"] != first || index.Peers["peer2"] != second {
+		t.Errorf("memory store peer index = %+v", index)
+	}
+
+	db := newTestStore(t, storage.DBConfig{})
+	first = registerTestQuorum(t, db, "1", 100, onPeerA)
+	second = registerTestQuorum(t, db, "2", 100, onPeerA)
+	third := registerTestQuorum(t, db, "3", 100)
+	h := newTestHandler(db, HandlerConfig{})
+	router = gin.New()
+	router.GET("/api/debug/peers", h.GetPeerDIDs)
+	router.GET("/api/debug/peer-index", h.GetPeerIndex)
+
+	w = serve(router, http.MethodGet, "/api/debug/peers", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("DB store status = %d, body %s", w.Code, w.Body)
+	}
+	var grouped struct {
+		Peers       map[string][]string `json:"peers"`
+		Count       int                 `json:"count"`
+		SharedPeers int                 `json:"shared_peers"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &grouped); err != nil {
+		t.Fatal(err)
+	}
+	if grouped.Count != 2 || grouped.SharedPeers != 1 ||
+		!slices.Equal(grouped.Peers["peerA"], []string{first, second}) || !slices.Equal(grouped.Peers["peer3"], []string{third}) {
+		t.Errorf("DB store peers = %+v", grouped)
+	}
+
+	// The DB store groups DIDs by peer rather than keeping an index
+	if w = serve(router, http.MethodGet, "/api/debug/peer-index", ""); w.Code != http.StatusNotImplemented {
+		t.Errorf("DB store peer index status = %d, want 501", w.Code)
+	}
+}
+
+func TestOnboardPersistsProfileAndIsSelectable(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	h := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.POST("/api/quorum/onboard", h.OnboardQuorum)
+	router.GET("/api/quorum/available", h.GetAvailableQuorums)
+
+	did := testDID("1")
+	body := `{"did":"` + did + `","peer_id":"peer1","balance":50,"did_type":4,` +
+		`"supported_tokens":["TRI"],"capabilities":["archival"],"region":"eu-west","tier":"gold",` +
+		`"metadata":{"operator":"acme"},"min_participation_balance":5,"max_transaction_share":25}`
+	w := serve(router, http.MethodPost, "/api/quorum/onboard", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("onboard status = %d, body %s", w.Code, w.Body)
+	}
+	var resp struct {
+		Quorum models.QuorumInfo `json:"quorum"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := store.GetQuorumByDID(did)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for source, quorum := range map[string]*models.QuorumInfo{"response": &resp.Quorum, "store": stored} {
+		if quorum.DID != did || quorum.PeerID != "peer1" || quorum.Balance != 50 || quorum.DIDType != 4 {
+			t.Errorf("%s identity = %s/%s/%v/%d", source, quorum.DID, quorum.PeerID, quorum.Balance, quorum.DIDType)
+		}
+		if !slices.Equal(quorum.SupportedTokens, []string{"TRI"}) || !slices.Equal(quorum.Capabilities, []string{"archival"}) {
+			t.Errorf("%s tokens/capabilities = %v/%v", source, quorum.SupportedTokens, quorum.Capabilities)
+		}
+		if quorum.Region != "eu-west" || quorum.Tier != "gold" || quorum.Metadata["operator"] != "acme" {
+			t.Errorf("%s region/tier/metadata = %q/%q/%v", source, quorum.Region, quorum.Tier, quorum.Metadata)
+		}
+		if quorum.MinParticipationBalance != 5 || quorum.MaxTransactionShare != 25 {
+			t.Errorf("%s participation = %v/%v", source, quorum.MinParticipationBalance, quorum.MaxTransactionShare)
+		}
+		if !quorum.Available {
+			t.Errorf("%s quorum is not available after onboarding", source)
+		}
+	}
+
+	w = serve(router, http.MethodGet, "/api/quorum/available?count=1&transaction_amount=10&ft_name=TRI&region=eu-west&requires=archival", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("available status = %d, body %s", w.Code, w.Body)
+	}
+	if !strings.Contains(w.Body.String(), did) {
+		t.Errorf("selection right after onboarding = %s, want %s", w.Body, did)
+	}
+}
+
+func TestImportQuorumsMixedLines(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	h := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.POST("/api/quorum/import", h.ImportQuorums)
+
+	record := func(suffix string) string {
+		return `{"did":"` + testDID(suffix) + `","peer_id":"peer` + suffix + `","balance":10,"did_type":4}`
+	}
+	upload := strings.Join([]string{
+		record("1"),
+		`{"did":`,
+		"",
+		`{"did":"not-a-did","peer_id":"peer3","balance":10,"did_type":4}`,
+		record("2"),
+	}, "\n")
+
+	for _, dryRun := range []bool{true, false} {
+		w := serve(router, http.MethodPost, fmt.Sprintf("/api/quorum/import?dry_run=%t", dryRun), upload)
+		if w.Code != http.StatusOK {
+			t.Fatalf("dry_run=%t status = %d, body %s", dryRun, w.Code, w.Body)
+		}
+		var summary models.ImportSummary
+		if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+			t.Fatal(err)
+		}
+		// The blank line is skipped and not counted
+		if summary.DryRun != dryRun || summary.Total != 4 || summary.Succeeded != 2 || summary.Failed != 2 {
+			t.Errorf("dry_run=%t summary = %+v, want 2 of 4 lines succeeding", dryRun, summary)
+		}
+		if len(summary.Errors) != 2 ||
+			summary.Errors[0].Line != 2 || summary.Errors[0].Code != models.ErrCodeInvalidRequest ||
+			summary.Errors[1].Line != 4 || summary.Errors[1].Code != models.ErrCodeInvalidDID {
+			t.Errorf("dry_run=%t errors = %+v, want line 2 %s and line 4 %s",
+				dryRun, summary.Errors, models.ErrCodeInvalidRequest, models.ErrCodeInvalidDID)
+		}
+
+		for _, suffix := range []string{"1", "2"} {
+			_, err := store.GetQuorumByDID(testDID(suffix))
+			if dryRun && err == nil {
+				t.Errorf("dry run registered quorum %s", suffix)
+			}
+			if !dryRun && err != nil {
+				t.Errorf("quorum %s from a valid line: %v", suffix, err)
+			}
+		}
+	}
+}
+
+func TestSelectionReportsRequestedAndDeliveredCounts(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	h := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.GET("/api/quorum/available", h.GetAvailableQuorums)
+	for i := 1; i <= 8; i++ {
+		registerTestQuorum(t, store, fmt.Sprint(i), 100)
+	}
+
+	tests := []struct {
+		name                         string
+		query                        string
+		wantStatus                   int
+		wantRequested, wantDelivered int
+	}{
+		{"exact", "count=3&transaction_amount=3", http.StatusOK, 3, 3},
+		{"count defaulted to 7", "count=0&transaction_amount=7", http.StatusOK, 7, 7},
+		{"pool too small", "count=9&transaction_amount=9", http.StatusServiceUnavailable, 9, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodGet, "/api/quorum/available?"+tt.query, "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body %s", w.Code, tt.wantStatus, w.Body)
+			}
+			var resp models.QuorumListResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.RequestedCount != tt.wantRequested || resp.DeliveredCount != tt.wantDelivered || len(resp.Quorums) != tt.wantDelivered {
+				t.Errorf("requested %d, delivered %d with %d quorums, want %d and %d",
+					resp.RequestedCount, resp.DeliveredCount, len(resp.Quorums), tt.wantRequested, tt.wantDelivered)
+			}
+		})
+	}
+}
+
+func TestValidateRegistration(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	h := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.POST("/api/quorum/validate", h.ValidateRegistration)
+
+	// Tombstone one DID with a signed unregister
+	tombstoned := registerTestQuorum(t, store, "9", 100)
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedAt := time.Now().Unix()
+	if err := store.UnregisterQuorumSigned(tombstoned, &models.UnregisterSignature{
+		PublicKey: base64.StdEncoding.EncodeToString(public),
+		SignedAt:  signedAt,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(storage.SignedMessage("unregister", tombstoned, signedAt)))),
+	}, false); err != nil {
+		t.Fatalf("UnregisterQuorumSigned: %v", err)
+	}
+
+	// payload is a valid registration with field set to value
+	payload := func(field string, value interface{}) string {
+		fields := map[string]interface{}{"did": testDID("1"), "peer_id": "peer1", "balance": 100, "did_type": 4}
+		if field != "" {
+			fields[field] = value
+		}
+		body, _ := json.Marshal(fields)
+		return string(body)
+	}
+	tests := []struct {
+		name      string
+		body      string
+		wantCheck string
+		wantCode  string
+	}{
+		{"valid", payload("", nil), "", ""},
+		{"malformed", `{"did":`, "request_format", models.ErrCodeInvalidRequest},
+		{"did", payload("did", "bafybmi-short"), "did_format", models.ErrCodeInvalidDID},
+		{"peer ID", payload("peer_id", "not base58!"), "peer_id_format", models.ErrCodeInvalidPeerID},
+		{"public key", payload("public_key", "AAAA"), "public_key", models.ErrCodeInvalidPublicKey},
+		{"DID type", payload("did_type", 7), "did_type", models.ErrCodeInvalidDIDType},
+		{"balances", payload("min_participation_balance", -1), "balances", models.ErrCodeInvalidBalance},
+		{"tokens", payload("supported_tokens", []string{"NOPE"}), "supported_tokens", models.ErrCodeInvalidToken},
+		{"region", payload("region", "eu west"), "region", models.ErrCodeInvalidRegion},
+		{"namespace", payload("namespace", "team/b"), "namespace", models.ErrCodeInvalidNamespace},
+		{"tier", payload("tier", "platinum"), "tier", models.ErrCodeInvalidTier},
+		{"metadata", payload("metadata", map[string]string{"": "x"}), "metadata", models.ErrCodeInvalidMetadata},
+		{"capabilities", payload("capabilities", []string{"no spaces"}), "capabilities", models.ErrCodeInvalidCapability},
+		{"tombstoned", payload("did", tombstoned), "tombstone", models.ErrCodeTombstoned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, http.MethodPost, "/api/quorum/validate", tt.body)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body)
+			}
+			var report models.RegistrationValidation
+			if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+				t.Fatal(err)
+			}
+
+			var failed []models.ValidationCheck
+			for _, check := range report.Checks {
+				if !check.Passed {
+					failed = append(failed, check)
+				}
+			}
+			if tt.wantCheck == "" {
+				if !report.Valid || len(failed) != 0 || report.Normalized == nil {
+					t.Errorf("report = %+v, want valid with the normalized payload", report)
+				}
+				return
+			}
+			if report.Valid || report.Normalized != nil {
+				t.Errorf("report = %+v, want invalid without a normalized payload", report)
+			}
+			if len(failed) != 1 || failed[0].Check != tt.wantCheck || failed[0].Code != tt.wantCode {
+				t.Errorf("failed checks = %+v, want only %s with %s", failed, tt.wantCheck, tt.wantCode)
+			}
+		})
+	}
+
+	if _, err := store.GetQuorumByDID(testDID("1")); err == nil {
+		t.Error("validation registered the quorum")
+	}
+}
+
+func TestCompareQuorums(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	h := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.GET("/api/quorum/compare", h.CompareQuorums)
+
+	a := registerTestQuorum(t, store, "1", 100, func(req *models.QuorumRegistrationRequest) {
+		req.SupportedTokens = []string{"RBT", "TRI"}
+	})
+	b := registerTestQuorum(t, store, "2", 50, func(req *models.QuorumRegistrationRequest) {
+		req.SupportedTokens = []string{"RBT"}
+	})
+	// Both serve two transactions; only the first succeeds
+	for _, txnID := range []string{"txn-1", "txn-2"} {
+		if _, err := store.SelectQuorums(models.QuorumListRequest{Count: 2, TransactionAmount: 2, TransactionID: txnID}); err != nil {
+			t.Fatalf("SelectQuorums(%s): %v", txnID, err)
+		}
+	}
+	if _, err := store.CompleteTransaction("txn-1", "success", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.CompleteTransaction("txn-2", "failed", false); err != nil {
+		t.Fatal(err)
+	}
+
+	w := serve(router, http.MethodGet, "/api/quorum/compare?a="+a+"&b="+b, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body)
+	}
+	comparison, _ := decodeBody(t, w)["comparison"].(map[string]interface{})
+	for side, want := range map[string]struct {
+		did     string
+		balance float64
+		tokens  int
+	}{"a": {a, 100, 2}, "b": {b, 50, 1}} {
+		metrics, _ := comparison[side].(map[string]interface{})
+		for _, field := range []string{"did", "balance", "assignment_count", "total_transactions", "successful_transactions",
+			"reliability", "uptime_ratio", "available", "last_ping", "registration_time", "supported_tokens"} {
+			if _, ok := metrics[field]; !ok {
+				t.Errorf("%s is missing %s: %v", side, field, metrics)
+			}
+		}
+		if metrics["did"] != want.did || metrics["balance"] != want.balance || metrics["assignment_count"] != 2.0 ||
+			metrics["total_transactions"] != 2.0 || metrics["successful_transactions"] != 1.0 || metrics["reliability"] != 0.5 {
+			t.Errorf("%s = %v, want %s with balance %v, 2 assignments and 1 of 2 transactions successful",
+				side, metrics, want.did, want.balance)
+		}
+		if tokens, _ := metrics["supported_tokens"].([]interface{}); len(tokens) != want.tokens {
+			t.Errorf("%s supported_tokens = %v, want %d", side, metrics["supported_tokens"], want.tokens)
+		}
+	}
+}
+
+func TestTransactionHistoryDIDsAreArrays(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	h := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.GET("/api/quorum/transactions", h.GetTransactionHistory)
+
+	registerTestQuorum(t, store, "1", 100)
+	registerTestQuorum(t, store, "2", 100)
+	selection, err := store.SelectQuorums(models.QuorumListRequest{Count: 2, TransactionAmount: 2, TransactionID: "txn-1"})
+	if err != nil {
+		t.Fatalf("SelectQuorums: %v", err)
+	}
+
+	w := serve(router, http.MethodGet, "/api/quorum/transactions", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body)
+	}
+	history, _ := decodeBody(t, w)["history"].([]interface{})
+	if len(history) != 1 {
+		t.Fatalf("history = %v, want one record", history)
+	}
+	record, _ := history[0].(map[string]interface{})
+	dids, ok := record["quorum_dids"].([]interface{})
+	if !ok {
+		t.Fatalf("quorum_dids = %#v, want a JSON array", record["quorum_dids"])
+	}
+	var want []interface{}
+	for _, q := range selection.Selected {
+		want = append(want, q.DID)
+	}
+	if !slices.Equal(dids, want) {
+		t.Errorf("quorum_dids = %v, want %v", dids, want)
+	}
+	if record["transaction_id"] != "txn-1" {
+		t.Errorf("transaction_id = %v, want txn-1", record["transaction_id"])
+	}
+}
+
+func TestResetAssignmentsEndpoint(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	h := newTestHandler(store, HandlerConfig{AdminToken: "admin-secret"})
+	router := gin.New()
+	router.POST("/api/admin/reset-assignments", h.ResetAssignments)
+
+	first := registerTestQuorum(t, store, "1", 100)
+	second := registerTestQuorum(t, store, "2", 100)
+	for i := 0; i < 3; i++ {
+		if _, err := store.SelectQuorums(models.QuorumListRequest{Count: 2, TransactionAmount: 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assignments := func(did string) int {
+		t.Helper()
+		quorum, err := store.GetQuorumByDID(did)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return quorum.AssignmentCount
+	}
+
+	w := serve(router, http.MethodPost, "/api/admin/reset-assignments", `{"did":"`+first+`"}`)
+	if w.Code != http.StatusOK || decodeBody(t, w)["reset"] != 1.0 {
+		t.Fatalf("reset one status = %d, body %s", w.Code, w.Body)
+	}
+	if assignments(first) != 0 || assignments(second) != 3 {
+		t.Errorf("counts = %d and %d, want only %s reset", assignments(first), assignments(second), first)
+	}
+
+	for _, tt := range []struct {
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{`{"did":"bafybmi-short"}`, http.StatusBadRequest, models.ErrCodeInvalidDID},
+		{`{"did":"` + testDID("9") + `"}`, http.StatusNotFound, models.ErrCodeQuorumNotFound},
+		{`{"did":`, http.StatusBadRequest, models.ErrCodeInvalidRequest},
+	} {
+		w := serve(router, http.MethodPost, "/api/admin/reset-assignments", tt.body, "Accept", mediaTypeV2)
+		if w.Code != tt.wantStatus || decodeBody(t, w)["error_code"] != tt.wantCode {
+			t.Errorf("%s: status = %d, body %s; want %d with %s", tt.body, w.Code, w.Body, tt.wantStatus, tt.wantCode)
+		}
+	}
+
+	// No body resets every quorum
+	w = serve(router, http.MethodPost, "/api/admin/reset-assignments", "")
+	if w.Code != http.StatusOK || decodeBody(t, w)["reset"] != 1.0 {
+		t.Fatalf("reset all status = %d, body %s", w.Code, w.Body)
+	}
+	if assignments(second) != 0 {
+		t.Errorf("%s count = %d after resetting all, want 0", second, assignments(second))
+	}
+
+	// Without an admin token or API keys the endpoint is refused
+	open := newTestHandler(store, HandlerConfig{})
+	router = gin.New()
+	router.POST("/api/admin/reset-assignments", open.RequireAdmin, open.ResetAssignments)
+	if w := serve(router, http.MethodPost, "/api/admin/reset-assignments", ""); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("reset without admin credentials: status = %d, want 503; body %s", w.Code, w.Body)
+	}
+}
+
+func TestCreateWebhookRefusesPrivateTargets(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	body := func(url string) string { return `{"url":"` + url + `","events":["quorum_stale"]}` }
+
+	h := newTestHandler(store, HandlerConfig{AdminToken: "admin-secret"})
+	router := gin.New()
+	router.POST("/api/webhooks", h.CreateWebhook)
+	for _, url := range []string{
+		"http://127.0.0.1:9000/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"https://10.0.0.5/hook",
+		"http://[::1]/hook",
+		"http://localhost/hook",
+	} {
+		w := serve(router, http.MethodPost, "/api/webhooks", body(url), "Accept", mediaTypeV2)
+		if w.Code != http.StatusBadRequest || decodeBody(t, w)["error_code"] != models.ErrCodeInvalidWebhook {
+			t.Errorf("%s: status = %d, body %s; want 400 %s", url, w.Code, w.Body, models.ErrCodeInvalidWebhook)
+		}
+	}
+
+	allowed := newTestHandler(store, HandlerConfig{AdminToken: "admin-secret", WebhookAllowPrivate: true})
+	router = gin.New()
+	router.POST("/api/webhooks", allowed.CreateWebhook)
+	if w := serve(router, http.MethodPost, "/api/webhooks", body("http://127.0.0.1:9000/hook")); w.Code != http.StatusCreated {
+		t.Errorf("with private targets allowed: status = %d, body %s", w.Code, w.Body)
+	}
+}
+
+func TestWebhooksRequireAdminCredential(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	open := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	hooks := router.Group("/api/webhooks", open.RequireAdmin)
+	hooks.POST("", open.CreateWebhook)
+	hooks.GET("", open.ListWebhooks)
+	hooks.DELETE("/:id", open.DeleteWebhook)
+
+	for _, tc := range []struct{ method, path, body string }{
+		{http.MethodPost, "/api/webhooks", `{"url":"https://hooks.example.com/advisory","events":["quorum_stale"]}`},
+		{http.MethodGet, "/api/webhooks", ""},
+		{http.MethodDelete, "/api/webhooks/1", ""},
+	} {
+		if w := serve(router, tc.method, tc.path, tc.body); w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s %s without admin credentials: status = %d, want 503; body %s", tc.method, tc.path, w.Code, w.Body)
+		}
+	}
+	if subscriptions, err := store.ListWebhookSubscriptions(); err != nil || len(subscriptions) != 0 {
+		t.Errorf("webhooks after refused requests = %v (%v), want none", subscriptions, err)
+	}
+}
+
+func TestMemoryStoreRefusesUnappliedSelectionFilters(t *testing.T) {
+	memory := storage.NewMemoryStore()
+	for _, suffix := range []string{"1", "2", "3"} {
+		registerTestQuorum(t, memory, suffix, 100)
+	}
+	router := gin.New()
+	router.GET("/api/quorum/available", newTestHandler(memory, HandlerConfig{}).GetAvailableQuorums)
+
+	for _, query := range []string{
+		"min_regions=2",
+		"region=eu-west",
+		"requires=gpu",
+		"namespace=testnet",
+		"min_tier=gold",
+		"min_successful_txns=5",
+		"transaction_id=txn-1",
+	} {
+		param, _, _ := strings.Cut(query, "=")
+		w := serve(router, http.MethodGet, "/api/quorum/available?count=2&transaction_amount=10&"+query, "", "Accept", mediaTypeV2)
+		if w.Code != http.StatusNotImplemented {
+			t.Errorf("%s: status = %d, want 501; body %s", query, w.Code, w.Body)
+			continue
+		}
+		body := decodeBody(t, w)
+		if body["error_code"] != models.ErrCodeNotImplemented || !strings.Contains(fmt.Sprint(body["message"]), param) {
+			t.Errorf("%s: body = %v, want %s naming %s", query, body, models.ErrCodeNotImplemented, param)
+		}
+	}
+
+	// The filters the store applies still select, and the message claims nothing else
+	w := serve(router, http.MethodGet, "/api/quorum/available?count=2&transaction_amount=10&did_type=4", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body)
+	}
+	var resp models.QuorumListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Quorums) != 2 || resp.Message != "Found 2 quorums with minimum balance of 5.0000 RBT of DID type 4" {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestPoolExportImportRequireAdminCredential(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	registerTestQuorum(t, store, "1", 100)
+	document := `{"format_version":1,"quorums":[]}`
+
+	open := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.GET("/api/admin/export", open.RequireAdmin, open.ExportPool)
+	router.POST("/api/admin/import", open.RequireAdmin, open.ImportPool)
+	if w := serve(router, http.MethodGet, "/api/admin/export", ""); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("export without admin credentials: status = %d, want 503", w.Code)
+	}
+	if w := serve(router, http.MethodPost, "/api/admin/import?mode=replace", document); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("import without admin credentials: status = %d, want 503; body %s", w.Code, w.Body)
+	}
+	if _, err := store.GetQuorumByDID(testDID("1")); err != nil {
+		t.Fatalf("quorum gone after a refused replace import: %v", err)
+	}
+
+	guarded := newTestHandler(store, HandlerConfig{AdminToken: "admin-secret"})
+	router = gin.New()
+	router.GET("/api/admin/export", guarded.RequireAdmin, guarded.ExportPool)
+	if w := serve(router, http.MethodGet, "/api/admin/export", "", "Authorization", "Bearer admin-secret"); w.Code != http.StatusOK {
+		t.Errorf("export with the token: status = %d, body %s", w.Code, w.Body)
+	}
+}
+
+func TestFreezeStalenessRequiresAdminCredential(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	body := `{"until":"` + time.Now().Add(time.Hour).Format(time.RFC3339) + `"}`
+
+	open := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.POST("/api/admin/freeze-staleness", open.RequireAdmin, open.FreezeStaleness)
+	if w := serve(router, http.MethodPost, "/api/admin/freeze-staleness", body); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("freeze without admin credentials: status = %d, want 503; body %s", w.Code, w.Body)
+	}
+	if _, frozen, err := store.StalenessFrozenUntil(); err != nil || frozen {
+		t.Fatalf("staleness frozen = %v (%v) after a refused freeze", frozen, err)
+	}
+
+	guarded := newTestHandler(store, HandlerConfig{AdminToken: "admin-secret"})
+	router = gin.New()
+	router.POST("/api/admin/freeze-staleness", guarded.RequireAdmin, guarded.FreezeStaleness)
+	if w := serve(router, http.MethodPost, "/api/admin/freeze-staleness", body, "Authorization", "Bearer admin-secret"); w.Code != http.StatusOK {
+		t.Errorf("freeze with the token: status = %d, body %s", w.Code, w.Body)
+	}
+	if _, frozen, err := store.StalenessFrozenUntil(); err != nil || !frozen {
+		t.Errorf("staleness frozen = %v (%v) after the freeze, want true", frozen, err)
+	}
+}
+
+func TestSetBalanceSafetyRequiresAdminCredential(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+
+	open := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.POST("/api/admin/balance-safety", open.RequireAdmin, open.SetBalanceSafety)
+	if w := serve(router, http.MethodPost, "/api/admin/balance-safety", `{"multiplier":10}`); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("multiplier without admin credentials: status = %d, want 503; body %s", w.Code, w.Body)
+	}
+	if got := store.BalanceSafetyMultiplier(); got != 1 {
+		t.Fatalf("multiplier = %v after a refused change, want 1", got)
+	}
+
+	guarded := newTestHandler(store, HandlerConfig{AdminToken: "admin-secret"})
+	router = gin.New()
+	router.POST("/api/admin/balance-safety", guarded.RequireAdmin, guarded.SetBalanceSafety)
+	for _, tt := range []struct {
+		body       string
+		wantStatus int
+	}{
+		{`{"multiplier":0.5}`, http.StatusBadRequest},
+		{`{"multiplier":1.5}`, http.StatusOK},
+	} {
+		if w := serve(router, http.MethodPost, "/api/admin/balance-safety", tt.body, "Authorization", "Bearer admin-secret"); w.Code != tt.wantStatus {
+			t.Errorf("%s with the token: status = %d, want %d; body %s", tt.body, w.Code, tt.wantStatus, w.Body)
+		}
+	}
+	if got := store.BalanceSafetyMultiplier(); got != 1.5 {
+		t.Errorf("multiplier = %v, want 1.5", got)
+	}
+}
+
+func TestCreateAPIKeyRequiresAdminCredential(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	body := `{"name":"wallet-backend","scopes":["admin"]}`
+
+	open := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.POST("/api/admin/api-keys", open.RequireAdmin, open.CreateAPIKey)
+	if w := serve(router, http.MethodPost, "/api/admin/api-keys", body); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("key creation without admin credentials: status = %d, want 503; body %s", w.Code, w.Body)
+	}
+	if keys, err := store.ListAPIKeys(); err != nil || len(keys) != 0 {
+		t.Fatalf("keys after a refused creation = %v (%v), want none", keys, err)
+	}
+
+	guarded := newTestHandler(store, HandlerConfig{AdminToken: "admin-secret"})
+	router = gin.New()
+	router.POST("/api/admin/api-keys", guarded.RequireAdmin, guarded.CreateAPIKey)
+	if w := serve(router, http.MethodPost, "/api/admin/api-keys", body, "Authorization", "Bearer admin-secret"); w.Code != http.StatusCreated {
+		t.Errorf("key creation with the token: status = %d, body %s", w.Code, w.Body)
+	}
+}
+
+func TestReserveScheduledBoundsCount(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	registerTestQuorum(t, store, "1", 100)
+	router := gin.New()
+	router.POST("/api/quorum/reserve-scheduled", newTestHandler(store, HandlerConfig{}).ReserveScheduled)
+
+	start := time.Now().Add(time.Hour).UTC()
+	body := func(count int) string {
+		return fmt.Sprintf(`{"transaction_amount":1,"count":%d,"start":%q,"end":%q}`,
+			count, start.Format(time.RFC3339), start.Add(time.Hour).Format(time.RFC3339))
+	}
+	for _, count := range []int{-1, storage.MaxQuorumCount + 1, 1 << 40} {
+		if w := serve(router, http.MethodPost, "/api/quorum/reserve-scheduled", body(count)); w.Code != http.StatusBadRequest {
+			t.Errorf("count %d: status = %d, want 400; body %s", count, w.Code, w.Body)
+		}
+	}
+	if w := serve(router, http.MethodPost, "/api/quorum/reserve-scheduled", body(1)); w.Code != http.StatusOK {
+		t.Errorf("count 1: status = %d, body %s", w.Code, w.Body)
+	}
+}
+
+func TestExplainSelectionBoundsCount(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	registerTestQuorum(t, store, "1", 100)
+	router := gin.New()
+	router.GET("/api/quorum/available/explain", newTestHandler(store, HandlerConfig{}).ExplainSelection)
+
+	if w := serve(router, http.MethodGet, "/api/quorum/available/explain?transaction_amount=1&count=2000000000", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("huge count: status = %d, want 400; body %s", w.Code, w.Body)
+	}
+	w := serve(router, http.MethodGet, "/api/quorum/available/explain?transaction_amount=1&count=2", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("count 2: status = %d, body %s", w.Code, w.Body)
+	}
+	explanation, _ := decodeBody(t, w)["explanation"].(map[string]interface{})
+	if explanation["selected_count"] != float64(0) || explanation["satisfiable"] != false {
+		t.Errorf("explanation = %v, want an unsatisfiable one selecting nothing", explanation)
+	}
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/metrics"
+	"github.com/gklps/advisory-node/models"
+	"github.com/gklps/advisory-node/proto/advisorypb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Rate limit defaults: requests per minute per client for registration and balance
+// writes, and for query endpoints
+const (
+	DefaultWriteRateLimit = 120
+	DefaultQueryRateLimit = 1200
+)
+
+// Rate limiter bounds: the largest body read to find a DID, and how many client
+// buckets are tracked before full ones are swept
+const (
+	maxRateLimitPeekBytes = 16 * 1024
+	maxTrackedRateClients = 10000
+)
+
+// RateLimiter is a token bucket per client. Each bucket holds up to a minute's worth of
+// requests and refills continuously, so a client may burst to its per-minute limit
+// and then sustain the limit's rate. Clients are keyed by IP, and optionally by the DID
+// a request is about, so one host serving many DIDs gets a bucket per DID.
+type RateLimiter struct {
+	name   string  // Bucket name reported in metrics and responses
+	limit  float64 // Requests per minute; also the bucket capacity
+	perDID bool
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewRateLimiter creates a limiter allowing perMinute requests per client. It returns
+// nil when perMinute is not positive; a nil limiter's middleware lets everything through.
+func NewRateLimiter(name string, perMinute float64, perDID bool) *RateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &RateLimiter{
+		name:    name,
+		limit:   perMinute,
+		perDID:  perDID,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// Middleware answers clients over their limit with 429 and a Retry-After header
+func (l *RateLimiter) Middleware() gin.HandlerFunc {
+	if l == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	return func(c *gin.Context) {
+		wait, ok := l.take(l.clientKey(c), time.Now())
+		if ok {
+			c.Next()
+			return
+		}
+
+		metrics.RateLimitedRequests.WithLabelValues(l.name).Inc()
+		setRetryAfter(c, wait)
+		respondError(c, http.StatusTooManyRequests, models.ErrCodeRateLimited, models.BasicResponse{
+			Status:  false,
+			Message: l.exceeded(wait),
+		})
+		c.Abort()
+	}
+}
+
+// GRPCRateLimit applies the write and query limiters to the gRPC API as setupRoutes does
+// to the REST equivalents: RegisterQuorum and UpdateBalance are writes, GetAvailableQuorums
+// and GetHealth queries, and availability confirmations and heartbeats are not limited.
+// Clients are keyed by IP, plus the request's DID when limiting per DID, and get
+// ResourceExhausted over their limit.
+func GRPCRateLimit(write, query *RateLimiter) grpc.UnaryServerInterceptor {
+	limiters := map[string]*RateLimiter{
+		advisorypb.AdvisoryNode_RegisterQuorum_FullMethodName:      write,
+		advisorypb.AdvisoryNode_UpdateBalance_FullMethodName:       write,
+		advisorypb.AdvisoryNode_GetAvailableQuorums_FullMethodName: query,
+		advisorypb.AdvisoryNode_GetHealth_FullMethodName:           query,
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		l := limiters[info.FullMethod]
+		if l == nil {
+			return handler(ctx, req)
+		}
+
+		key := grpcClientIP(ctx)
+		if in, ok := req.(interface{ GetDid() string }); ok && l.perDID && in.GetDid() != "" {
+			key += "|" + in.GetDid()
+		}
+		if wait, ok := l.take(key, time.Now()); !ok {
+			metrics.RateLimitedRequests.WithLabelValues(l.name).Inc()
+			return nil, status.Error(codes.ResourceExhausted, l.exceeded(wait))
+		}
+		return handler(ctx, req)
+	}
+}
+
+// exceeded describes a rejection, with wait until the client's next request is allowed
+func (l *RateLimiter) exceeded(wait time.Duration) string {
+	return fmt.Sprintf("Rate limit of %.0f %s requests per minute exceeded, retry in %s", l.limit, l.name, wait.Round(time.Second))
+}
+
+// take spends a token from key's bucket, or returns how long until one is available
+func (l *RateLimiter) take(key string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxTrackedRateClients {
+			l.sweep(now)
+		}
+		bucket = &tokenBucket{tokens: l.limit, updated: now}
+		l.buckets[key] = bucket
+	}
+
+	perSecond := l.limit / 60
+	bucket.tokens = min(l.limit, bucket.tokens+now.Sub(bucket.updated).Seconds()*perSecond)
+	bucket.updated = now
+
+	if bucket.tokens < 1 {
+		return time.Duration((1 - bucket.tokens) / perSecond * float64(time.Second)), false
+	}
+	bucket.tokens--
+	return 0, true
+}
+
+// sweep drops buckets that have refilled, which behave the same as new ones; callers
+// hold l.mu
+func (l *RateLimiter) sweep(now time.Time) {
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.updated) >= time.Minute {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// clientKey identifies the client: its IP, plus the DID from the path or a small JSON
+// body when limiting per DID. The body is restored for the handler.
+func (l *RateLimiter) clientKey(c *gin.Context) string {
+	key := c.ClientIP()
+	if !l.perDID {
+		return key
+	}
+
+	if did := c.Param("did"); did != "" {
+		return key + "|" + did
+	}
+	if c.Request.Body == nil || c.Request.ContentLength <= 0 || c.Request.ContentLength > maxRateLimitPeekBytes {
+		return key
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return key
+	}
+	var payload struct {
+		DID string `json:"did"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.DID != "" {
+		return key + "|" + payload.DID
+	}
+	return key
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+	"github.com/gklps/advisory-node/models"
+	"github.com/gklps/advisory-node/storage"
+)
+
+// Response envelope versions negotiated through the Accept header
+const (
+	apiVersionV1 = "v1"
+	apiVersionV2 = "v2"
+
+	mediaTypeV2 = "application/vnd.advisory.v2+json"
+)
+
+// Wire formats negotiated through the Accept header
+const (
+	formatJSON    = "json"
+	formatMsgPack = "msgpack"
+)
+
+// msgpackMediaTypes are the Accept values that select msgpack encoding
+var msgpackMediaTypes = []string{"application/x-msgpack", "application/msgpack", "application/vnd.msgpack"}
+
+// responseEncoder writes a handler result in a specific envelope version
+type responseEncoder interface {
+	Encode(c *gin.Context, httpStatus int, errorCode string, body interface{})
+}
+
+// v1Encoder writes bodies unchanged (the original API shape used by RubixGo)
+type v1Encoder struct{}
+
+func (v1Encoder) Encode(c *gin.Context, httpStatus int, _ string, body interface{}) {
+	writeBody(c, httpStatus, "", body)
+}
+
+// v2Encoder wraps bodies in models.APIEnvelope with status, message and error code lifted out
+type v2Encoder struct{}
+
+func (v2Encoder) Encode(c *gin.Context, httpStatus int, errorCode string, body interface{}) {
+	envelope := models.APIEnvelope{
+		APIVersion: apiVersionV2,
+		Status:     httpStatus < http.StatusBadRequest,
+		ErrorCode:  errorCode,
+		Timestamp:  time.Now(),
+	}
+
+	switch b := body.(type) {
+	case models.BasicResponse:
+		envelope.Status = b.Status
+		envelope.Message = b.Message
+	case models.QuorumListResponse:
+		envelope.Status = b.Status
+		envelope.Message = b.Message
+		if b.Quorums != nil {
+			envelope.Data = gin.H{
+				"quorums":         b.Quorums,
+				"requested_count": b.RequestedCount,
+				"delivered_count": b.DeliveredCount,
+			}
+		}
+	case models.VerboseQuorumListResponse:
+		envelope.Status = b.Status
+		envelope.Message = b.Message
+		envelope.Data = gin.H{
+			"quorums":         b.Quorums,
+			"requested_count": b.RequestedCount,
+			"delivered_count": b.DeliveredCount,
+		}
+	case gin.H:
+		data := gin.H{}
+		for key, value := range b {
+			switch key {
+			case "status":
+				if status, ok := value.(bool); ok {
+					envelope.Status = status
+				}
+			case "message":
+				if message, ok := value.(string); ok {
+					envelope.Message = message
+				}
+			default:
+				data[key] = value
+			}
+		}
+		if len(data) > 0 {
+			envelope.Data = data
+		}
+	default:
+		envelope.Data = body
+	}
+
+	writeBody(c, httpStatus, mediaTypeV2, envelope)
+}
+
+// writeBody serializes body in the negotiated wire format: msgpack when requested,
+// JSON otherwise (with jsonContentType overriding gin's default when set)
+func writeBody(c *gin.Context, httpStatus int, jsonContentType string, body interface{}) {
+	if negotiateFormat(c) == formatMsgPack {
+		c.Render(httpStatus, render.MsgPack{Data: body})
+		return
+	}
+	if jsonContentType != "" {
+		c.Header("Content-Type", jsonContentType)
+	}
+	WriteJSON(c, httpStatus, body)
+}
+
+// prettyJSONKey is the context key PrettyJSON sets to indent a request's responses
+const prettyJSONKey = "pretty_json"
+
+// PrettyJSON indents JSON responses by default, for manual exploration during
+// development; a request can still opt out with ?pretty=false
+func PrettyJSON() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(prettyJSONKey, true)
+		c.Next()
+	}
+}
+
+// WriteJSON writes body as JSON, indented when the request passes ?pretty=true or
+// PrettyJSON is installed. Compact output stays the default for API clients.
+func WriteJSON(c *gin.Context, httpStatus int, body interface{}) {
+	pretty := c.GetBool(prettyJSONKey)
+	if value := c.Query("pretty"); value != "" {
+		pretty, _ = strconv.ParseBool(value)
+	}
+
+	if pretty {
+		c.IndentedJSON(httpStatus, body)
+		return
+	}
+	c.JSON(httpStatus, body)
+}
+
+// acceptedMediaTypes returns the media types listed in the Accept header, without parameters
+func acceptedMediaTypes(c *gin.Context) []string {
+	var mediaTypes []string
+	for _, part := range strings.Split(c.GetHeader("Accept"), ",") {
+		if mediaType := strings.TrimSpace(strings.SplitN(part, ";", 2)[0]); mediaType != "" {
+			mediaTypes = append(mediaTypes, strings.ToLower(mediaType))
+		}
+	}
+	return mediaTypes
+}
+
+// negotiateFormat picks the wire format from the Accept header, defaulting to JSON
+func negotiateFormat(c *gin.Context) string {
+	for _, mediaType := range acceptedMediaTypes(c) {
+		for _, msgpackType := range msgpackMediaTypes {
+			if mediaType == msgpackType {
+				return formatMsgPack
+			}
+		}
+	}
+	return formatJSON
+}
+
+// negotiateVersion picks the envelope version from the Accept header, defaulting to v1
+func negotiateVersion(c *gin.Context) string {
+	for _, mediaType := range acceptedMediaTypes(c) {
+		if mediaType == mediaTypeV2 {
+			return apiVersionV2
+		}
+	}
+	return apiVersionV1
+}
+
+// encoderFor returns the response encoder for the version negotiated by the request
+func encoderFor(c *gin.Context) responseEncoder {
+	c.Header("Vary", "Accept")
+	if negotiateVersion(c) == apiVersionV2 {
+		return v2Encoder{}
+	}
+	return v1Encoder{}
+}
+
+// respond writes a successful (or code-less) response in the negotiated envelope
+func respond(c *gin.Context, httpStatus int, body interface{}) {
+	encoderFor(c).Encode(c, httpStatus, "", body)
+}
+
+// respondError writes a failed response; v2 clients also receive the error code
+func respondError(c *gin.Context, httpStatus int, errorCode string, body interface{}) {
+	encoderFor(c).Encode(c, httpStatus, errorCode, body)
+}
+
+// respondQuorumError reports a store failure for a DID-addressed request: an unknown
+// quorum is 404 QUORUM_NOT_FOUND, a disabled one 409 QUORUM_DISABLED, anything else is a
+// 500 prefixed with action
+func respondQuorumError(c *gin.Context, err error, action string) {
+	if errors.Is(err, storage.ErrQuorumNotFound) {
+		respondError(c, http.StatusNotFound, models.ErrCodeQuorumNotFound, models.BasicResponse{
+			Status:  false,
+			Message: "Quorum not found",
+		})
+		return
+	}
+	if errors.Is(err, storage.ErrQuorumDisabled) {
+		respondError(c, http.StatusConflict, models.ErrCodeQuorumDisabled, models.BasicResponse{
+			Status:  false,
+			Message: "Quorum was disabled by an operator and stays unavailable until re-enabled",
+		})
+		return
+	}
+	respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+		Status:  false,
+		Message: action + ": " + err.Error(),
+	})
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/models"
+	"github.com/gklps/advisory-node/storage"
+	"github.com/ugorji/go/codec"
+)
+
+func TestResponseVersionsShareEndpoint(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	h := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.GET("/api/quorum/info/:did", h.GetQuorumInfo)
+
+	did := registerTestQuorum(t, store, "1", 100)
+	path := "/api/quorum/info/" + did
+
+	// v1 clients send no Accept header and get the original shape
+	w := serve(router, http.MethodGet, path, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("v1 status = %d, body %s", w.Code, w.Body)
+	}
+	v1 := decodeBody(t, w)
+	if v1["status"] != true {
+		t.Errorf("v1 status field = %v, want true", v1["status"])
+	}
+	if _, ok := v1["quorum"].(map[string]interface{}); !ok {
+		t.Errorf("v1 body has no top-level quorum: %v", v1)
+	}
+	if _, ok := v1["api_version"]; ok {
+		t.Errorf("v1 body carries api_version: %v", v1)
+	}
+
+	// v2 clients get the envelope, with the payload under data
+	w = serve(router, http.MethodGet, path, "", "Accept", mediaTypeV2)
+	if w.Code != http.StatusOK {
+		t.Fatalf("v2 status = %d, body %s", w.Code, w.Body)
+	}
+	if got := w.Header().Get("Content-Type"); got != mediaTypeV2 {
+		t.Errorf("v2 Content-Type = %q, want %q", got, mediaTypeV2)
+	}
+	v2 := decodeBody(t, w)
+	if v2["api_version"] != apiVersionV2 || v2["status"] != true {
+		t.Errorf("v2 envelope = %v", v2)
+	}
+	data, ok := v2["data"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("v2 body has no data: %v", v2)
+	}
+	if _, ok := data["quorum"].(map[string]interface{}); !ok {
+		t.Errorf("v2 data has no quorum: %v", data)
+	}
+	if _, ok := v2["quorum"]; ok {
+		t.Errorf("v2 body carries a top-level quorum: %v", v2)
+	}
+}
+
+func TestResponseVersionsReportErrors(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	h := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.GET("/api/quorum/info/:did", h.GetQuorumInfo)
+	path := "/api/quorum/info/" + testDID("9")
+
+	w := serve(router, http.MethodGet, path, "")
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("v1 status = %d, want 404", w.Code)
+	}
+	v1 := decodeBody(t, w)
+	if msg, _ := v1["message"].(string); v1["status"] != false || !strings.HasPrefix(msg, "Quorum not found") {
+		t.Errorf("v1 body = %v", v1)
+	}
+	if _, ok := v1["error_code"]; ok {
+		t.Errorf("v1 body carries error_code: %v", v1)
+	}
+
+	w = serve(router, http.MethodGet, path, "", "Accept", mediaTypeV2)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("v2 status = %d, want 404", w.Code)
+	}
+	v2 := decodeBody(t, w)
+	if msg, _ := v2["message"].(string); v2["status"] != false || v2["error_code"] != "QUORUM_NOT_FOUND" || !strings.HasPrefix(msg, "Quorum not found") {
+		t.Errorf("v2 body = %v", v2)
+	}
+}
+
+func TestMsgPackMatchesJSON(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	h := newTestHandler(store, HandlerConfig{})
+	router := gin.New()
+	router.GET("/api/quorum/available", h.GetAvailableQuorums)
+	for _, suffix := range []string{"1", "2", "3"} {
+		registerTestQuorum(t, store, suffix, 100)
+	}
+
+	// The pool holds exactly count quorums and the transaction ID fixes their order, so
+	// both requests select the same set
+	path := "/api/quorum/available?count=3&transaction_amount=3&transaction_id=txn-msgpack"
+	w := serve(router, http.MethodGet, path, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("JSON status = %d, body %s", w.Code, w.Body)
+	}
+	var fromJSON models.QuorumListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	w = serve(router, http.MethodGet, path, "", "Accept", "application/x-msgpack")
+	if w.Code != http.StatusOK {
+		t.Fatalf("msgpack status = %d, body %s", w.Code, w.Body)
+	}
+	if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/msgpack") {
+		t.Errorf("msgpack Content-Type = %q", got)
+	}
+	var fromMsgPack models.QuorumListResponse
+	if err := codec.NewDecoderBytes(w.Body.Bytes(), new(codec.MsgpackHandle)).Decode(&fromMsgPack); err != nil {
+		t.Fatalf("decoding msgpack: %v", err)
+	}
+
+	if len(fromJSON.Quorums) != 3 {
+		t.Fatalf("JSON response = %+v, want 3 quorums", fromJSON)
+	}
+	if !reflect.DeepEqual(fromMsgPack, fromJSON) {
+		t.Errorf("msgpack response = %+v\nJSON response    = %+v", fromMsgPack, fromJSON)
+	}
+}
+
+func TestPrettyJSON(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	h := newTestHandler(store, HandlerConfig{})
+	did := registerTestQuorum(t, store, "1", 100)
+	path := "/api/quorum/info/" + did
+
+	plain := gin.New()
+	plain.GET("/api/quorum/info/:did", h.GetQuorumInfo)
+	withFlag := gin.New()
+	withFlag.Use(PrettyJSON())
+	withFlag.GET("/api/quorum/info/:did", h.GetQuorumInfo)
+
+	tests := []struct {
+		name       string
+		router     *gin.Engine
+		query      string
+		wantIndent bool
+	}{
+		{"compact by default", plain, "", false},
+		{"requested", plain, "?pretty=true", true},
+		{"flag", withFlag, "", true},
+		{"flag, opted out", withFlag, "?pretty=false", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(tt.router, http.MethodGet, path+tt.query, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body %s", w.Code, w.Body)
+			}
+			body := w.Body.String()
+			indented := strings.HasPrefix(body, "{\n    \"")
+			if indented != tt.wantIndent {
+				t.Errorf("indented = %t, want %t: %s", indented, tt.wantIndent, body)
+			}
+			if !json.Valid(w.Body.Bytes()) {
+				t.Errorf("invalid JSON: %s", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/models"
+)
+
+// Selection backoff bounds: the shortest Retry-After handed out, the default longest,
+// and how many client/parameter pairs are tracked before stale ones are swept
+const (
+	minSelectionBackoff        = time.Second
+	DefaultSelectionBackoffMax = time.Minute
+	maxTrackedSelections       = 10000
+)
+
+// selectionBackoff tracks consecutive failed selections per client and parameter set.
+// Once a client has failed threshold times in a row it is answered with 429 until a
+// Retry-After elapses, so retry loops against an exhausted pool stop reaching the
+// database. The wait is the time since the pool last satisfied the same parameters for
+// anyone, clamped to [minSelectionBackoff, max]: a pool that served the request moments
+// ago is likely to again soon, one that hasn't for minutes is not. A registration,
+// balance update or availability confirmation lifts every backoff, since the pool may
+// have grown.
+type selectionBackoff struct {
+	threshold int // Consecutive failures before backing off (0 disables)
+	max       time.Duration
+
+	mu         sync.Mutex
+	clients    map[string]*backoffEntry // Key: client IP + parameters
+	satisfied  map[string]time.Time     // Key: parameters; last successful selection
+	poolGrewAt time.Time
+}
+
+type backoffEntry struct {
+	failures    int
+	lastFailure time.Time
+	retryAt     time.Time
+}
+
+func newSelectionBackoff(threshold int, max time.Duration) *selectionBackoff {
+	if max <= 0 {
+		max = DefaultSelectionBackoffMax
+	}
+	return &selectionBackoff{
+		threshold: threshold,
+		max:       max,
+		clients:   make(map[string]*backoffEntry),
+		satisfied: make(map[string]time.Time),
+	}
+}
+
+// selectionKey identifies a request's parameters; the transaction ID is left out
+// because clients usually generate a fresh one per attempt
+func selectionKey(req models.QuorumListRequest) string {
+	return strings.Join([]string{
+		strconv.Itoa(req.Count),
+		strconv.FormatFloat(req.TransactionAmount, 'g', -1, 64),
+		req.FTName,
+		req.Namespace,
+		req.LastCharTID,
+		strconv.Itoa(req.Type),
+		strconv.Itoa(req.MinRegions),
+		strings.Join(req.Requires, ","),
+		req.MinTier,
+		strconv.FormatInt(req.MinSuccessfulTxns, 10),
+		didTypeKey(req.DIDType),
+	}, "|")
+}
+
+func didTypeKey(didType *int) string {
+	if didType == nil {
+		return ""
+	}
+	return strconv.Itoa(*didType)
+}
+
+// retryAfter returns how long client must wait before selecting with params again,
+// and whether it is currently backed off
+func (b *selectionBackoff) retryAfter(client, params string) (time.Duration, bool) {
+	if b.threshold <= 0 {
+		return 0, false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entry, ok := b.clients[client+"|"+params]
+	if !ok || entry.lastFailure.Before(b.poolGrewAt) {
+		return 0, false
+	}
+	wait := time.Until(entry.retryAt)
+	return wait, wait > 0
+}
+
+// fail records a failed selection and returns the wait now imposed on client, or 0
+// while it is still under the threshold
+func (b *selectionBackoff) fail(client, params string) time.Duration {
+	if b.threshold <= 0 {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	key := client + "|" + params
+	entry, ok := b.clients[key]
+	if !ok {
+		if len(b.clients) >= maxTrackedSelections {
+			b.sweep(now)
+			if len(b.clients) >= maxTrackedSelections {
+				return 0
+			}
+		}
+		entry = &backoffEntry{}
+		b.clients[key] = entry
+	}
+
+	// A failure streak ends once it goes quiet for the longest backoff, or when the
+	// pool has grown since the last failure
+	if now.Sub(entry.lastFailure) > b.max || entry.lastFailure.Before(b.poolGrewAt) {
+		entry.failures = 0
+		entry.retryAt = time.Time{}
+	}
+	entry.failures++
+	entry.lastFailure = now
+
+	if entry.failures < b.threshold {
+		return 0
+	}
+
+	wait := b.max
+	if at, ok := b.satisfied[params]; ok && now.Sub(at) < wait {
+		wait = now.Sub(at)
+	}
+	if wait < minSelectionBackoff {
+		wait = minSelectionBackoff
+	}
+	entry.retryAt = now.Add(wait)
+	return wait
+}
+
+// succeed clears client's failure streak and records that the pool satisfied params
+func (b *selectionBackoff) succeed(client, params string) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.clients, client+"|"+params)
+	if _, ok := b.satisfied[params]; !ok && len(b.satisfied) >= maxTrackedSelections {
+		b.sweep(time.Now())
+	}
+	b.satisfied[params] = time.Now()
+}
+
+// poolGrew lifts every active backoff
+func (b *selectionBackoff) poolGrew() {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.poolGrewAt = time.Now()
+}
+
+// sweep drops entries too old to affect any decision; callers hold b.mu
+func (b *selectionBackoff) sweep(now time.Time) {
+	for key, entry := range b.clients {
+		if now.Sub(entry.lastFailure) > b.max && now.After(entry.retryAt) {
+			delete(b.clients, key)
+		}
+	}
+	for params, at := range b.satisfied {
+		if now.Sub(at) > b.max {
+			delete(b.satisfied, params)
+		}
+	}
+}
+
+// setRetryAfter sets the Retry-After header to wait, rounded up to whole seconds
+func setRetryAfter(c *gin.Context, wait time.Duration) {
+	c.Header("Retry-After", fmt.Sprintf("%d", int64(math.Ceil(wait.Seconds()))))
+}
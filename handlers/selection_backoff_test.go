@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/models"
+	"github.com/gklps/advisory-node/storage"
+)
+
+func TestRepeatedFailedSelectionsBackOff(t *testing.T) {
+	store := newTestStore(t, storage.DBConfig{})
+	h := newTestHandler(store, HandlerConfig{SelectionBackoffFailures: 3, SelectionBackoffMax: 30 * time.Second})
+	router := gin.New()
+	router.GET("/api/quorum/available", h.GetAvailableQuorums)
+	router.POST("/api/quorum/register", h.RegisterQuorum)
+	registerTestQuorum(t, store, "1", 100)
+
+	// One quorum can't satisfy a selection of two
+	const tooMany = "/api/quorum/available?count=2&transaction_amount=2"
+	for i := 1; i <= 3; i++ {
+		w := serve(router, http.MethodGet, tooMany, "")
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("failure %d status = %d, want 503", i, w.Code)
+		}
+		// The failure reaching the threshold announces the backoff
+		if retryAfter := w.Header().Get("Retry-After"); (retryAfter != "") != (i == 3) {
+			t.Errorf("failure %d Retry-After = %q", i, retryAfter)
+		}
+	}
+
+	w := serve(router, http.MethodGet, tooMany, "", "Accept", mediaTypeV2)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status after 3 failures = %d, want 429; body %s", w.Code, w.Body)
+	}
+	// The pool never satisfied these parameters, so the wait is the maximum
+	if seconds, err := strconv.Atoi(w.Header().Get("Retry-After")); err != nil || seconds < 29 || seconds > 30 {
+		t.Errorf("Retry-After = %q, want about 30 seconds", w.Header().Get("Retry-After"))
+	}
+	if code := decodeBody(t, w)["error_code"]; code != models.ErrCodeSelectionBackoff {
+		t.Errorf("error_code = %v, want %s", code, models.ErrCodeSelectionBackoff)
+	}
+
+	// Other parameters aren't held back
+	if w := serve(router, http.MethodGet, "/api/quorum/available?count=1&transaction_amount=1", ""); w.Code != http.StatusOK {
+		t.Errorf("different selection status = %d, want 200", w.Code)
+	}
+
+	// A registration may have made the selection possible, so it lifts the backoff
+	w = serve(router, http.MethodPost, "/api/quorum/register", `{"did":"`+testDID("2")+`","peer_id":"peer2","balance":100,"did_type":4}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("register status = %d, body %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodGet, tooMany, ""); w.Code != http.StatusOK {
+		t.Errorf("status after the pool grew = %d, want 200; body %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/models"
+	"github.com/gklps/advisory-node/storage"
+)
+
+// paramError is a query-parameter validation failure together with its API error code
+type paramError struct {
+	Code    string
+	Message string
+}
+
+func (e *paramError) Error() string {
+	return e.Message
+}
+
+// maxTransactionIDLength bounds caller-supplied transaction IDs to the stored column size
+const maxTransactionIDLength = 255
+
+// selectionQuery reads the selection query parameters shared by GET /available and the
+// read-only endpoints that evaluate the same filters. Malformed numbers are passed on
+// out of range, so validateSelection reports them as the gRPC API does.
+func selectionQuery(c *gin.Context) models.QuorumListRequest {
+	req := models.QuorumListRequest{
+		LastCharTID:   c.Query("last_char_tid"),
+		FTName:        c.Query("ft_name"), // Get token type parameter
+		TransactionID: c.Query("transaction_id"),
+		Region:        c.Query("region"),
+		Namespace:     c.Query("namespace"),
+		MinTier:       c.Query("min_tier"),
+	}
+
+	// Parse query parameters; an unparseable count or type gets the default
+	if count, err := strconv.Atoi(c.Query("count")); err == nil {
+		req.Count = count
+	}
+	if qtype, err := strconv.Atoi(c.Query("type")); err == nil {
+		req.Type = qtype
+	}
+
+	// Parse transaction amount
+	if amount, err := strconv.ParseFloat(c.Query("transaction_amount"), 64); err == nil {
+		req.TransactionAmount = amount
+	}
+
+	// Parse minimum region spread
+	if minRegionsStr := c.Query("min_regions"); minRegionsStr != "" {
+		minRegions, err := strconv.Atoi(minRegionsStr)
+		if err != nil {
+			minRegions = -1
+		}
+		req.MinRegions = minRegions
+	}
+
+	// Parse required capabilities (comma-separated)
+	if requiresStr := c.Query("requires"); requiresStr != "" {
+		req.Requires = strings.Split(requiresStr, ",")
+	}
+
+	// Parse minimum proven-quorum track record
+	if minTxnsStr := c.Query("min_successful_txns"); minTxnsStr != "" {
+		minTxns, err := strconv.ParseInt(minTxnsStr, 10, 64)
+		if err != nil {
+			minTxns = -1
+		}
+		req.MinSuccessfulTxns = minTxns
+	}
+
+	if didTypeStr := c.Query("did_type"); didTypeStr != "" {
+		didType, err := strconv.Atoi(didTypeStr)
+		if err != nil {
+			didType = -1
+		}
+		req.DIDType = &didType
+	}
+
+	return req
+}
+
+// validateSelection applies the selection defaults to req, normalizes its filters and
+// validates them, including min_tier against the store's configured collateral tiers.
+// Both the REST query parameters and gRPC GetAvailableQuorums requests go through it.
+func (h *QuorumHandler) validateSelection(req *models.QuorumListRequest) *paramError {
+	if req.Count <= 0 {
+		req.Count = 7 // Default to 7 quorums
+	}
+	if req.Count > storage.MaxQuorumCount {
+		return &paramError{models.ErrCodeInvalidRequest, fmt.Sprintf("count must be at most %d", storage.MaxQuorumCount)}
+	}
+	if req.Type == 0 {
+		req.Type = 2 // Default to type 2 (private subnet)
+	}
+
+	// Transaction amount is required for balance validation
+	if req.TransactionAmount <= 0 {
+		return &paramError{models.ErrCodeInvalidAmount, "Transaction amount must be provided and greater than 0"}
+	}
+	if len(req.TransactionID) > maxTransactionIDLength {
+		return &paramError{models.ErrCodeInvalidRequest,
+			fmt.Sprintf("transaction_id must be at most %d characters", maxTransactionIDLength)}
+	}
+
+	if req.MinRegions < 0 || req.MinRegions > req.Count {
+		return &paramError{models.ErrCodeInvalidRequest,
+			fmt.Sprintf("min_regions must be an integer between 0 and count (%d)", req.Count)}
+	}
+
+	// Preferred region, filled from other regions when it has too few quorums
+	req.Region = normalizeRegion(req.Region)
+	if !isValidRegion(req.Region) {
+		return &paramError{models.ErrCodeInvalidRegion, "Invalid region. Use up to 64 lowercase letters, digits, '-' or '_'"}
+	}
+
+	if len(req.Requires) > 0 {
+		requires, err := normalizeCapabilities(req.Requires)
+		if err != nil {
+			return &paramError{models.ErrCodeInvalidCapability, "Invalid requires parameter: " + err.Error()}
+		}
+		req.Requires = requires
+	}
+
+	if req.MinSuccessfulTxns < 0 {
+		return &paramError{models.ErrCodeInvalidRequest, "min_successful_txns must be a non-negative integer"}
+	}
+
+	// Logical network to select from
+	req.Namespace = normalizeNamespace(req.Namespace)
+	if !isValidNamespace(req.Namespace) {
+		return &paramError{models.ErrCodeInvalidNamespace, "Invalid namespace. Use up to 64 lowercase letters, digits, '-' or '_'"}
+	}
+
+	if req.DIDType != nil && (*req.DIDType < 0 || *req.DIDType > 4) {
+		return &paramError{models.ErrCodeInvalidDIDType, "did_type must be between 0 and 4"}
+	}
+
+	// Minimum collateral tier
+	req.MinTier = normalizeTier(req.MinTier)
+	if req.MinTier != "" && !h.hasTier(req.MinTier) {
+		return &paramError{models.ErrCodeInvalidTier, fmt.Sprintf("Unknown min_tier %q", req.MinTier)}
+	}
+	return nil
+}
+
+// parseDIDTypeFilter reads the optional did_type query parameter, returning nil when
+// it is absent
+func parseDIDTypeFilter(c *gin.Context) (*int, *paramError) {
+	value := c.Query("did_type")
+	if value == "" {
+		return nil, nil
+	}
+	didType, err := strconv.Atoi(value)
+	if err != nil || didType < 0 || didType > 4 {
+		return nil, &paramError{models.ErrCodeInvalidDIDType, "did_type must be between 0 and 4"}
+	}
+	return &didType, nil
+}
+
+// parseSelection reads and validates the selection query parameters
+func (h *QuorumHandler) parseSelection(c *gin.Context) (models.QuorumListRequest, *paramError) {
+	req := selectionQuery(c)
+	return req, h.validateSelection(&req)
+}
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/gklps/advisory-node/models"
+	"github.com/gklps/advisory-node/storage"
 )
 
 // isValidDID validates DID format (matching RubixGo validation)
@@ -16,3 +20,151 @@ func isValidDID(did string) bool {
 	isAlphanumeric := regexp.MustCompile(`^[a-zA-Z0-9]*$`).MatchString(did)
 	return isAlphanumeric
 }
+
+// peerIDPattern matches base58 libp2p peer IDs: an Ed25519 identity multihash
+// ("12D3KooW...", 52 characters), a secp256k1 one ("16Uiu2HAm...", 53) or the SHA-256
+// multihash of an RSA key ("Qm...", 46). Quorum addresses are formatted "PeerID.DID",
+// so a malformed peer ID would poison every selection returning the quorum.
+var peerIDPattern = regexp.MustCompile(`^(12D3KooW[1-9A-HJ-NP-Za-km-z]{44}|16Uiu2HAm[1-9A-HJ-NP-Za-km-z]{44}|Qm[1-9A-HJ-NP-Za-km-z]{44})$`)
+
+// relaxedPeerIDPattern accepts any short base58 string, for test environments with
+// made-up peer IDs; it still excludes '.', which would break address parsing
+var relaxedPeerIDPattern = regexp.MustCompile(`^[1-9A-HJ-NP-Za-km-z]{1,128}$`)
+
+// isValidPeerID validates a peer ID's format
+func isValidPeerID(peerID string) bool {
+	return peerIDPattern.MatchString(peerID)
+}
+
+// checkPeerID validates peerID strictly, or against relaxedPeerIDPattern when relaxed,
+// returning the message to reject it with
+func checkPeerID(peerID string, relaxed bool) (string, bool) {
+	if relaxed {
+		return "Invalid peer ID format. Peer ID must be 1-128 base58 characters", relaxedPeerIDPattern.MatchString(peerID)
+	}
+	return "Invalid peer ID format. Peer ID must be a base58 libp2p peer ID (12D3KooW..., 16Uiu2HAm... or Qm...)", isValidPeerID(peerID)
+}
+
+// regionPattern matches region tags such as "eu-west" or "us-east-1"
+var regionPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// normalizeRegion lower-cases and trims a region tag
+func normalizeRegion(region string) string {
+	return strings.ToLower(strings.TrimSpace(region))
+}
+
+// normalizeTier lower-cases and trims a collateral tier name
+func normalizeTier(tier string) string {
+	return strings.ToLower(strings.TrimSpace(tier))
+}
+
+// isValidRegion validates an already-normalized region tag (empty means untagged)
+func isValidRegion(region string) bool {
+	return region == "" || regionPattern.MatchString(region)
+}
+
+// normalizeNamespace lower-cases and trims a namespace, mapping empty to the default pool
+func normalizeNamespace(namespace string) string {
+	namespace = strings.ToLower(strings.TrimSpace(namespace))
+	if namespace == "" {
+		return models.DefaultNamespace
+	}
+	return namespace
+}
+
+// isValidNamespace validates an already-normalized namespace (same rules as region tags)
+func isValidNamespace(namespace string) bool {
+	return regionPattern.MatchString(namespace)
+}
+
+// Limits on operator-supplied quorum metadata
+const (
+	maxMetadataEntries  = 32
+	maxMetadataKeyLen   = 64
+	maxMetadataValueLen = 256
+)
+
+// validateMetadata checks quorum metadata against size limits
+func validateMetadata(metadata map[string]string) error {
+	if len(metadata) > maxMetadataEntries {
+		return fmt.Errorf("metadata may have at most %d entries", maxMetadataEntries)
+	}
+	for key, value := range metadata {
+		if key == "" || len(key) > maxMetadataKeyLen {
+			return fmt.Errorf("metadata keys must be 1-%d characters", maxMetadataKeyLen)
+		}
+		if len(value) > maxMetadataValueLen {
+			return fmt.Errorf("metadata value for %q exceeds %d characters", key, maxMetadataValueLen)
+		}
+	}
+	return nil
+}
+
+// capabilityPattern matches capability flags such as "supports-lite"
+var capabilityPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// normalizeCapabilities lower-cases, trims and de-duplicates capability flags,
+// rejecting any that don't match capabilityPattern
+func normalizeCapabilities(capabilities []string) ([]string, error) {
+	seen := make(map[string]bool)
+	normalized := make([]string, 0, len(capabilities))
+	for _, capability := range capabilities {
+		capability = strings.ToLower(strings.TrimSpace(capability))
+		if capability == "" {
+			continue
+		}
+		if !capabilityPattern.MatchString(capability) {
+			return nil, fmt.Errorf("invalid capability %q", capability)
+		}
+		if !seen[capability] {
+			seen[capability] = true
+			normalized = append(normalized, capability)
+		}
+	}
+	return normalized, nil
+}
+
+// KnownTokens are the token types quorums may declare without further configuration
+var KnownTokens = storage.KnownTokens
+
+// normalizeTokenName upper-cases and trims a token name, the form tokens are stored and
+// filtered in
+func normalizeTokenName(token string) string {
+	return strings.ToUpper(strings.TrimSpace(token))
+}
+
+// normalizeTokens normalizes and de-duplicates token names, rejecting any not in
+// allowed. A list without tokens stays nil, so it is stored as declaring none.
+func normalizeTokens(tokens []string, allowed map[string]bool) ([]string, error) {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, token := range tokens {
+		token = normalizeTokenName(token)
+		if token == "" {
+			continue
+		}
+		if !allowed[token] {
+			return nil, fmt.Errorf("unknown token %q", token)
+		}
+		if !seen[token] {
+			seen[token] = true
+			normalized = append(normalized, token)
+		}
+	}
+	return normalized, nil
+}
+
+// excludeStrings returns values without any of the entries in drop, keeping their order
+func excludeStrings(values, drop []string) []string {
+	dropped := make(map[string]bool, len(drop))
+	for _, value := range drop {
+		dropped[value] = true
+	}
+	kept := make([]string, 0, len(values))
+	for _, value := range values {
+		if !dropped[value] {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gklps/advisory-node/models"
+	"github.com/gklps/advisory-node/storage"
+	"github.com/gklps/advisory-node/webhooks"
+)
+
+// CreateWebhook handles POST /api/webhooks
+// Subscribes a URL to quorum lifecycle events; each matching event is POSTed to it as
+// JSON. Like the other webhook endpoints, it is refused unless an admin token or API keys
+// are configured, since subscriptions receive every quorum's lifecycle events.
+func (h *QuorumHandler) CreateWebhook(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok || !h.requireAdminCredential(c, "Creating webhooks") {
+		return
+	}
+
+	var req models.WebhookSubscriptionRequest
+	if err := h.bindJSON(c, &req); err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid request format: " + err.Error(),
+		})
+		return
+	}
+
+	if paramErr := validateWebhookRequest(c.Request.Context(), &req, h.config.WebhookAllowPrivate); paramErr != nil {
+		respondError(c, http.StatusBadRequest, paramErr.Code, models.BasicResponse{
+			Status:  false,
+			Message: paramErr.Message,
+		})
+		return
+	}
+
+	sub, err := ext.CreateWebhookSubscription(req.URL, req.Events)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to create webhook subscription: " + err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusCreated, gin.H{
+		"status":       true,
+		"message":      fmt.Sprintf("Subscribed %s to %d events", sub.URL, len(sub.Events)),
+		"subscription": sub,
+	})
+}
+
+// ListWebhooks handles GET /api/webhooks
+func (h *QuorumHandler) ListWebhooks(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok || !h.requireAdminCredential(c, "Listing webhooks") {
+		return
+	}
+
+	subs, err := ext.ListWebhookSubscriptions()
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to list webhook subscriptions: " + err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, gin.H{
+		"status":        true,
+		"count":         len(subs),
+		"subscriptions": subs,
+	})
+}
+
+// DeleteWebhook handles DELETE /api/webhooks/:id
+func (h *QuorumHandler) DeleteWebhook(c *gin.Context) {
+	ext, ok := h.extended(c)
+	if !ok || !h.requireAdminCredential(c, "Deleting webhooks") {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, models.ErrCodeInvalidRequest, models.BasicResponse{
+			Status:  false,
+			Message: "Invalid webhook subscription ID",
+		})
+		return
+	}
+
+	if err := ext.DeleteWebhookSubscription(uint(id)); err != nil {
+		if errors.Is(err, storage.ErrWebhookNotFound) {
+			respondError(c, http.StatusNotFound, models.ErrCodeWebhookNotFound, models.BasicResponse{
+				Status:  false,
+				Message: err.Error(),
+			})
+			return
+		}
+		respondError(c, http.StatusInternalServerError, models.ErrCodeInternal, models.BasicResponse{
+			Status:  false,
+			Message: "Failed to delete webhook subscription: " + err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, models.BasicResponse{
+		Status:  true,
+		Message: "Webhook subscription deleted",
+	})
+}
+
+// validateWebhookRequest requires an absolute http(s) URL, on a public host unless
+// allowPrivate is set, and at least one known event, dropping repeated events
+func validateWebhookRequest(ctx context.Context, req *models.WebhookSubscriptionRequest, allowPrivate bool) *paramError {
+	parsed, err := url.Parse(req.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return &paramError{models.ErrCodeInvalidWebhook, "Webhook URL must be an absolute http or https URL"}
+	}
+	if !allowPrivate {
+		if err := webhooks.CheckTarget(ctx, parsed.Hostname()); err != nil {
+			return &paramError{models.ErrCodeInvalidWebhook, err.Error()}
+		}
+	}
+
+	if len(req.Events) == 0 {
+		return &paramError{models.ErrCodeInvalidWebhook, fmt.Sprintf("At least one event is required, from %v", webhooks.EventTypes)}
+	}
+	seen := make(map[string]bool, len(req.Events))
+	events := make([]string, 0, len(req.Events))
+	for _, event := range req.Events {
+		if err := webhooks.ValidateEventType(event); err != nil {
+			return &paramError{models.ErrCodeInvalidWebhook, err.Error()}
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+	req.Events = events
+	return nil
+}
+
+// publishRegistered notifies quorum_registered subscribers of successful registrations
+func (h *QuorumHandler) publishRegistered(reqs ...*models.QuorumRegistrationRequest) {
+	events := make([]interface{}, 0, len(reqs))
+	for _, req := range reqs {
+		events = append(events, webhooks.QuorumEvent{DID: req.DID, PeerID: req.PeerID})
+	}
+	h.config.Webhooks.Publish(webhooks.EventQuorumRegistered, events...)
+}
@@ -20,6 +20,15 @@ var (
 	port       = flag.String("port", "8080", "Server port")
 	mode       = flag.String("mode", "release", "Server mode (debug/release)")
 	corsOrigin = flag.String("cors", "*", "CORS allowed origins")
+
+	debugEndpoints = flag.Bool("debug-endpoints", false, "Expose /api/debug endpoints (peer index dump)")
+	emptyTokens    = flag.String("empty-tokens-means", storage.EmptyTokensRBTOnly, "Tokens supported by quorums declaring none (rbt-only/all/none)")
+	precision      = flag.Int("balance-precision", storage.DefaultBalancePrecision, "Decimal places required balances are rounded up to")
+	window         = flag.Duration("availability-window", storage.DefaultAvailabilityWindow, "How recently a quorum must have pinged to be selectable")
+	relaxedPeers   = flag.Bool("relaxed-peer-ids", false, "Accept any short base58 peer ID at registration, not only libp2p peer IDs (test environments)")
+
+	storeType = flag.String("store-type", "memory", "Quorum store (memory/redis); redis shares one pool between replicas")
+	redisURL  = flag.String("redis-url", "redis://localhost:6379/0", "Redis server URL for -store-type redis")
 )
 
 func main() {
@@ -29,7 +38,11 @@ func main() {
 	gin.SetMode(*mode)
 
 	// Initialize storage
-	store := storage.NewMemoryStore()
+	store, closeStore, err := openStore()
+	if err != nil {
+		log.Fatalf("Failed to initialize %s store: %v", *storeType, err)
+	}
+	defer closeStore()
 
 	// Initialize router
 	router := gin.Default()
@@ -48,7 +61,9 @@ func main() {
 	router.Use(gin.Recovery())
 
 	// Initialize handlers
-	quorumHandler := handlers.NewQuorumHandler(store)
+	quorumHandler := handlers.NewQuorumHandlerWithConfig(store, handlers.HandlerConfig{
+		RelaxedPeerIDs: *relaxedPeers,
+	})
 
 	// Setup routes
 	setupRoutes(router, quorumHandler)
@@ -71,6 +86,7 @@ func main() {
 
 	fmt.Printf("Advisory Node Service started on port %s\n", *port)
 	fmt.Printf("Mode: %s\n", *mode)
+	fmt.Printf("Store: %s\n", *storeType)
 	fmt.Println("API Endpoints:")
 	fmt.Println("  POST   /api/quorum/register           - Register a quorum")
 	fmt.Println("  POST   /api/quorum/confirm-availability - Confirm quorum availability")
@@ -79,6 +95,13 @@ func main() {
 	fmt.Println("  POST   /api/quorum/heartbeat          - Update quorum heartbeat")
 	fmt.Println("  GET    /api/quorum/info/:did          - Get quorum information")
 	fmt.Println("  GET    /api/quorum/health             - Get service health status")
+	if *debugEndpoints {
+		if *storeType == "memory" {
+			fmt.Println("  GET    /api/debug/peers               - Dump the PeerID -> DID index")
+		} else {
+			fmt.Println("  GET    /api/debug/peers               - DIDs grouped by peer ID")
+		}
+	}
 
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
@@ -107,6 +130,18 @@ func setupRoutes(router *gin.Engine, handler *handlers.QuorumHandler) {
 			quorum.DELETE("/unregister/:did", handler.UnregisterQuorum)
 			quorum.POST("/heartbeat", handler.Heartbeat)
 		}
+
+		if *debugEndpoints {
+			debug := v1.Group("/debug")
+			{
+				// The in-memory store dumps its PeerID -> DID index
+				if *storeType == "memory" {
+					debug.GET("/peers", handler.GetPeerIndex)
+				} else {
+					debug.GET("/peers", handler.GetPeerDIDs)
+				}
+			}
+		}
 	}
 
 	// Root health check
@@ -119,14 +154,54 @@ func setupRoutes(router *gin.Engine, handler *handlers.QuorumHandler) {
 	})
 }
 
-func startCleanupRoutine(store *storage.MemoryStore) {
+// openStore creates the store selected by -store-type, returning a function that closes it
+func openStore() (storage.Store, func(), error) {
+	switch *storeType {
+	case "memory":
+		store := storage.NewMemoryStore()
+		if err := store.SetEmptyTokensMeans(*emptyTokens); err != nil {
+			return nil, nil, err
+		}
+		if err := store.SetBalancePrecision(*precision); err != nil {
+			return nil, nil, err
+		}
+		if err := store.SetAvailabilityWindow(*window); err != nil {
+			return nil, nil, err
+		}
+		return store, func() {}, nil
+	case "redis":
+		store, err := storage.NewRedisStore(*redisURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := store.SetEmptyTokensMeans(*emptyTokens); err != nil {
+			store.Close()
+			return nil, nil, err
+		}
+		if err := store.SetBalancePrecision(*precision); err != nil {
+			store.Close()
+			return nil, nil, err
+		}
+		if err := store.SetAvailabilityWindow(*window); err != nil {
+			store.Close()
+			return nil, nil, err
+		}
+		return store, func() { store.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown store type %q: use memory or redis", *storeType)
+	}
+}
+
+func startCleanupRoutine(store storage.Store) {
 	ticker := time.NewTicker(5 * time.Minute)
 	defer ticker.Stop()
 
 	for {
 		<-ticker.C
-		removed := store.CleanupStaleQuorums()
-		if removed > 0 {
+		removed, err := store.CleanupStaleQuorums()
+		if err != nil {
+			log.Printf("Cleanup failed: %v\n", err)
+		} else if removed > 0 {
 			log.Printf("Cleaned up %d stale quorums\n", removed)
 		}
 	}
@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// Registry holds every advisory node metric. It is shared by the scrape endpoint and
+// the pushgateway pusher so both expose identical metric definitions.
+var Registry = prometheus.NewRegistry()
+
+// Quorum pool gauges
+var (
+	QuorumsTotal = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "advisory_quorums_total",
+		Help: "Number of registered quorums.",
+	})
+	QuorumsAvailable = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "advisory_quorums_available",
+		Help: "Number of quorums that are available and recently pinged.",
+	})
+)
+
+// Request counters
+var (
+	RegisterRequests = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "advisory_register_requests_total",
+		Help: "Number of quorum registration requests received.",
+	})
+	AvailableRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "advisory_available_requests_total",
+		Help: "Number of quorum selection requests by outcome (success, insufficient, throttled or error).",
+	}, []string{"result"})
+)
+
+// RateLimitedRequests counts requests refused by a per-client rate limit
+var RateLimitedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "advisory_rate_limited_requests_total",
+	Help: "Number of requests answered with 429 by a per-client rate limit, by bucket (write or query).",
+}, []string{"bucket"})
+
+// IdempotentReplays counts requests answered from the idempotency cache
+var IdempotentReplays = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "advisory_idempotent_replays_total",
+	Help: "Number of requests retried with an Idempotency-Key and answered with the stored response.",
+})
+
+// RequestDuration times HTTP requests per route
+var RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "advisory_request_duration_seconds",
+	Help:    "Duration of HTTP requests by route.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"handler"})
+
+// Webhook delivery counters
+var (
+	WebhookDeliveryFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "advisory_webhook_delivery_failures_total",
+		Help: "Number of failed webhook delivery attempts, including ones later retried.",
+	})
+	WebhookDeadLetters = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "advisory_webhook_dead_letters_total",
+		Help: "Number of webhook events abandoned after exhausting retries or finding the queue full.",
+	})
+)
+
+// AuditRecordsDropped counts selection audit records that never reached the sink
+var AuditRecordsDropped = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "advisory_audit_records_dropped_total",
+	Help: "Number of selection audit records dropped because the buffer was full or the sink rejected them.",
+})
+
+// OperationDuration times store operations such as quorum selection
+var OperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "advisory_operation_duration_seconds",
+	Help:    "Duration of store operations such as quorum selection.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"operation"})
+
+// AssignmentRebalances counts corrective decays applied to skewed assignment counts
+var AssignmentRebalances = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "advisory_assignment_rebalances_total",
+	Help: "Number of forced rebalances applied to a namespace whose assignment counts were too skewed.",
+}, []string{"namespace"})
+
+// CleanupFailures counts stale-quorum cleanup runs that failed, such as on a broken
+// database connection
+var CleanupFailures = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "advisory_cleanup_failures_total",
+	Help: "Number of stale-quorum cleanup runs that failed.",
+})
+
+func init() {
+	Registry.MustRegister(
+		QuorumsTotal,
+		QuorumsAvailable,
+		RegisterRequests,
+		AvailableRequests,
+		RequestDuration,
+		RateLimitedRequests,
+		IdempotentReplays,
+		WebhookDeliveryFailures,
+		WebhookDeadLetters,
+		AuditRecordsDropped,
+		AssignmentRebalances,
+		CleanupFailures,
+		OperationDuration,
+	)
+}
+
+// RegisterDBPool exports the database connection pool statistics returned by stats,
+// read at each scrape or push
+func RegisterDBPool(stats func() sql.DBStats) {
+	gauge := func(name, help string, value func(sql.DBStats) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, func() float64 {
+			return value(stats())
+		})
+	}
+	counter := func(name, help string, value func(sql.DBStats) float64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{Name: name, Help: help}, func() float64 {
+			return value(stats())
+		})
+	}
+
+	Registry.MustRegister(
+		gauge("advisory_db_max_open_connections", "Maximum open database connections (0 for unlimited).",
+			func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }),
+		gauge("advisory_db_open_connections", "Open database connections, in use or idle.",
+			func(s sql.DBStats) float64 { return float64(s.OpenConnections) }),
+		gauge("advisory_db_in_use_connections", "Database connections currently in use.",
+			func(s sql.DBStats) float64 { return float64(s.InUse) }),
+		gauge("advisory_db_idle_connections", "Idle database connections.",
+			func(s sql.DBStats) float64 { return float64(s.Idle) }),
+		counter("advisory_db_wait_count_total", "Number of times a request waited for a free database connection.",
+			func(s sql.DBStats) float64 { return float64(s.WaitCount) }),
+		counter("advisory_db_wait_duration_seconds_total", "Time spent waiting for a free database connection.",
+			func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }),
+	)
+}
+
+// UpdatePoolGauges refreshes the pool gauges from a health snapshot
+func UpdatePoolGauges(health models.HealthStatus) {
+	QuorumsTotal.Set(float64(health.TotalQuorums))
+	QuorumsAvailable.Set(float64(health.AvailableQuorums))
+}
+
+// Handler serves Registry in the Prometheus exposition format for scraping
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// RunPusher periodically pushes Registry to a Prometheus pushgateway, for deployments
+// that can't be scraped. refresh (optional) runs before each push to update gauges.
+// It blocks until ctx is cancelled.
+func RunPusher(ctx context.Context, gatewayURL string, interval time.Duration, refresh func()) {
+	instance, err := os.Hostname()
+	if err != nil || instance == "" {
+		instance = "advisory-node"
+	}
+
+	pusher := push.New(gatewayURL, "advisory_node").
+		Gatherer(Registry).
+		Grouping("instance", instance)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if refresh != nil {
+				refresh()
+			}
+			if err := pusher.PushContext(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("⚠️  Failed to push metrics to %s: %v\n", gatewayURL, err)
+			}
+		}
+	}
+}
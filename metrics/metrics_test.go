@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunPusherPushesOnInterval(t *testing.T) {
+	type push struct {
+		method, path string
+		body         []byte
+		at           time.Time
+	}
+	pushes := make(chan push, 16)
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		pushes <- push{r.Method, r.URL.Path, body, time.Now()}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer gateway.Close()
+
+	var refreshes atomic.Int32
+	refresh := func() {
+		refreshes.Add(1)
+		QuorumsTotal.Set(7)
+	}
+
+	const interval = 50 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	start := time.Now()
+	go func() {
+		RunPusher(ctx, gateway.URL, interval, refresh)
+		close(done)
+	}()
+
+	var received []push
+	for len(received) < 2 {
+		select {
+		case p := <-pushes:
+			received = append(received, p)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("got %d pushes, want 2", len(received))
+		}
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunPusher didn't return after cancel")
+	}
+
+	// Nothing is pushed before the first tick, and each push waits for the next one
+	if received[0].at.Sub(start) < interval {
+		t.Errorf("first push after %v, want at least one interval (%v)", received[0].at.Sub(start), interval)
+	}
+	if gap := received[1].at.Sub(received[0].at); gap < interval/2 {
+		t.Errorf("pushes %v apart, want about %v", gap, interval)
+	}
+	if refreshes.Load() < 2 {
+		t.Errorf("refresh ran %d times, want once per push", refreshes.Load())
+	}
+	for _, p := range received {
+		if p.method != http.MethodPut || !strings.HasPrefix(p.path, "/metrics/job/advisory_node/instance/") {
+			t.Errorf("push = %s %s, want PUT to the advisory_node job", p.method, p.path)
+		}
+		// The payload carries the same metric families the scrape endpoint serves
+		for _, name := range []string{"advisory_quorums_total", "advisory_register_requests_total"} {
+			if !bytes.Contains(p.body, []byte(name)) {
+				t.Errorf("push payload is missing %s", name)
+			}
+		}
+	}
+}
@@ -12,43 +12,107 @@ const (
 	StandardDIDMode
 )
 
+// DefaultNamespace is the pool quorums and requests belong to when no namespace is given
+const DefaultNamespace = "default"
+
 // QuorumRegistrationRequest represents the request to register a quorum
 type QuorumRegistrationRequest struct {
-	DID             string   `json:"did" binding:"required"`
-	PeerID          string   `json:"peer_id" binding:"required"`
-	Balance         float64  `json:"balance"`
-	DIDType         int      `json:"did_type" binding:"required"`
-	SupportedTokens []string `json:"supported_tokens"` // List of supported token types (e.g., ["RBT", "TRI"])
+	DID             string            `json:"did" binding:"required"`
+	PeerID          string            `json:"peer_id" binding:"required"`
+	Balance         float64           `json:"balance"`
+	DIDType         int               `json:"did_type" binding:"required"`
+	SupportedTokens []string          `json:"supported_tokens"`    // List of supported token types (e.g., ["RBT", "TRI"])
+	Capabilities    []string          `json:"capabilities"`        // Optional: capability flags (e.g., ["supports-lite"])
+	Region          string            `json:"region"`              // Optional: deployment region tag (e.g., "eu-west")
+	Tier            string            `json:"tier,omitempty"`      // Optional: collateral tier (e.g., "gold")
+	Namespace       string            `json:"namespace,omitempty"` // Optional: logical network the quorum serves (default "default")
+	Metadata        map[string]string `json:"metadata,omitempty"`  // Optional: free-form operator labels
+
+	// Optional: balance the node keeps in reserve; only balance above it counts toward selection
+	MinParticipationBalance float64 `json:"min_participation_balance"`
+
+	// Optional: largest per-quorum required balance the node will back; it is skipped for
+	// transactions needing more, whatever its balance (0 means no cap)
+	MaxTransactionShare float64 `json:"max_transaction_share"`
+
+	// Optional: base64 Ed25519 public key. Once registered, registration, heartbeat and
+	// balance requests for the DID must be signed by it (X-Quorum-Signature header)
+	PublicKey string `json:"public_key,omitempty"`
+
+	// Required only to re-register a DID deregistered with a signed tombstone: a base64
+	// Ed25519 signature over "register:<did>:<signed_at>" by the tombstone's key
+	Signature string `json:"signature,omitempty"`
+	SignedAt  int64  `json:"signed_at,omitempty"` // Unix seconds
+}
+
+// UnregisterSignature is the optional body of DELETE /api/quorum/unregister/:did that
+// deregisters with a signed tombstone
+type UnregisterSignature struct {
+	PublicKey string `json:"public_key" binding:"required"` // Base64 Ed25519 public key
+	SignedAt  int64  `json:"signed_at" binding:"required"`  // Unix seconds
+	Signature string `json:"signature" binding:"required"`  // Base64 signature over "unregister:<did>:<signed_at>"
 }
 
 // QuorumInfo represents a registered quorum with additional metadata
 type QuorumInfo struct {
-	DID              string    `json:"did"`
-	PeerID           string    `json:"peer_id"`
-	Balance          float64   `json:"balance"`
-	DIDType          int       `json:"did_type"`
-	Available        bool      `json:"available"`
-	LastPing         time.Time `json:"last_ping"`
-	AssignmentCount  int       `json:"assignment_count"`
-	LastAssignment   time.Time `json:"last_assignment"`
-	RegistrationTime time.Time `json:"registration_time"`
-	SupportedTokens  []string  `json:"supported_tokens"` // List of supported token types
+	DID              string            `json:"did"`
+	PeerID           string            `json:"peer_id"`
+	Balance          float64           `json:"balance"`
+	DIDType          int               `json:"did_type"`
+	Available        bool              `json:"available"`
+	ManuallyDisabled bool              `json:"manually_disabled,omitempty"` // Taken out of rotation by an operator
+	LastPing         time.Time         `json:"last_ping"`
+	AssignmentCount  int               `json:"assignment_count"`
+	LastAssignment   time.Time         `json:"last_assignment"`
+	RegistrationTime time.Time         `json:"registration_time"`
+	SupportedTokens  []string          `json:"supported_tokens"` // List of supported token types
+	Capabilities     []string          `json:"capabilities,omitempty"`
+	Region           string            `json:"region,omitempty"`
+	Tier             string            `json:"tier,omitempty"`
+	Namespace        string            `json:"namespace"`
+	Metadata         map[string]string `json:"metadata,omitempty"`
+
+	MinParticipationBalance float64 `json:"min_participation_balance"`
+	MaxTransactionShare     float64 `json:"max_transaction_share,omitempty"`
+
+	// Derived: when the quorum stops being selectable unless it pings again (last_ping + heartbeat timeout)
+	NextHeartbeatDeadline time.Time `json:"next_heartbeat_deadline"`
 }
 
 // QuorumListRequest represents a request to get available quorums
 type QuorumListRequest struct {
-	Count             int     `json:"count"`              // Number of quorums needed (default 7)
-	LastCharTID       string  `json:"last_char_tid"`      // Optional: for type-1 quorum selection
-	Type              int     `json:"type"`               // Quorum type (1 or 2)
-	TransactionAmount float64 `json:"transaction_amount"` // Transaction amount for balance validation
-	FTName            string  `json:"ft_name"`            // Token type for filtering (e.g., "TRI", "RBT")
+	Count             int      `json:"count"`               // Number of quorums needed (default 7)
+	LastCharTID       string   `json:"last_char_tid"`       // Optional: for type-1 quorum selection
+	Type              int      `json:"type"`                // Quorum type (1 or 2)
+	TransactionAmount float64  `json:"transaction_amount"`  // Transaction amount for balance validation
+	FTName            string   `json:"ft_name"`             // Token type for filtering (e.g., "TRI", "RBT")
+	MinRegions        int      `json:"min_regions"`         // Optional: minimum number of distinct regions in the selected set
+	Region            string   `json:"region"`              // Optional: preferred region; other regions only fill what it can't
+	TransactionID     string   `json:"transaction_id"`      // Optional: caller-supplied ID recorded in transaction history
+	Requires          []string `json:"requires"`            // Optional: capabilities every selected quorum must have
+	MinSuccessfulTxns int64    `json:"min_successful_txns"` // Optional: minimum successfully completed transactions per quorum
+	MinTier           string   `json:"min_tier"`            // Optional: lowest collateral tier eligible for selection
+	Namespace         string   `json:"namespace"`           // Logical network to select from (default "default")
+	DIDType           *int     `json:"did_type,omitempty"`  // Optional: only quorums of this DID type
 }
 
 // QuorumListResponse represents the response with available quorums
 type QuorumListResponse struct {
-	Status  bool         `json:"status"`
-	Message string       `json:"message"`
-	Quorums []QuorumData `json:"quorums"`
+	Status        bool         `json:"status"`
+	Message       string       `json:"message"`
+	Quorums       []QuorumData `json:"quorums"`
+	TransactionID string       `json:"transaction_id,omitempty"`
+
+	// Quorums asked for (count, after defaulting) and actually returned, so short
+	// responses are self-describing
+	RequestedCount int `json:"requested_count"`
+	DeliveredCount int `json:"delivered_count"`
+}
+
+// EligibleCountResponse reports how many quorums a selection would choose from
+type EligibleCountResponse struct {
+	Eligible        int64   `json:"eligible"`
+	RequiredBalance float64 `json:"required_balance"` // Per quorum, after any minimum quorum balance
 }
 
 // QuorumData represents the quorum data format expected by RubixGo
@@ -57,11 +121,463 @@ type QuorumData struct {
 	Address string `json:"address"` // Format: "PeerID.DID"
 }
 
+// SelectedQuorum is QuorumData plus the details selection ranked the quorum by,
+// returned by GET /api/quorum/available?verbose=true
+type SelectedQuorum struct {
+	Type            int     `json:"type"`
+	Address         string  `json:"address"` // Format: "PeerID.DID"
+	DID             string  `json:"did"`
+	Balance         float64 `json:"balance"`
+	AssignmentCount int     `json:"assignment_count"` // Before this selection's assignment
+	DIDType         int     `json:"did_type"`
+	Region          string  `json:"region,omitempty"`
+}
+
+// VerboseQuorumListResponse is QuorumListResponse with SelectedQuorum entries
+type VerboseQuorumListResponse struct {
+	Status         bool             `json:"status"`
+	Message        string           `json:"message"`
+	Quorums        []SelectedQuorum `json:"quorums"`
+	TransactionID  string           `json:"transaction_id,omitempty"`
+	RequestedCount int              `json:"requested_count"`
+	DeliveredCount int              `json:"delivered_count"`
+}
+
+// SetAvailabilityRequest takes a quorum out of rotation or puts it back
+type SetAvailabilityRequest struct {
+	DID       string `json:"did" binding:"required"`
+	Available *bool  `json:"available" binding:"required"`
+}
+
+// QuorumReplaceRequest represents a request to replace a failed quorum in an assigned set
+type QuorumReplaceRequest struct {
+	TransactionID string   `json:"transaction_id" binding:"required"`
+	FailedDID     string   `json:"failed_did" binding:"required"`
+	Exclude       []string `json:"exclude"` // DIDs of the current set that must not be returned
+	FTName        string   `json:"ft_name"` // Optional: token type the replacement must support
+}
+
+// TransactionCompleteRequest reports how a transaction that was handed quorums ended
+type TransactionCompleteRequest struct {
+	TransactionID string `json:"transaction_id" binding:"required,max=255"`
+	Status        string `json:"status" binding:"required,oneof=success failed"`
+	// On failure, take back the assignment count each assigned quorum was charged
+	DecrementAssignments bool `json:"decrement_assignments"`
+}
+
+// TransactionCompletion is the recorded outcome of a transaction
+type TransactionCompletion struct {
+	TransactionID string    `json:"transaction_id"`
+	Status        string    `json:"status"`
+	CompletedAt   time.Time `json:"completed_at"`
+	QuorumDIDs    []string  `json:"quorum_dids"`
+	Decremented   int64     `json:"decremented"` // Assignment counts taken back
+}
+
+// QuorumReserveRequest holds an assigned set for a transaction while it runs consensus
+type QuorumReserveRequest struct {
+	TransactionID     string   `json:"transaction_id" binding:"required,max=255"`
+	DIDs              []string `json:"dids" binding:"required"`
+	TransactionAmount float64  `json:"transaction_amount" binding:"required"`
+	TTLSeconds        int      `json:"ttl_seconds"` // Defaults to 300, at most 3600
+}
+
+// QuorumReleaseRequest frees the quorums a transaction reserved
+type QuorumReleaseRequest struct {
+	TransactionID string `json:"transaction_id" binding:"required"`
+}
+
+// ScheduledReservationRequest asks for a quorum set to be held for a future time window
+type ScheduledReservationRequest struct {
+	TransactionAmount float64   `json:"transaction_amount" binding:"required"`
+	Count             int       `json:"count"` // Defaults to 7
+	Start             time.Time `json:"start" binding:"required"`
+	End               time.Time `json:"end" binding:"required"`
+	FTName            string    `json:"ft_name"`   // Optional: token type the set must support
+	Namespace         string    `json:"namespace"` // Defaults to the default namespace
+}
+
+// ScheduledReservation is a quorum set held out of other selections between Start and End
+type ScheduledReservation struct {
+	ReservationID   string       `json:"reservation_id"`
+	Start           time.Time    `json:"start"`
+	End             time.Time    `json:"end"`
+	RequiredBalance float64      `json:"required_balance"`
+	Namespace       string       `json:"namespace"`
+	Quorums         []QuorumData `json:"quorums"`
+}
+
+// WebhookSubscriptionRequest subscribes a URL to quorum lifecycle events
+type WebhookSubscriptionRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Events []string `json:"events" binding:"required"` // quorum_stale, quorum_registered, quorum_unregistered
+}
+
+// WebhookSubscription is a URL receiving the listed events as JSON POSTs
+type WebhookSubscription struct {
+	ID        uint      `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// APIKeyRequest creates an API key
+type APIKeyRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required"` // read, write, admin
+}
+
+// APIKeyInfo describes an API key without the key itself
+type APIKeyInfo struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Prefix    string    `json:"prefix"` // First characters of the key
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EligibilityAttestation is a point-in-time snapshot of the selection-eligible quorum set
+type EligibilityAttestation struct {
+	TransactionAmount float64      `json:"transaction_amount"`
+	Count             int          `json:"count"`
+	RequiredBalance   float64      `json:"required_balance"`
+	Quorums           []QuorumData `json:"quorums"` // Sorted by DID
+	Timestamp         time.Time    `json:"timestamp"`
+}
+
+// SignedAttestation is an attestation together with the server's signature over Payload
+type SignedAttestation struct {
+	Attestation EligibilityAttestation `json:"attestation"`
+	Payload     string                 `json:"payload"`   // Base64 of the exact signed JSON bytes
+	Signature   string                 `json:"signature"` // Base64 signature over the decoded payload
+	Algorithm   string                 `json:"algorithm"`
+	PublicKey   string                 `json:"public_key"` // Base64 public key of the signing server
+}
+
+// QuorumUsage is a quorum's accumulated assignment statistics
+type QuorumUsage struct {
+	DID                    string    `json:"did"`
+	TotalTransactions      int64     `json:"total_transactions"`
+	SuccessfulTransactions int64     `json:"successful_transactions"`
+	TotalAmount            float64   `json:"total_amount"` // Sum of the per-quorum shares it was assigned
+	LastActive             time.Time `json:"last_active"`  // Last assignment; zero if never assigned
+}
+
+// BalanceChange is one recorded change of a quorum's balance
+type BalanceChange struct {
+	OldBalance float64   `json:"old_balance"`
+	NewBalance float64   `json:"new_balance"`
+	Reason     string    `json:"reason"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// TransactionRecord is one recorded quorum assignment
+type TransactionRecord struct {
+	TransactionID     string     `json:"transaction_id"`
+	TransactionAmount float64    `json:"transaction_amount"`
+	QuorumDIDs        []string   `json:"quorum_dids"`
+	RequiredBalance   float64    `json:"required_balance"`
+	Namespace         string     `json:"namespace"`
+	Timestamp         time.Time  `json:"timestamp"`
+	Status            string     `json:"status,omitempty"` // success or failed once reported
+	CompletedAt       *time.Time `json:"completed_at,omitempty"`
+}
+
+// QuorumMetrics are the figures operators weigh when comparing quorums
+type QuorumMetrics struct {
+	DID                    string    `json:"did"`
+	Balance                float64   `json:"balance"`
+	AssignmentCount        int       `json:"assignment_count"`
+	TotalTransactions      int64     `json:"total_transactions"`
+	SuccessfulTransactions int64     `json:"successful_transactions"`
+	Reliability            *float64  `json:"reliability"`  // Successful / total transactions; null before any
+	UptimeRatio            *float64  `json:"uptime_ratio"` // Share of time available since registration; null without events
+	Available              bool      `json:"available"`
+	LastPing               time.Time `json:"last_ping"`
+	RegistrationTime       time.Time `json:"registration_time"`
+	SupportedTokens        []string  `json:"supported_tokens"`
+}
+
+// QuorumComparison puts two quorums' metrics side by side
+type QuorumComparison struct {
+	A QuorumMetrics `json:"a"`
+	B QuorumMetrics `json:"b"`
+}
+
+// TimelineEvent is one entry in a quorum's merged activity timeline
+type TimelineEvent struct {
+	Timestamp         time.Time `json:"timestamp"`
+	Source            string    `json:"source"` // balance, availability or transaction
+	Event             string    `json:"event"`
+	OldBalance        *float64  `json:"old_balance,omitempty"`
+	NewBalance        *float64  `json:"new_balance,omitempty"`
+	TransactionID     string    `json:"transaction_id,omitempty"`
+	TransactionAmount float64   `json:"transaction_amount,omitempty"`
+	Token             string    `json:"token,omitempty"`
+}
+
+// SelectionFailure records a selection request that could not be served
+type SelectionFailure struct {
+	Timestamp         time.Time `json:"timestamp"`
+	TransactionID     string    `json:"transaction_id,omitempty"`
+	RequestedCount    int       `json:"requested_count"`
+	TransactionAmount float64   `json:"transaction_amount"`
+	Token             string    `json:"token"`
+	Namespace         string    `json:"namespace"`
+	Code              string    `json:"code"` // INSUFFICIENT_QUORUMS or POOL_NOT_READY
+	Reason            string    `json:"reason"`
+}
+
+// ValidationCheck is the outcome of one registration check
+type ValidationCheck struct {
+	Check   string `json:"check"`
+	Passed  bool   `json:"passed"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// RegistrationValidation is the response of POST /api/quorum/validate
+type RegistrationValidation struct {
+	Status     bool                       `json:"status"`
+	Valid      bool                       `json:"valid"` // Whether /register would accept the payload
+	Message    string                     `json:"message"`
+	Checks     []ValidationCheck          `json:"checks"`
+	Normalized *QuorumRegistrationRequest `json:"normalized,omitempty"` // Payload as it would be stored, when valid
+}
+
+// ImportLineError reports why one line of a bulk import was rejected
+type ImportLineError struct {
+	Line    int    `json:"line"`
+	DID     string `json:"did,omitempty"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ImportSummary is the response of POST /api/quorum/import
+type ImportSummary struct {
+	Status          bool              `json:"status"`
+	Message         string            `json:"message"`
+	DryRun          bool              `json:"dry_run"`
+	Total           int               `json:"total"`     // Non-blank lines read
+	Succeeded       int               `json:"succeeded"` // Registered, or valid in a dry run
+	Failed          int               `json:"failed"`
+	Errors          []ImportLineError `json:"errors,omitempty"`
+	ErrorsTruncated bool              `json:"errors_truncated,omitempty"` // Errors lists only the first 100 failed lines
+}
+
+// PoolExport is the document GET /api/admin/export returns and POST /api/admin/import
+// restores, for backups and for moving a pool between databases
+type PoolExport struct {
+	FormatVersion  int                     `json:"format_version"`
+	ExportedAt     time.Time               `json:"exported_at"`
+	Quorums        []ExportedQuorum        `json:"quorums"`
+	Stats          []QuorumUsage           `json:"stats,omitempty"`           // Only with ?include=stats
+	BalanceHistory []ExportedBalanceChange `json:"balance_history,omitempty"` // Only with ?include=balance_history
+}
+
+// ExportedQuorum is a quorum's registration plus the state the service keeps for it, so
+// a restored quorum keeps its place in load balancing and its heartbeat age
+type ExportedQuorum struct {
+	QuorumRegistrationRequest
+	Available        bool      `json:"available"`
+	ManuallyDisabled bool      `json:"manually_disabled,omitempty"`
+	AssignmentCount  int64     `json:"assignment_count"`
+	LastPing         time.Time `json:"last_ping"`
+	LastAssignment   time.Time `json:"last_assignment"`
+	RegistrationTime time.Time `json:"registration_time"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// ExportedBalanceChange is one recorded balance change of the quorum DID
+type ExportedBalanceChange struct {
+	DID string `json:"did"`
+	BalanceChange
+}
+
+// PoolImportSummary is the response of POST /api/admin/import
+type PoolImportSummary struct {
+	Status         bool   `json:"status"`
+	Message        string `json:"message"`
+	Mode           string `json:"mode"`
+	Created        int    `json:"created"`         // Quorums not registered before
+	Updated        int    `json:"updated"`         // Registered quorums overwritten from the document
+	Removed        int    `json:"removed"`         // Quorums missing from the document, unregistered in replace mode
+	Stats          int    `json:"stats"`           // Stats rows restored
+	BalanceChanges int    `json:"balance_changes"` // Balance history entries restored
+}
+
+// BatchRegistrationResult reports the outcome of one entry of a batch registration
+type BatchRegistrationResult struct {
+	Index   int    `json:"index"` // Position in the request array
+	DID     string `json:"did,omitempty"`
+	Status  bool   `json:"status"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// BatchRegistrationResponse is the response of POST /api/quorum/register-batch
+type BatchRegistrationResponse struct {
+	Status    bool                      `json:"status"`
+	Message   string                    `json:"message"`
+	Total     int                       `json:"total"`
+	Succeeded int                       `json:"succeeded"`
+	Failed    int                       `json:"failed"`
+	Results   []BatchRegistrationResult `json:"results"`
+}
+
+// NearEligibleQuorum is a quorum that misses selection only because of its balance
+type NearEligibleQuorum struct {
+	DID                     string  `json:"did"`
+	PeerID                  string  `json:"peer_id"`
+	Balance                 float64 `json:"balance"`
+	MinParticipationBalance float64 `json:"min_participation_balance"`
+	RequiredBalance         float64 `json:"required_balance"`
+	Shortfall               float64 `json:"shortfall"` // Top-up needed to become eligible
+}
+
+// DiagnosisStage reports how many quorums remain after one selection filter stage
+type DiagnosisStage struct {
+	Stage       string `json:"stage"`
+	Description string `json:"description"`
+	Remaining   int64  `json:"remaining"`
+	Excluded    int64  `json:"excluded"` // Quorums dropped by this stage
+}
+
+// AvailabilityDiagnosis is a read-only breakdown of why a selection can or can't be satisfied
+type AvailabilityDiagnosis struct {
+	TotalQuorums    int64            `json:"total_quorums"`
+	RequestedCount  int              `json:"requested_count"`
+	RequiredBalance float64          `json:"required_balance"`
+	Stages          []DiagnosisStage `json:"stages"`
+	EligibleCount   int64            `json:"eligible_count"`
+	Satisfiable     bool             `json:"satisfiable"`
+}
+
+// Final decisions of a dry-run selection, per quorum
+const (
+	DecisionSelected    = "selected"     // Would be assigned
+	DecisionNotSelected = "not_selected" // Passed every gate but ranked below the ones selected
+	DecisionExcluded    = "excluded"     // Failed at least one gate
+)
+
+// GateResult is whether a quorum passed one selection stage
+type GateResult struct {
+	Gate   string `json:"gate"`
+	Passed bool   `json:"passed"`
+}
+
+// GateSummary counts the quorums passing one selection stage, each stage judged on its own
+type GateSummary struct {
+	Gate        string `json:"gate"`
+	Description string `json:"description"`
+	Passed      int    `json:"passed"`
+}
+
+// QuorumExplanation is how a dry-run selection treated one quorum
+type QuorumExplanation struct {
+	DID             string       `json:"did"`
+	Decision        string       `json:"decision"`
+	Rank            int          `json:"rank,omitempty"` // Place in selection order among quorums passing every gate, from 1
+	Gates           []GateResult `json:"gates"`
+	FailedGates     []string     `json:"failed_gates,omitempty"`
+	Balance         float64      `json:"balance"`
+	AssignmentCount int64        `json:"assignment_count"`
+	LastPing        time.Time    `json:"last_ping"`
+	Region          string       `json:"region,omitempty"`
+}
+
+// SelectionExplanation is a read-only, per-quorum account of what a selection would do
+type SelectionExplanation struct {
+	RequestedCount  int                 `json:"requested_count"`
+	RequiredBalance float64             `json:"required_balance"`
+	TransactionID   string              `json:"transaction_id"` // Seeds the tie-break between equally loaded quorums
+	Namespace       string              `json:"namespace"`
+	TotalQuorums    int                 `json:"total_quorums"` // Quorums in the namespace
+	Gates           []GateSummary       `json:"gates"`
+	EligibleCount   int                 `json:"eligible_count"`
+	SelectedCount   int                 `json:"selected_count"`
+	Satisfiable     bool                `json:"satisfiable"`
+	Error           string              `json:"error,omitempty"` // Why the selection would fail
+	Quorums         []QuorumExplanation `json:"quorums"`
+	Truncated       bool                `json:"truncated"` // More quorums than the limit were evaluated
+}
+
+// EligibilityForecast estimates when the pool will satisfy a selection, from the net
+// change in eligible quorums over a recent window
+type EligibilityForecast struct {
+	RequestedCount  int     `json:"requested_count"`
+	RequiredBalance float64 `json:"required_balance"`
+	EligibleCount   int64   `json:"eligible_count"`
+	Needed          int64   `json:"needed"` // Further eligible quorums required; 0 when satisfiable now
+
+	WindowHours   float64 `json:"window_hours"`
+	Gained        int64   `json:"gained"`
+	Lost          int64   `json:"lost"`
+	GrowthPerHour float64 `json:"growth_per_hour"`
+
+	// Time until the pool is expected to satisfy the request; null while it isn't growing
+	ETASeconds  *int64     `json:"eta_seconds"`
+	EstimatedAt *time.Time `json:"estimated_at"`
+}
+
 // ConfirmAvailabilityRequest represents the request to confirm quorum availability
 type ConfirmAvailabilityRequest struct {
 	DID string `json:"did" binding:"required"`
 }
 
+// FreezeStalenessRequest represents the request to suspend staleness checks for maintenance
+type FreezeStalenessRequest struct {
+	Until time.Time `json:"until" binding:"required"`
+}
+
+// MaintenanceRequest switches read-only maintenance mode on or off
+type MaintenanceRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// ResetAssignmentsRequest selects the quorum whose assignment count to reset; an empty
+// DID, or no request body, resets every quorum
+type ResetAssignmentsRequest struct {
+	DID string `json:"did"`
+}
+
+// BalanceSafetyRequest represents the request to change the balance safety multiplier
+type BalanceSafetyRequest struct {
+	Multiplier float64 `json:"multiplier" binding:"required"`
+}
+
+// HeartbeatRequest represents a heartbeat, optionally carrying updated quorum details
+// that are applied atomically with the ping
+type HeartbeatRequest struct {
+	DID      string            `json:"did" binding:"required"`
+	Balance  *float64          `json:"balance,omitempty"`
+	Region   *string           `json:"region,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// HasDetails reports whether the heartbeat carries any quorum updates besides the ping
+func (r *HeartbeatRequest) HasDetails() bool {
+	return r.Balance != nil || r.Region != nil || r.Metadata != nil
+}
+
+// HeartbeatBatchRequest pings many quorums at once
+type HeartbeatBatchRequest struct {
+	DIDs []string `json:"dids" binding:"required"`
+}
+
+// HeartbeatBatchResponse reports which DIDs of a heartbeat batch were updated. Missing
+// DIDs aren't registered and should be registered again; invalid ones are malformed,
+// and keyed ones have a public_key and must send signed heartbeats one at a time.
+type HeartbeatBatchResponse struct {
+	Status  bool     `json:"status"`
+	Message string   `json:"message"`
+	Updated int      `json:"updated"`
+	Found   []string `json:"found"`
+	Missing []string `json:"missing"`
+	Invalid []string `json:"invalid,omitempty"`
+	Keyed   []string `json:"keyed,omitempty"`
+}
+
 // HealthStatus represents the health status of the advisory node
 type HealthStatus struct {
 	Status           string    `json:"status"`
@@ -69,6 +585,32 @@ type HealthStatus struct {
 	AvailableQuorums int       `json:"available_quorums"`
 	Uptime           string    `json:"uptime"`
 	LastCheck        time.Time `json:"last_check"`
+
+	// Set only when a read replica is configured
+	ReplicationLagSeconds *float64 `json:"replication_lag_seconds,omitempty"`
+	ReplicaError          string   `json:"replica_error,omitempty"`
+
+	ConnectionPool *ConnectionPoolStats `json:"connection_pool,omitempty"` // Primary database
+
+	Maintenance bool `json:"maintenance,omitempty"` // Writes are being refused (read-only mode)
+
+	// Available quorums supporting each token, keyed by token name
+	Tokens map[string]TokenAvailability `json:"tokens,omitempty"`
+}
+
+// TokenAvailability reports the pool for one token in HealthStatus
+type TokenAvailability struct {
+	Available int `json:"available"`
+}
+
+// ConnectionPoolStats reports database connection pool usage, for tuning the pool limits
+type ConnectionPoolStats struct {
+	MaxOpen        int   `json:"max_open"` // 0 means unlimited
+	Open           int   `json:"open"`
+	InUse          int   `json:"in_use"`
+	Idle           int   `json:"idle"`
+	WaitCount      int64 `json:"wait_count"`       // Total waits for a free connection
+	WaitDurationMs int64 `json:"wait_duration_ms"` // Total time spent waiting
 }
 
 // BasicResponse represents a basic API response
@@ -76,3 +618,55 @@ type BasicResponse struct {
 	Status  bool   `json:"status"`
 	Message string `json:"message"`
 }
+
+// API error codes reported in the v2 response envelope
+const (
+	ErrCodeInvalidRequest        = "INVALID_REQUEST"
+	ErrCodeBodyTooLarge          = "REQUEST_TOO_LARGE"
+	ErrCodeInvalidDID            = "INVALID_DID"
+	ErrCodeInvalidDIDType        = "INVALID_DID_TYPE"
+	ErrCodeInvalidPeerID         = "INVALID_PEER_ID"
+	ErrCodeInvalidRegion         = "INVALID_REGION"
+	ErrCodeInvalidMetadata       = "INVALID_METADATA"
+	ErrCodeInvalidCapability     = "INVALID_CAPABILITY"
+	ErrCodeInvalidTier           = "INVALID_TIER"
+	ErrCodeInvalidNamespace      = "INVALID_NAMESPACE"
+	ErrCodeInvalidSignature      = "INVALID_SIGNATURE"
+	ErrCodeInvalidPublicKey      = "INVALID_PUBLIC_KEY"
+	ErrCodeTombstoned            = "DID_TOMBSTONED"
+	ErrCodeTooManyTokens         = "TOO_MANY_TOKENS"
+	ErrCodeInvalidToken          = "INVALID_TOKEN"
+	ErrCodeInvalidBalance        = "INVALID_BALANCE"
+	ErrCodeInvalidAmount         = "INVALID_TRANSACTION_AMOUNT"
+	ErrCodeQuorumNotFound        = "QUORUM_NOT_FOUND"
+	ErrCodeQuorumDisabled        = "QUORUM_DISABLED"
+	ErrCodeInsufficientQuorums   = "INSUFFICIENT_QUORUMS"
+	ErrCodePoolNotReady          = "POOL_NOT_READY"
+	ErrCodeSelectionBackoff      = "SELECTION_BACKOFF"
+	ErrCodeRateLimited           = "RATE_LIMITED"
+	ErrCodeMaintenance           = "MAINTENANCE_MODE"
+	ErrCodeUnauthorized          = "UNAUTHORIZED"
+	ErrCodeForbidden             = "FORBIDDEN"
+	ErrCodeIdempotencyKeyReused  = "IDEMPOTENCY_KEY_REUSED"
+	ErrCodeIdempotencyInProgress = "IDEMPOTENCY_KEY_IN_PROGRESS"
+	ErrCodeTransactionNotFound   = "TRANSACTION_NOT_FOUND"
+	ErrCodeQuorumReserved        = "QUORUM_RESERVED"
+	ErrCodeTransactionComplete   = "TRANSACTION_COMPLETED"
+	ErrCodeInvalidWebhook        = "INVALID_WEBHOOK"
+	ErrCodeWebhookNotFound       = "WEBHOOK_NOT_FOUND"
+	ErrCodeAPIKeyNotFound        = "API_KEY_NOT_FOUND"
+	ErrCodeAPIKeyNameTaken       = "API_KEY_NAME_TAKEN"
+	ErrCodeNotImplemented        = "NOT_IMPLEMENTED"
+	ErrCodeInternal              = "INTERNAL_ERROR"
+)
+
+// APIEnvelope is the v2 response shape, selected with
+// "Accept: application/vnd.advisory.v2+json"
+type APIEnvelope struct {
+	APIVersion string      `json:"api_version"`
+	Status     bool        `json:"status"`
+	Message    string      `json:"message,omitempty"`
+	ErrorCode  string      `json:"error_code,omitempty"`
+	Data       interface{} `json:"data,omitempty"`
+	Timestamp  time.Time   `json:"timestamp"`
+}
@@ -31,7 +31,7 @@ PEER_IDS=(
     "12D3KooWRqVnNFYjFKkPFRgHpVjgR8ipLMXqRApnQKkbRq9gVNxc"
     "12D3KooWFGDqgQMFnJHkxjKJrYNq8GpbHsGg3FnQfNbAQkYkWQvM"
     "12D3KooWPjKPqFpLVHJNhg8qVKhGYnXGJkjhQvQ3rBNxYTNjKFvS"
-    "12D3KooWNRxLmKiYg5JrVH4GHKkWSSNMKqh8sYGASK4KjM87LqPv5"
+    "12D3KooWNRxLmKiYg5JrVH4GHKkWSSNMKqh8sYGASK4KjM87LqPv"
 )
 
 # Array of balances (varied amounts)
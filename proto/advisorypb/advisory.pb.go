@@ -0,0 +1,898 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: advisory.proto
+
+package advisorypb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// RegisterQuorumRequest matches the REST registration body. Quorums registered with a
+// public_key sign their requests, which gRPC does not support; they must use REST.
+type RegisterQuorumRequest struct {
+	state                   protoimpl.MessageState `protogen:"open.v1"`
+	Did                     string                 `protobuf:"bytes,1,opt,name=did,proto3" json:"did,omitempty"`
+	PeerId                  string                 `protobuf:"bytes,2,opt,name=peer_id,json=peerId,proto3" json:"peer_id,omitempty"`
+	Balance                 float64                `protobuf:"fixed64,3,opt,name=balance,proto3" json:"balance,omitempty"`
+	DidType                 int32                  `protobuf:"varint,4,opt,name=did_type,json=didType,proto3" json:"did_type,omitempty"`
+	SupportedTokens         []string               `protobuf:"bytes,5,rep,name=supported_tokens,json=supportedTokens,proto3" json:"supported_tokens,omitempty"`
+	Capabilities            []string               `protobuf:"bytes,6,rep,name=capabilities,proto3" json:"capabilities,omitempty"`
+	Region                  string                 `protobuf:"bytes,7,opt,name=region,proto3" json:"region,omitempty"`
+	Tier                    string                 `protobuf:"bytes,8,opt,name=tier,proto3" json:"tier,omitempty"`
+	Namespace               string                 `protobuf:"bytes,9,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Metadata                map[string]string      `protobuf:"bytes,10,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	MinParticipationBalance float64                `protobuf:"fixed64,11,opt,name=min_participation_balance,json=minParticipationBalance,proto3" json:"min_participation_balance,omitempty"`
+	MaxTransactionShare     float64                `protobuf:"fixed64,12,opt,name=max_transaction_share,json=maxTransactionShare,proto3" json:"max_transaction_share,omitempty"`
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *RegisterQuorumRequest) Reset() {
+	*x = RegisterQuorumRequest{}
+	mi := &file_advisory_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterQuorumRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterQuorumRequest) ProtoMessage() {}
+
+func (x *RegisterQuorumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_advisory_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterQuorumRequest.ProtoReflect.Descriptor instead.
+func (*RegisterQuorumRequest) Descriptor() ([]byte, []int) {
+	return file_advisory_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RegisterQuorumRequest) GetDid() string {
+	if x != nil {
+		return x.Did
+	}
+	return ""
+}
+
+func (x *RegisterQuorumRequest) GetPeerId() string {
+	if x != nil {
+		return x.PeerId
+	}
+	return ""
+}
+
+func (x *RegisterQuorumRequest) GetBalance() float64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *RegisterQuorumRequest) GetDidType() int32 {
+	if x != nil {
+		return x.DidType
+	}
+	return 0
+}
+
+func (x *RegisterQuorumRequest) GetSupportedTokens() []string {
+	if x != nil {
+		return x.SupportedTokens
+	}
+	return nil
+}
+
+func (x *RegisterQuorumRequest) GetCapabilities() []string {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *RegisterQuorumRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *RegisterQuorumRequest) GetTier() string {
+	if x != nil {
+		return x.Tier
+	}
+	return ""
+}
+
+func (x *RegisterQuorumRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *RegisterQuorumRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RegisterQuorumRequest) GetMinParticipationBalance() float64 {
+	if x != nil {
+		return x.MinParticipationBalance
+	}
+	return 0
+}
+
+func (x *RegisterQuorumRequest) GetMaxTransactionShare() float64 {
+	if x != nil {
+		return x.MaxTransactionShare
+	}
+	return 0
+}
+
+type ConfirmAvailabilityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Did           string                 `protobuf:"bytes,1,opt,name=did,proto3" json:"did,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmAvailabilityRequest) Reset() {
+	*x = ConfirmAvailabilityRequest{}
+	mi := &file_advisory_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmAvailabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmAvailabilityRequest) ProtoMessage() {}
+
+func (x *ConfirmAvailabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_advisory_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmAvailabilityRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmAvailabilityRequest) Descriptor() ([]byte, []int) {
+	return file_advisory_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ConfirmAvailabilityRequest) GetDid() string {
+	if x != nil {
+		return x.Did
+	}
+	return ""
+}
+
+// GetAvailableQuorumsRequest carries the /available query parameters
+type GetAvailableQuorumsRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Count             int32                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"` // Defaults to 7
+	TransactionAmount float64                `protobuf:"fixed64,2,opt,name=transaction_amount,json=transactionAmount,proto3" json:"transaction_amount,omitempty"`
+	LastCharTid       string                 `protobuf:"bytes,3,opt,name=last_char_tid,json=lastCharTid,proto3" json:"last_char_tid,omitempty"`
+	Type              int32                  `protobuf:"varint,4,opt,name=type,proto3" json:"type,omitempty"` // Defaults to 2
+	FtName            string                 `protobuf:"bytes,5,opt,name=ft_name,json=ftName,proto3" json:"ft_name,omitempty"`
+	TransactionId     string                 `protobuf:"bytes,6,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	Namespace         string                 `protobuf:"bytes,7,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	MinRegions        int32                  `protobuf:"varint,8,opt,name=min_regions,json=minRegions,proto3" json:"min_regions,omitempty"`
+	Requires          []string               `protobuf:"bytes,9,rep,name=requires,proto3" json:"requires,omitempty"`
+	MinTier           string                 `protobuf:"bytes,10,opt,name=min_tier,json=minTier,proto3" json:"min_tier,omitempty"`
+	MinSuccessfulTxns int64                  `protobuf:"varint,11,opt,name=min_successful_txns,json=minSuccessfulTxns,proto3" json:"min_successful_txns,omitempty"`
+	DidType           *int32                 `protobuf:"varint,12,opt,name=did_type,json=didType,proto3,oneof" json:"did_type,omitempty"`
+	Region            string                 `protobuf:"bytes,13,opt,name=region,proto3" json:"region,omitempty"` // Preferred region; other regions only fill what it can't
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *GetAvailableQuorumsRequest) Reset() {
+	*x = GetAvailableQuorumsRequest{}
+	mi := &file_advisory_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAvailableQuorumsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAvailableQuorumsRequest) ProtoMessage() {}
+
+func (x *GetAvailableQuorumsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_advisory_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAvailableQuorumsRequest.ProtoReflect.Descriptor instead.
+func (*GetAvailableQuorumsRequest) Descriptor() ([]byte, []int) {
+	return file_advisory_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetAvailableQuorumsRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *GetAvailableQuorumsRequest) GetTransactionAmount() float64 {
+	if x != nil {
+		return x.TransactionAmount
+	}
+	return 0
+}
+
+func (x *GetAvailableQuorumsRequest) GetLastCharTid() string {
+	if x != nil {
+		return x.LastCharTid
+	}
+	return ""
+}
+
+func (x *GetAvailableQuorumsRequest) GetType() int32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *GetAvailableQuorumsRequest) GetFtName() string {
+	if x != nil {
+		return x.FtName
+	}
+	return ""
+}
+
+func (x *GetAvailableQuorumsRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *GetAvailableQuorumsRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *GetAvailableQuorumsRequest) GetMinRegions() int32 {
+	if x != nil {
+		return x.MinRegions
+	}
+	return 0
+}
+
+func (x *GetAvailableQuorumsRequest) GetRequires() []string {
+	if x != nil {
+		return x.Requires
+	}
+	return nil
+}
+
+func (x *GetAvailableQuorumsRequest) GetMinTier() string {
+	if x != nil {
+		return x.MinTier
+	}
+	return ""
+}
+
+func (x *GetAvailableQuorumsRequest) GetMinSuccessfulTxns() int64 {
+	if x != nil {
+		return x.MinSuccessfulTxns
+	}
+	return 0
+}
+
+func (x *GetAvailableQuorumsRequest) GetDidType() int32 {
+	if x != nil && x.DidType != nil {
+		return *x.DidType
+	}
+	return 0
+}
+
+func (x *GetAvailableQuorumsRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+type QuorumData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          int32                  `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"` // "PeerID.DID"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuorumData) Reset() {
+	*x = QuorumData{}
+	mi := &file_advisory_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuorumData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuorumData) ProtoMessage() {}
+
+func (x *QuorumData) ProtoReflect() protoreflect.Message {
+	mi := &file_advisory_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuorumData.ProtoReflect.Descriptor instead.
+func (*QuorumData) Descriptor() ([]byte, []int) {
+	return file_advisory_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *QuorumData) GetType() int32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *QuorumData) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type QuorumListResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Status         bool                   `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message        string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Quorums        []*QuorumData          `protobuf:"bytes,3,rep,name=quorums,proto3" json:"quorums,omitempty"`
+	TransactionId  string                 `protobuf:"bytes,4,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	RequestedCount int32                  `protobuf:"varint,5,opt,name=requested_count,json=requestedCount,proto3" json:"requested_count,omitempty"`
+	DeliveredCount int32                  `protobuf:"varint,6,opt,name=delivered_count,json=deliveredCount,proto3" json:"delivered_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *QuorumListResponse) Reset() {
+	*x = QuorumListResponse{}
+	mi := &file_advisory_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuorumListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuorumListResponse) ProtoMessage() {}
+
+func (x *QuorumListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_advisory_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuorumListResponse.ProtoReflect.Descriptor instead.
+func (*QuorumListResponse) Descriptor() ([]byte, []int) {
+	return file_advisory_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *QuorumListResponse) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+func (x *QuorumListResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *QuorumListResponse) GetQuorums() []*QuorumData {
+	if x != nil {
+		return x.Quorums
+	}
+	return nil
+}
+
+func (x *QuorumListResponse) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *QuorumListResponse) GetRequestedCount() int32 {
+	if x != nil {
+		return x.RequestedCount
+	}
+	return 0
+}
+
+func (x *QuorumListResponse) GetDeliveredCount() int32 {
+	if x != nil {
+		return x.DeliveredCount
+	}
+	return 0
+}
+
+type UpdateBalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Did           string                 `protobuf:"bytes,1,opt,name=did,proto3" json:"did,omitempty"`
+	Balance       float64                `protobuf:"fixed64,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateBalanceRequest) Reset() {
+	*x = UpdateBalanceRequest{}
+	mi := &file_advisory_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateBalanceRequest) ProtoMessage() {}
+
+func (x *UpdateBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_advisory_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateBalanceRequest.ProtoReflect.Descriptor instead.
+func (*UpdateBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_advisory_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *UpdateBalanceRequest) GetDid() string {
+	if x != nil {
+		return x.Did
+	}
+	return ""
+}
+
+func (x *UpdateBalanceRequest) GetBalance() float64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+type HeartbeatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Did           string                 `protobuf:"bytes,1,opt,name=did,proto3" json:"did,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatRequest) Reset() {
+	*x = HeartbeatRequest{}
+	mi := &file_advisory_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatRequest) ProtoMessage() {}
+
+func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_advisory_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_advisory_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HeartbeatRequest) GetDid() string {
+	if x != nil {
+		return x.Did
+	}
+	return ""
+}
+
+type GetHealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"` // Empty reports every namespace
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHealthRequest) Reset() {
+	*x = GetHealthRequest{}
+	mi := &file_advisory_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHealthRequest) ProtoMessage() {}
+
+func (x *GetHealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_advisory_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHealthRequest.ProtoReflect.Descriptor instead.
+func (*GetHealthRequest) Descriptor() ([]byte, []int) {
+	return file_advisory_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetHealthRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type HealthStatus struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Status           string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	TotalQuorums     int32                  `protobuf:"varint,2,opt,name=total_quorums,json=totalQuorums,proto3" json:"total_quorums,omitempty"`
+	AvailableQuorums int32                  `protobuf:"varint,3,opt,name=available_quorums,json=availableQuorums,proto3" json:"available_quorums,omitempty"`
+	Uptime           string                 `protobuf:"bytes,4,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	LastCheck        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_check,json=lastCheck,proto3" json:"last_check,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *HealthStatus) Reset() {
+	*x = HealthStatus{}
+	mi := &file_advisory_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthStatus) ProtoMessage() {}
+
+func (x *HealthStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_advisory_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthStatus.ProtoReflect.Descriptor instead.
+func (*HealthStatus) Descriptor() ([]byte, []int) {
+	return file_advisory_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *HealthStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *HealthStatus) GetTotalQuorums() int32 {
+	if x != nil {
+		return x.TotalQuorums
+	}
+	return 0
+}
+
+func (x *HealthStatus) GetAvailableQuorums() int32 {
+	if x != nil {
+		return x.AvailableQuorums
+	}
+	return 0
+}
+
+func (x *HealthStatus) GetUptime() string {
+	if x != nil {
+		return x.Uptime
+	}
+	return ""
+}
+
+func (x *HealthStatus) GetLastCheck() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastCheck
+	}
+	return nil
+}
+
+type BasicResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        bool                   `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BasicResponse) Reset() {
+	*x = BasicResponse{}
+	mi := &file_advisory_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BasicResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BasicResponse) ProtoMessage() {}
+
+func (x *BasicResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_advisory_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BasicResponse.ProtoReflect.Descriptor instead.
+func (*BasicResponse) Descriptor() ([]byte, []int) {
+	return file_advisory_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BasicResponse) GetStatus() bool {
+	if x != nil {
+		return x.Status
+	}
+	return false
+}
+
+func (x *BasicResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+var File_advisory_proto protoreflect.FileDescriptor
+
+const file_advisory_proto_rawDesc = "" +
+	"\n" +
+	"\x0eadvisory.proto\x12\vadvisory.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8b\x04\n" +
+	"\x15RegisterQuorumRequest\x12\x10\n" +
+	"\x03did\x18\x01 \x01(\tR\x03did\x12\x17\n" +
+	"\apeer_id\x18\x02 \x01(\tR\x06peerId\x12\x18\n" +
+	"\abalance\x18\x03 \x01(\x01R\abalance\x12\x19\n" +
+	"\bdid_type\x18\x04 \x01(\x05R\adidType\x12)\n" +
+	"\x10supported_tokens\x18\x05 \x03(\tR\x0fsupportedTokens\x12\"\n" +
+	"\fcapabilities\x18\x06 \x03(\tR\fcapabilities\x12\x16\n" +
+	"\x06region\x18\a \x01(\tR\x06region\x12\x12\n" +
+	"\x04tier\x18\b \x01(\tR\x04tier\x12\x1c\n" +
+	"\tnamespace\x18\t \x01(\tR\tnamespace\x12L\n" +
+	"\bmetadata\x18\n" +
+	" \x03(\v20.advisory.v1.RegisterQuorumRequest.MetadataEntryR\bmetadata\x12:\n" +
+	"\x19min_participation_balance\x18\v \x01(\x01R\x17minParticipationBalance\x122\n" +
+	"\x15max_transaction_share\x18\f \x01(\x01R\x13maxTransactionShare\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\".\n" +
+	"\x1aConfirmAvailabilityRequest\x12\x10\n" +
+	"\x03did\x18\x01 \x01(\tR\x03did\"\xc4\x03\n" +
+	"\x1aGetAvailableQuorumsRequest\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x05R\x05count\x12-\n" +
+	"\x12transaction_amount\x18\x02 \x01(\x01R\x11transactionAmount\x12\"\n" +
+	"\rlast_char_tid\x18\x03 \x01(\tR\vlastCharTid\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\x05R\x04type\x12\x17\n" +
+	"\aft_name\x18\x05 \x01(\tR\x06ftName\x12%\n" +
+	"\x0etransaction_id\x18\x06 \x01(\tR\rtransactionId\x12\x1c\n" +
+	"\tnamespace\x18\a \x01(\tR\tnamespace\x12\x1f\n" +
+	"\vmin_regions\x18\b \x01(\x05R\n" +
+	"minRegions\x12\x1a\n" +
+	"\brequires\x18\t \x03(\tR\brequires\x12\x19\n" +
+	"\bmin_tier\x18\n" +
+	" \x01(\tR\aminTier\x12.\n" +
+	"\x13min_successful_txns\x18\v \x01(\x03R\x11minSuccessfulTxns\x12\x1e\n" +
+	"\bdid_type\x18\f \x01(\x05H\x00R\adidType\x88\x01\x01\x12\x16\n" +
+	"\x06region\x18\r \x01(\tR\x06regionB\v\n" +
+	"\t_did_type\":\n" +
+	"\n" +
+	"QuorumData\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\x05R\x04type\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\"\xf2\x01\n" +
+	"\x12QuorumListResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\bR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x121\n" +
+	"\aquorums\x18\x03 \x03(\v2\x17.advisory.v1.QuorumDataR\aquorums\x12%\n" +
+	"\x0etransaction_id\x18\x04 \x01(\tR\rtransactionId\x12'\n" +
+	"\x0frequested_count\x18\x05 \x01(\x05R\x0erequestedCount\x12'\n" +
+	"\x0fdelivered_count\x18\x06 \x01(\x05R\x0edeliveredCount\"B\n" +
+	"\x14UpdateBalanceRequest\x12\x10\n" +
+	"\x03did\x18\x01 \x01(\tR\x03did\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\x01R\abalance\"$\n" +
+	"\x10HeartbeatRequest\x12\x10\n" +
+	"\x03did\x18\x01 \x01(\tR\x03did\"0\n" +
+	"\x10GetHealthRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\"\xcb\x01\n" +
+	"\fHealthStatus\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12#\n" +
+	"\rtotal_quorums\x18\x02 \x01(\x05R\ftotalQuorums\x12+\n" +
+	"\x11available_quorums\x18\x03 \x01(\x05R\x10availableQuorums\x12\x16\n" +
+	"\x06uptime\x18\x04 \x01(\tR\x06uptime\x129\n" +
+	"\n" +
+	"last_check\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tlastCheck\"A\n" +
+	"\rBasicResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\bR\x06status\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage2\xfc\x03\n" +
+	"\fAdvisoryNode\x12P\n" +
+	"\x0eRegisterQuorum\x12\".advisory.v1.RegisterQuorumRequest\x1a\x1a.advisory.v1.BasicResponse\x12Z\n" +
+	"\x13ConfirmAvailability\x12'.advisory.v1.ConfirmAvailabilityRequest\x1a\x1a.advisory.v1.BasicResponse\x12_\n" +
+	"\x13GetAvailableQuorums\x12'.advisory.v1.GetAvailableQuorumsRequest\x1a\x1f.advisory.v1.QuorumListResponse\x12N\n" +
+	"\rUpdateBalance\x12!.advisory.v1.UpdateBalanceRequest\x1a\x1a.advisory.v1.BasicResponse\x12F\n" +
+	"\tHeartbeat\x12\x1d.advisory.v1.HeartbeatRequest\x1a\x1a.advisory.v1.BasicResponse\x12E\n" +
+	"\tGetHealth\x12\x1d.advisory.v1.GetHealthRequest\x1a\x19.advisory.v1.HealthStatusB1Z/github.com/gklps/advisory-node/proto/advisorypbb\x06proto3"
+
+var (
+	file_advisory_proto_rawDescOnce sync.Once
+	file_advisory_proto_rawDescData []byte
+)
+
+func file_advisory_proto_rawDescGZIP() []byte {
+	file_advisory_proto_rawDescOnce.Do(func() {
+		file_advisory_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_advisory_proto_rawDesc), len(file_advisory_proto_rawDesc)))
+	})
+	return file_advisory_proto_rawDescData
+}
+
+var file_advisory_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_advisory_proto_goTypes = []any{
+	(*RegisterQuorumRequest)(nil),      // 0: advisory.v1.RegisterQuorumRequest
+	(*ConfirmAvailabilityRequest)(nil), // 1: advisory.v1.ConfirmAvailabilityRequest
+	(*GetAvailableQuorumsRequest)(nil), // 2: advisory.v1.GetAvailableQuorumsRequest
+	(*QuorumData)(nil),                 // 3: advisory.v1.QuorumData
+	(*QuorumListResponse)(nil),         // 4: advisory.v1.QuorumListResponse
+	(*UpdateBalanceRequest)(nil),       // 5: advisory.v1.UpdateBalanceRequest
+	(*HeartbeatRequest)(nil),           // 6: advisory.v1.HeartbeatRequest
+	(*GetHealthRequest)(nil),           // 7: advisory.v1.GetHealthRequest
+	(*HealthStatus)(nil),               // 8: advisory.v1.HealthStatus
+	(*BasicResponse)(nil),              // 9: advisory.v1.BasicResponse
+	nil,                                // 10: advisory.v1.RegisterQuorumRequest.MetadataEntry
+	(*timestamppb.Timestamp)(nil),      // 11: google.protobuf.Timestamp
+}
+var file_advisory_proto_depIdxs = []int32{
+	10, // 0: advisory.v1.RegisterQuorumRequest.metadata:type_name -> advisory.v1.RegisterQuorumRequest.MetadataEntry
+	3,  // 1: advisory.v1.QuorumListResponse.quorums:type_name -> advisory.v1.QuorumData
+	11, // 2: advisory.v1.HealthStatus.last_check:type_name -> google.protobuf.Timestamp
+	0,  // 3: advisory.v1.AdvisoryNode.RegisterQuorum:input_type -> advisory.v1.RegisterQuorumRequest
+	1,  // 4: advisory.v1.AdvisoryNode.ConfirmAvailability:input_type -> advisory.v1.ConfirmAvailabilityRequest
+	2,  // 5: advisory.v1.AdvisoryNode.GetAvailableQuorums:input_type -> advisory.v1.GetAvailableQuorumsRequest
+	5,  // 6: advisory.v1.AdvisoryNode.UpdateBalance:input_type -> advisory.v1.UpdateBalanceRequest
+	6,  // 7: advisory.v1.AdvisoryNode.Heartbeat:input_type -> advisory.v1.HeartbeatRequest
+	7,  // 8: advisory.v1.AdvisoryNode.GetHealth:input_type -> advisory.v1.GetHealthRequest
+	9,  // 9: advisory.v1.AdvisoryNode.RegisterQuorum:output_type -> advisory.v1.BasicResponse
+	9,  // 10: advisory.v1.AdvisoryNode.ConfirmAvailability:output_type -> advisory.v1.BasicResponse
+	4,  // 11: advisory.v1.AdvisoryNode.GetAvailableQuorums:output_type -> advisory.v1.QuorumListResponse
+	9,  // 12: advisory.v1.AdvisoryNode.UpdateBalance:output_type -> advisory.v1.BasicResponse
+	9,  // 13: advisory.v1.AdvisoryNode.Heartbeat:output_type -> advisory.v1.BasicResponse
+	8,  // 14: advisory.v1.AdvisoryNode.GetHealth:output_type -> advisory.v1.HealthStatus
+	9,  // [9:15] is the sub-list for method output_type
+	3,  // [3:9] is the sub-list for method input_type
+	3,  // [3:3] is the sub-list for extension type_name
+	3,  // [3:3] is the sub-list for extension extendee
+	0,  // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_advisory_proto_init() }
+func file_advisory_proto_init() {
+	if File_advisory_proto != nil {
+		return
+	}
+	file_advisory_proto_msgTypes[2].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_advisory_proto_rawDesc), len(file_advisory_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_advisory_proto_goTypes,
+		DependencyIndexes: file_advisory_proto_depIdxs,
+		MessageInfos:      file_advisory_proto_msgTypes,
+	}.Build()
+	File_advisory_proto = out.File
+	file_advisory_proto_goTypes = nil
+	file_advisory_proto_depIdxs = nil
+}
@@ -0,0 +1,331 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: advisory.proto
+
+package advisorypb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AdvisoryNode_RegisterQuorum_FullMethodName      = "/advisory.v1.AdvisoryNode/RegisterQuorum"
+	AdvisoryNode_ConfirmAvailability_FullMethodName = "/advisory.v1.AdvisoryNode/ConfirmAvailability"
+	AdvisoryNode_GetAvailableQuorums_FullMethodName = "/advisory.v1.AdvisoryNode/GetAvailableQuorums"
+	AdvisoryNode_UpdateBalance_FullMethodName       = "/advisory.v1.AdvisoryNode/UpdateBalance"
+	AdvisoryNode_Heartbeat_FullMethodName           = "/advisory.v1.AdvisoryNode/Heartbeat"
+	AdvisoryNode_GetHealth_FullMethodName           = "/advisory.v1.AdvisoryNode/GetHealth"
+)
+
+// AdvisoryNodeClient is the client API for AdvisoryNode service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AdvisoryNode mirrors the quorum registration, availability and selection endpoints of
+// the REST API for Go services that would rather skip HTTP/JSON. Requests are handled
+// by the same store and validation as their REST counterparts.
+type AdvisoryNodeClient interface {
+	// POST /api/quorum/register
+	RegisterQuorum(ctx context.Context, in *RegisterQuorumRequest, opts ...grpc.CallOption) (*BasicResponse, error)
+	// POST /api/quorum/confirm-availability
+	ConfirmAvailability(ctx context.Context, in *ConfirmAvailabilityRequest, opts ...grpc.CallOption) (*BasicResponse, error)
+	// GET /api/quorum/available
+	GetAvailableQuorums(ctx context.Context, in *GetAvailableQuorumsRequest, opts ...grpc.CallOption) (*QuorumListResponse, error)
+	// PUT /api/quorum/balance
+	UpdateBalance(ctx context.Context, in *UpdateBalanceRequest, opts ...grpc.CallOption) (*BasicResponse, error)
+	// POST /api/quorum/heartbeat (ping only)
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*BasicResponse, error)
+	// GET /api/quorum/health
+	GetHealth(ctx context.Context, in *GetHealthRequest, opts ...grpc.CallOption) (*HealthStatus, error)
+}
+
+type advisoryNodeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdvisoryNodeClient(cc grpc.ClientConnInterface) AdvisoryNodeClient {
+	return &advisoryNodeClient{cc}
+}
+
+func (c *advisoryNodeClient) RegisterQuorum(ctx context.Context, in *RegisterQuorumRequest, opts ...grpc.CallOption) (*BasicResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BasicResponse)
+	err := c.cc.Invoke(ctx, AdvisoryNode_RegisterQuorum_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisoryNodeClient) ConfirmAvailability(ctx context.Context, in *ConfirmAvailabilityRequest, opts ...grpc.CallOption) (*BasicResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BasicResponse)
+	err := c.cc.Invoke(ctx, AdvisoryNode_ConfirmAvailability_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisoryNodeClient) GetAvailableQuorums(ctx context.Context, in *GetAvailableQuorumsRequest, opts ...grpc.CallOption) (*QuorumListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuorumListResponse)
+	err := c.cc.Invoke(ctx, AdvisoryNode_GetAvailableQuorums_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisoryNodeClient) UpdateBalance(ctx context.Context, in *UpdateBalanceRequest, opts ...grpc.CallOption) (*BasicResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BasicResponse)
+	err := c.cc.Invoke(ctx, AdvisoryNode_UpdateBalance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisoryNodeClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*BasicResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BasicResponse)
+	err := c.cc.Invoke(ctx, AdvisoryNode_Heartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *advisoryNodeClient) GetHealth(ctx context.Context, in *GetHealthRequest, opts ...grpc.CallOption) (*HealthStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HealthStatus)
+	err := c.cc.Invoke(ctx, AdvisoryNode_GetHealth_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdvisoryNodeServer is the server API for AdvisoryNode service.
+// All implementations must embed UnimplementedAdvisoryNodeServer
+// for forward compatibility.
+//
+// AdvisoryNode mirrors the quorum registration, availability and selection endpoints of
+// the REST API for Go services that would rather skip HTTP/JSON. Requests are handled
+// by the same store and validation as their REST counterparts.
+type AdvisoryNodeServer interface {
+	// POST /api/quorum/register
+	RegisterQuorum(context.Context, *RegisterQuorumRequest) (*BasicResponse, error)
+	// POST /api/quorum/confirm-availability
+	ConfirmAvailability(context.Context, *ConfirmAvailabilityRequest) (*BasicResponse, error)
+	// GET /api/quorum/available
+	GetAvailableQuorums(context.Context, *GetAvailableQuorumsRequest) (*QuorumListResponse, error)
+	// PUT /api/quorum/balance
+	UpdateBalance(context.Context, *UpdateBalanceRequest) (*BasicResponse, error)
+	// POST /api/quorum/heartbeat (ping only)
+	Heartbeat(context.Context, *HeartbeatRequest) (*BasicResponse, error)
+	// GET /api/quorum/health
+	GetHealth(context.Context, *GetHealthRequest) (*HealthStatus, error)
+	mustEmbedUnimplementedAdvisoryNodeServer()
+}
+
+// UnimplementedAdvisoryNodeServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdvisoryNodeServer struct{}
+
+func (UnimplementedAdvisoryNodeServer) RegisterQuorum(context.Context, *RegisterQuorumRequest) (*BasicResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterQuorum not implemented")
+}
+func (UnimplementedAdvisoryNodeServer) ConfirmAvailability(context.Context, *ConfirmAvailabilityRequest) (*BasicResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmAvailability not implemented")
+}
+func (UnimplementedAdvisoryNodeServer) GetAvailableQuorums(context.Context, *GetAvailableQuorumsRequest) (*QuorumListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAvailableQuorums not implemented")
+}
+func (UnimplementedAdvisoryNodeServer) UpdateBalance(context.Context, *UpdateBalanceRequest) (*BasicResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateBalance not implemented")
+}
+func (UnimplementedAdvisoryNodeServer) Heartbeat(context.Context, *HeartbeatRequest) (*BasicResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedAdvisoryNodeServer) GetHealth(context.Context, *GetHealthRequest) (*HealthStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHealth not implemented")
+}
+func (UnimplementedAdvisoryNodeServer) mustEmbedUnimplementedAdvisoryNodeServer() {}
+func (UnimplementedAdvisoryNodeServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdvisoryNodeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdvisoryNodeServer will
+// result in compilation errors.
+type UnsafeAdvisoryNodeServer interface {
+	mustEmbedUnimplementedAdvisoryNodeServer()
+}
+
+func RegisterAdvisoryNodeServer(s grpc.ServiceRegistrar, srv AdvisoryNodeServer) {
+	// If the following call pancis, it indicates UnimplementedAdvisoryNodeServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdvisoryNode_ServiceDesc, srv)
+}
+
+func _AdvisoryNode_RegisterQuorum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterQuorumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisoryNodeServer).RegisterQuorum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisoryNode_RegisterQuorum_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisoryNodeServer).RegisterQuorum(ctx, req.(*RegisterQuorumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisoryNode_ConfirmAvailability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmAvailabilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisoryNodeServer).ConfirmAvailability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisoryNode_ConfirmAvailability_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisoryNodeServer).ConfirmAvailability(ctx, req.(*ConfirmAvailabilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisoryNode_GetAvailableQuorums_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAvailableQuorumsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisoryNodeServer).GetAvailableQuorums(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisoryNode_GetAvailableQuorums_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisoryNodeServer).GetAvailableQuorums(ctx, req.(*GetAvailableQuorumsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisoryNode_UpdateBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisoryNodeServer).UpdateBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisoryNode_UpdateBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisoryNodeServer).UpdateBalance(ctx, req.(*UpdateBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisoryNode_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisoryNodeServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisoryNode_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisoryNodeServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdvisoryNode_GetHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdvisoryNodeServer).GetHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdvisoryNode_GetHealth_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdvisoryNodeServer).GetHealth(ctx, req.(*GetHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdvisoryNode_ServiceDesc is the grpc.ServiceDesc for AdvisoryNode service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdvisoryNode_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "advisory.v1.AdvisoryNode",
+	HandlerType: (*AdvisoryNodeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RegisterQuorum",
+			Handler:    _AdvisoryNode_RegisterQuorum_Handler,
+		},
+		{
+			MethodName: "ConfirmAvailability",
+			Handler:    _AdvisoryNode_ConfirmAvailability_Handler,
+		},
+		{
+			MethodName: "GetAvailableQuorums",
+			Handler:    _AdvisoryNode_GetAvailableQuorums_Handler,
+		},
+		{
+			MethodName: "UpdateBalance",
+			Handler:    _AdvisoryNode_UpdateBalance_Handler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _AdvisoryNode_Heartbeat_Handler,
+		},
+		{
+			MethodName: "GetHealth",
+			Handler:    _AdvisoryNode_GetHealth_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "advisory.proto",
+}
@@ -84,6 +84,10 @@ case "$1" in
                 echo "Error: No main Go file found"
                 exit 1
             fi
+            echo "Applying database migrations..."
+            for env in testnet mainnet; do
+                (set -a; source ./$env.env; ./advisory-node -migrate) || exit 1
+            done
             sudo supervisorctl restart advisory-testnet advisory-mainnet
             echo "Update complete!"
             $0 status
@@ -453,6 +453,15 @@ sudo ufw allow 8080
 sudo ufw allow 8081
 echo "y" | sudo ufw enable
 
+# Bring both databases to the schema version the binary expects; it refuses to start otherwise
+print_status "Applying database migrations..."
+for env in testnet mainnet; do
+    if ! (set -a; source $APP_DIR/$env.env; cd $APP_DIR && ./advisory-node -migrate); then
+        print_error "Migrating the $env database failed"
+        exit 1
+    fi
+done
+
 # Start services
 print_status "Starting Advisory Node services..."
 sudo supervisorctl reread
@@ -569,6 +578,9 @@ case "$1" in
         cp -r * $APP_DIR/
         cd $APP_DIR
         go build -o advisory-node
+        for env in testnet mainnet; do
+            (set -a; source $APP_DIR/\$env.env; ./advisory-node -migrate) || exit 1
+        done
         sudo supervisorctl restart advisory-testnet advisory-mainnet
         echo "Update complete!"
         ;;
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultBalancePrecision is the number of decimal places required balances are rounded
+// up to, matching the %.4f they are reported with
+const DefaultBalancePrecision = 4
+
+// MaxBalancePrecision bounds the precision to what float64 balances hold reliably
+const MaxBalancePrecision = 8
+
+// ValidateBalancePrecision checks that places is between 1 and MaxBalancePrecision
+func ValidateBalancePrecision(places int) error {
+	if places < 1 || places > MaxBalancePrecision {
+		return fmt.Errorf("balance precision must be between 1 and %d decimal places, got %d", MaxBalancePrecision, places)
+	}
+	return nil
+}
+
+// RequiredBalanceShare is each of count quorums' share of amount, rounded up to places
+// decimal places. Rounding up means the shares always cover the amount, and the balance
+// check then compares against the same value the responses report.
+func RequiredBalanceShare(amount float64, count, places int) float64 {
+	return roundBalanceUp(amount/float64(count), places)
+}
+
+// roundBalanceUp rounds value up to places decimal places. A value within representation
+// error of a step stays on it, so 0.1 + 0.2 (0.30000000000000004) rounds to 0.3, not 0.3001.
+func roundBalanceUp(value float64, places int) float64 {
+	scale := math.Pow10(places)
+	return math.Ceil(value*scale-1e-6) / scale
+}
+
+// balanceTolerance is the floating-point slack allowed when a spare balance (balance
+// minus the reserved floor) is compared with a rounded required balance, so a quorum
+// holding exactly the required balance isn't excluded by subtraction error. It is a
+// thousandth of the rounding step.
+func balanceTolerance(places int) float64 {
+	return math.Pow10(-places) / 1000
+}
+
+// hasSpareBalance reports whether spare covers required, within balanceTolerance
+func hasSpareBalance(spare, required float64, places int) bool {
+	return spare >= required-balanceTolerance(places)
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+func TestRequiredBalanceShare(t *testing.T) {
+	tests := []struct {
+		amount float64
+		count  int
+		places int
+		want   float64
+	}{
+		{100, 7, 4, 14.2858},
+		{100, 7, 2, 14.29},
+		{10, 4, 4, 2.5},
+		{0.3, 1, 4, 0.3},
+		{0.1 + 0.2, 1, 4, 0.3}, // Representation error doesn't round up a whole step
+		{1, 3, 8, 0.33333334},
+	}
+	for _, tt := range tests {
+		if got := RequiredBalanceShare(tt.amount, tt.count, tt.places); got != tt.want {
+			t.Errorf("RequiredBalanceShare(%v, %d, %d) = %v, want %v", tt.amount, tt.count, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestBoundaryBalances(t *testing.T) {
+	// 100 over 7 quorums is 14.285714..., rounded up to 14.2858 at 4 places and 14.29 at 2
+	tests := []struct {
+		name     string
+		places   int
+		balance  float64
+		eligible bool
+	}{
+		{"exactly the rounded share", 4, 14.2858, true},
+		{"the unrounded share", 4, 100.0 / 7, false},
+		{"one step short", 4, 14.2857, false},
+		{"exactly the share at 2 places", 2, 14.29, true},
+		{"one step short at 2 places", 2, 14.28, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := newTestStore(t, DBConfig{BalancePrecision: tt.places})
+			ms := NewMemoryStore()
+			if err := ms.SetBalancePrecision(tt.places); err != nil {
+				t.Fatal(err)
+			}
+			for i := 1; i <= 7; i++ {
+				suffix := fmt.Sprint(i)
+				registerTestQuorum(t, ds, suffix, tt.balance)
+				if err := ms.RegisterQuorum(&models.QuorumRegistrationRequest{DID: testDID(suffix), PeerID: "peer" + suffix, Balance: tt.balance, DIDType: 4}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if _, err := ds.SelectQuorums(selectionRequest(7, 100)); (err == nil) != tt.eligible {
+				t.Errorf("DB selection with balance %v: err = %v, want eligible %t", tt.balance, err, tt.eligible)
+			}
+			if _, err := ms.GetAvailableQuorums(7, "", 100, "", nil); (err == nil) != tt.eligible {
+				t.Errorf("memory selection with balance %v: err = %v, want eligible %t", tt.balance, err, tt.eligible)
+			}
+		})
+	}
+
+	// A balance accumulated with representation error still meets a share it equals
+	ds := newTestStore(t, DBConfig{})
+	registerTestQuorum(t, ds, "1", 0.1+0.2)
+	if _, err := ds.SelectQuorums(selectionRequest(1, 0.3)); err != nil {
+		t.Errorf("balance 0.1+0.2 for a share of 0.3: %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+	"gorm.io/gorm"
+)
+
+// API key scopes. Each includes the ones before it: a write key can also read, and an
+// admin key can do anything.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+// apiKeyScopes lists the scopes from least to most privileged
+var apiKeyScopes = []string{ScopeRead, ScopeWrite, ScopeAdmin}
+
+// apiKeyPrefix starts every generated key, so leaked keys are easy to search for
+const apiKeyPrefix = "adv_"
+
+// ErrAPIKeyNotFound is returned when an API key ID does not exist
+var ErrAPIKeyNotFound = errors.New("API key not found")
+
+// ErrAPIKeyNameTaken is returned when creating a key under a name already in use
+var ErrAPIKeyNameTaken = errors.New("an API key with this name already exists")
+
+// APIKey is a client credential for the HTTP and gRPC APIs. Only the SHA-256 hash of the
+// key is stored; the key itself is shown once, when it is created.
+type APIKey struct {
+	ID        uint      `gorm:"primaryKey"`
+	Name      string    `gorm:"size:128;not null;uniqueIndex"`
+	Prefix    string    `gorm:"size:16;not null"` // Start of the key, to tell keys apart in listings
+	KeyHash   string    `gorm:"size:64;not null;uniqueIndex"`
+	Scopes    string    `gorm:"size:64;not null"` // Comma-separated
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for APIKey
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// ValidateScopes checks that every scope is known, returning them lower-cased and
+// without repeats
+func ValidateScopes(scopes []string) ([]string, error) {
+	var normalized []string
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !slices.Contains(apiKeyScopes, scope) {
+			return nil, fmt.Errorf("unknown scope %q, expected one of %v", scope, apiKeyScopes)
+		}
+		if !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("at least one scope is required, from %v", apiKeyScopes)
+	}
+	return normalized, nil
+}
+
+// ScopeAllows reports whether a key holding scopes may act with the needed scope
+func ScopeAllows(scopes []string, needed string) bool {
+	rank := slices.Index(apiKeyScopes, needed)
+	for _, scope := range scopes {
+		if slices.Index(apiKeyScopes, scope) >= rank {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateAPIKey generates a key with the given name and already validated scopes. The
+// returned key is not stored and can't be recovered later.
+func (ds *DBStore) CreateAPIKey(name string, scopes []string) (string, *models.APIKeyInfo, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	record := newAPIKey(name, key, scopes)
+	if err := ds.db.Create(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return "", nil, ErrAPIKeyNameTaken
+		}
+		return "", nil, err
+	}
+	return key, toAPIKeyInfo(record), nil
+}
+
+// EnsureAPIKey makes key the one stored under name, with the given scopes, creating or
+// replacing it. It is how a key supplied by the operator, such as the bootstrap admin
+// key, is installed; changing the supplied key rotates it.
+func (ds *DBStore) EnsureAPIKey(name, key string, scopes []string) error {
+	record := newAPIKey(name, key, scopes)
+	return ds.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("name = ?", name).Delete(&APIKey{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&record).Error
+	})
+}
+
+// LookupAPIKey returns the scopes of key, or nil when no such key exists
+func (ds *DBStore) LookupAPIKey(key string) ([]string, error) {
+	var record APIKey
+	err := ds.db.Select("scopes").Where("key_hash = ?", hashAPIKey(key)).Take(&record).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(record.Scopes, ","), nil
+}
+
+// ListAPIKeys returns every API key, oldest first, without the keys themselves
+func (ds *DBStore) ListAPIKeys() ([]models.APIKeyInfo, error) {
+	var records []APIKey
+	if err := ds.db.Order("id").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]models.APIKeyInfo, 0, len(records))
+	for _, record := range records {
+		result = append(result, *toAPIKeyInfo(record))
+	}
+	return result, nil
+}
+
+// DeleteAPIKey revokes a key; requests carrying it are refused from then on
+func (ds *DBStore) DeleteAPIKey(id uint) error {
+	result := ds.db.Delete(&APIKey{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func newAPIKey(name, key string, scopes []string) APIKey {
+	return APIKey{
+		Name:    name,
+		Prefix:  key[:min(len(key), 8)],
+		KeyHash: hashAPIKey(key),
+		Scopes:  strings.Join(scopes, ","),
+	}
+}
+
+// hashAPIKey is the stored form of a key. Keys are long random strings, so a plain
+// SHA-256 is enough; it also lets a key be looked up by its hash.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func toAPIKeyInfo(record APIKey) *models.APIKeyInfo {
+	return &models.APIKeyInfo{
+		ID:        record.ID,
+		Name:      record.Name,
+		Prefix:    record.Prefix,
+		Scopes:    strings.Split(record.Scopes, ","),
+		CreatedAt: record.CreatedAt,
+	}
+}
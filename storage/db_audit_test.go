@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/gklps/advisory-node/audit"
+)
+
+// mockAuditor keeps every record it is handed
+type mockAuditor struct {
+	mu      sync.Mutex
+	records []audit.Record
+}
+
+func (m *mockAuditor) AuditSelection(record audit.Record) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records = append(m.records, record)
+}
+
+func TestSelectionsAreAudited(t *testing.T) {
+	auditor := &mockAuditor{}
+	ds := newTestStore(t, DBConfig{Auditor: auditor})
+	registerTestQuorum(t, ds, "1", 100)
+	registerTestQuorum(t, ds, "2", 100)
+	registerTestQuorum(t, ds, "3", 10)
+
+	var want []*SelectionResult
+	for _, txnID := range []string{"txn-1", "txn-2"} {
+		req := selectionRequest(2, 40)
+		req.TransactionID = txnID
+		result, err := ds.SelectQuorums(req)
+		if err != nil {
+			t.Fatalf("SelectQuorums(%s): %v", txnID, err)
+		}
+		want = append(want, result)
+	}
+	// Failed selections aren't audited
+	if _, err := ds.SelectQuorums(selectionRequest(3, 300)); err == nil {
+		t.Fatal("selection beyond the pool succeeded")
+	}
+
+	if len(auditor.records) != len(want) {
+		t.Fatalf("auditor received %d records, want %d", len(auditor.records), len(want))
+	}
+	for i, record := range auditor.records {
+		if record.TransactionID != want[i].TransactionID || record.Request.TransactionID != want[i].TransactionID ||
+			record.RequiredBalance != 20 || !slices.Equal(record.Quorums, want[i].Quorums) || record.Timestamp.IsZero() {
+			t.Errorf("record %d = %+v, want selection %+v", i, record, want[i])
+		}
+		// The third quorum is short of the 20 required, so the balance stage drops it
+		if record.TotalQuorums != 3 || len(record.Stages) == 0 || record.Stages[len(record.Stages)-1].Remaining != 2 {
+			t.Errorf("record %d breakdown = %d quorums, stages %+v; want 3 narrowed to 2", i, record.TotalQuorums, record.Stages)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+// GetQuorumsByDIDs returns the quorums for dids in the order given, or an error wrapping
+// ErrQuorumNotFound naming the first DID that is not registered
+func (ds *DBStore) GetQuorumsByDIDs(dids []string) ([]models.QuorumInfo, error) {
+	var quorums []QuorumDB
+	if err := ds.db.Where("did IN ?", dids).Find(&quorums).Error; err != nil {
+		return nil, err
+	}
+
+	byDID := make(map[string]QuorumDB, len(quorums))
+	for _, q := range quorums {
+		byDID[q.DID] = q
+	}
+
+	result := make([]models.QuorumInfo, 0, len(dids))
+	for _, did := range dids {
+		q, ok := byDID[did]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrQuorumNotFound, did)
+		}
+		result = append(result, ds.toQuorumInfo(q))
+	}
+	return result, nil
+}
+
+// CompareQuorums returns the key metrics of quorums a and b side by side
+func (ds *DBStore) CompareQuorums(a, b string) (*models.QuorumComparison, error) {
+	quorums, err := ds.GetQuorumsByDIDs([]string{a, b})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := make([]models.QuorumMetrics, 0, len(quorums))
+	for _, q := range quorums {
+		m, err := ds.quorumMetrics(q)
+		if err != nil {
+			return nil, err
+		}
+		metrics = append(metrics, m)
+	}
+
+	return &models.QuorumComparison{A: metrics[0], B: metrics[1]}, nil
+}
+
+// quorumMetrics combines a quorum's record with its transaction stats and uptime
+func (ds *DBStore) quorumMetrics(q models.QuorumInfo) (models.QuorumMetrics, error) {
+	m := models.QuorumMetrics{
+		DID:              q.DID,
+		Balance:          q.Balance,
+		AssignmentCount:  q.AssignmentCount,
+		Available:        q.Available,
+		LastPing:         q.LastPing,
+		RegistrationTime: q.RegistrationTime,
+		SupportedTokens:  q.SupportedTokens,
+	}
+
+	var stats QuorumStats
+	result := ds.db.Where("quorum_d_id = ?", q.DID).Limit(1).Find(&stats)
+	if result.Error != nil {
+		return m, result.Error
+	}
+	m.TotalTransactions = stats.TotalTransactions
+	m.SuccessfulTransactions = stats.SuccessfulTransactions
+	if stats.TotalTransactions > 0 {
+		reliability := float64(stats.SuccessfulTransactions) / float64(stats.TotalTransactions)
+		m.Reliability = &reliability
+	}
+
+	var events []AvailabilityEvent
+	if err := ds.db.Where("quorum_d_id = ?", q.DID).Order("timestamp ASC").Find(&events).Error; err != nil {
+		return m, err
+	}
+	m.UptimeRatio = uptimeRatio(events, time.Now())
+
+	return m, nil
+}
+
+// uptimeRatio is the share of time between the first availability event and now that
+// the quorum spent available, replaying registration, availability, staleness and
+// removal events in order. It is nil when there are no events.
+func uptimeRatio(events []AvailabilityEvent, now time.Time) *float64 {
+	if len(events) == 0 || !now.After(events[0].Timestamp) {
+		return nil
+	}
+
+	var up time.Duration
+	available := false
+	since := events[0].Timestamp
+	for _, e := range events {
+		if available {
+			up += e.Timestamp.Sub(since)
+		}
+		since = e.Timestamp
+		available = e.Event != EventStale && e.Event != EventUnregistered
+	}
+	if available {
+		up += now.Sub(since)
+	}
+
+	ratio := float64(up) / float64(now.Sub(events[0].Timestamp))
+	return &ratio
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrTransactionCompleted is returned when a transaction's outcome was already reported
+var ErrTransactionCompleted = errors.New("transaction already completed")
+
+// Transaction completion statuses; history rows without one are still in flight
+const (
+	TransactionSucceeded = "success"
+	TransactionFailed    = "failed"
+)
+
+// QuorumAssignment records one quorum handed to one transaction, so the assignment can
+// be credited or taken back when the transaction's outcome is reported
+type QuorumAssignment struct {
+	ID            uint      `gorm:"primaryKey"`
+	TransactionID string    `gorm:"index;not null;size:255"`
+	QuorumDID     string    `gorm:"column:quorum_did;index;index:idx_quorum_assignments_did_time,priority:1;not null;size:59"`
+	Timestamp     time.Time `gorm:"index:idx_quorum_assignments_did_time,priority:2"` // Per-quorum timeline reads scan this index
+	CreatedAt     time.Time
+}
+
+// CompleteTransaction records the outcome of txnID on its history. A success credits
+// each assigned quorum with a successful transaction. A failure with
+// decrementAssignments takes back the assignment count bump each assignment made, so
+// quorums are not passed over in load balancing for transactions that never happened.
+// Transactions selected before assignments were recorded fall back to their history's
+// quorum list.
+func (ds *DBStore) CompleteTransaction(txnID, status string, decrementAssignments bool) (*models.TransactionCompletion, error) {
+	defer ds.timeOperation("complete_transaction", txnID)()
+
+	completion := &models.TransactionCompletion{
+		TransactionID: txnID,
+		Status:        status,
+		CompletedAt:   time.Now().UTC(),
+	}
+
+	err := ds.assignmentTransaction(func(tx *gorm.DB) error {
+		var history []TransactionHistory
+		if err := tx.Where("transaction_id = ?", txnID).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Find(&history).Error; err != nil {
+			return err
+		}
+		if len(history) == 0 {
+			return ErrTransactionNotFound
+		}
+		for _, entry := range history {
+			if entry.Status != "" {
+				return ErrTransactionCompleted
+			}
+		}
+
+		var assignments []QuorumAssignment
+		if err := tx.Where("transaction_id = ?", txnID).Find(&assignments).Error; err != nil {
+			return err
+		}
+		dids := make([]string, 0, len(assignments))
+		for _, assignment := range assignments {
+			dids = append(dids, assignment.QuorumDID)
+		}
+		if len(assignments) == 0 {
+			for _, entry := range history {
+				dids = append(dids, entry.AssignedDIDs()...)
+			}
+		}
+		completion.QuorumDIDs = uniqueStrings(dids)
+
+		if err := tx.Model(&TransactionHistory{}).Where("transaction_id = ?", txnID).Updates(map[string]interface{}{
+			"status":       status,
+			"completed_at": completion.CompletedAt,
+		}).Error; err != nil {
+			return err
+		}
+
+		switch {
+		case status == TransactionSucceeded:
+			return tx.Model(&QuorumStats{}).Where("quorum_d_id IN ?", completion.QuorumDIDs).
+				Update("successful_transactions", gorm.Expr("successful_transactions + 1")).Error
+		case decrementAssignments:
+			// One decrement per assignment, so a quorum assigned twice under the same
+			// transaction ID gives both back
+			for _, did := range dids {
+				result := tx.Model(&QuorumDB{}).Where("did = ? AND assignment_count > 0", did).
+					Update("assignment_count", gorm.Expr("assignment_count - 1"))
+				if result.Error != nil {
+					return result.Error
+				}
+				completion.Decremented += result.RowsAffected
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return completion, nil
+}
+
+// uniqueStrings returns values without repeats, keeping first occurrences in order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
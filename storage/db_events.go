@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/gklps/advisory-node/events"
+	"gorm.io/gorm"
+)
+
+// poolChangesKey is the context key under which poolTransaction collects the
+// availability events a transaction records
+type poolChangesKey struct{}
+
+// poolChanges collects a transaction's availability events, published once it commits
+type poolChanges struct {
+	events []events.Event
+}
+
+// SetEventBroadcaster sets the broadcaster notified of every recorded availability
+// event: registrations, unregistrations, staleness and availability changes
+func (ds *DBStore) SetEventBroadcaster(broadcaster *events.Broadcaster) {
+	ds.broadcaster = broadcaster
+}
+
+// poolTransaction runs fn in a transaction and, once it commits, broadcasts the
+// availability events it recorded. Events of a rolled-back transaction are discarded.
+func (ds *DBStore) poolTransaction(fn func(tx *gorm.DB) error) error {
+	changes := &poolChanges{}
+	ctx := context.WithValue(context.Background(), poolChangesKey{}, changes)
+	if err := ds.db.WithContext(ctx).Transaction(fn); err != nil {
+		return err
+	}
+	ds.broadcaster.Publish(changes.events...)
+	return nil
+}
+
+// recordPoolChange adds an availability event to the changes tx's poolTransaction will
+// broadcast; outside a poolTransaction it does nothing
+func recordPoolChange(tx *gorm.DB, did, event string, at time.Time) {
+	if changes, ok := tx.Statement.Context.Value(poolChangesKey{}).(*poolChanges); ok {
+		changes.events = append(changes.events, events.Event{Type: event, DID: did, Timestamp: at})
+	}
+}
+
+// savepoint runs fn in a nested transaction. When it rolls back, the availability
+// events fn recorded are dropped with it.
+func savepoint(tx *gorm.DB, fn func(tx *gorm.DB) error) error {
+	changes, _ := tx.Statement.Context.Value(poolChangesKey{}).(*poolChanges)
+	var recorded int
+	if changes != nil {
+		recorded = len(changes.events)
+	}
+
+	err := tx.Transaction(fn)
+	if err != nil && changes != nil {
+		changes.events = changes.events[:recorded]
+	}
+	return err
+}
@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+	"gorm.io/gorm"
+)
+
+// PoolExportVersion is the format_version of pool exports this build writes and reads
+const PoolExportVersion = 1
+
+// Pool import modes: merge leaves quorums missing from the document alone, replace
+// unregisters them
+const (
+	ImportMerge   = "merge"
+	ImportReplace = "replace"
+)
+
+// PoolImportResult counts what ImportPool changed
+type PoolImportResult struct {
+	Created        int
+	Updated        int
+	Removed        int
+	Stats          int
+	BalanceChanges int
+}
+
+// ExportPool returns every registered quorum with its stored state, oldest first, and
+// optionally the stats and balance history of every quorum that has any, including
+// unregistered ones
+func (ds *DBStore) ExportPool(includeStats, includeHistory bool) (*models.PoolExport, error) {
+	export := &models.PoolExport{
+		FormatVersion: PoolExportVersion,
+		ExportedAt:    time.Now(),
+		Quorums:       []models.ExportedQuorum{},
+	}
+
+	var quorums []QuorumDB
+	if err := ds.db.Order("id").Find(&quorums).Error; err != nil {
+		return nil, err
+	}
+	for _, q := range quorums {
+		export.Quorums = append(export.Quorums, toExportedQuorum(q))
+	}
+
+	if includeStats {
+		var stats []QuorumStats
+		if err := ds.db.Order("id").Find(&stats).Error; err != nil {
+			return nil, err
+		}
+		export.Stats = make([]models.QuorumUsage, 0, len(stats))
+		for _, s := range stats {
+			export.Stats = append(export.Stats, models.QuorumUsage{
+				DID:                    s.QuorumDID,
+				TotalTransactions:      s.TotalTransactions,
+				SuccessfulTransactions: s.SuccessfulTransactions,
+				TotalAmount:            s.TotalAmount,
+				LastActive:             s.LastActive,
+			})
+		}
+	}
+
+	if includeHistory {
+		var history []BalanceHistory
+		if err := ds.db.Order("id").Find(&history).Error; err != nil {
+			return nil, err
+		}
+		export.BalanceHistory = make([]models.ExportedBalanceChange, 0, len(history))
+		for _, entry := range history {
+			export.BalanceHistory = append(export.BalanceHistory, models.ExportedBalanceChange{
+				DID: entry.QuorumDID,
+				BalanceChange: models.BalanceChange{
+					OldBalance: entry.OldBalance,
+					NewBalance: entry.NewBalance,
+					Reason:     entry.ChangeReason,
+					Timestamp:  entry.Timestamp,
+				},
+			})
+		}
+	}
+
+	return export, nil
+}
+
+// ImportPool restores a pool export in one transaction. Each quorum in the document is
+// written as exported, over any registered or unregistered row with its DID; in replace
+// mode, registered quorums missing from the document are unregistered. Stats and balance
+// history in the document replace the stored ones of the DIDs they name. The document
+// must already be validated. A tombstoned DID fails the whole import with ErrTombstoned,
+// unless its entry carries a register signature lifting the tombstone.
+func (ds *DBStore) ImportPool(export *models.PoolExport, mode string) (*PoolImportResult, error) {
+	if mode != ImportMerge && mode != ImportReplace {
+		return nil, fmt.Errorf("unknown import mode %q", mode)
+	}
+
+	result := &PoolImportResult{}
+	err := ds.poolTransaction(func(tx *gorm.DB) error {
+		*result = PoolImportResult{}
+
+		imported := make(map[string]bool, len(export.Quorums))
+		for i := range export.Quorums {
+			q := &export.Quorums[i]
+			imported[q.DID] = true
+			created, err := importQuorum(tx, q)
+			if err != nil {
+				return fmt.Errorf("quorum %s: %w", q.DID, err)
+			}
+			if created {
+				result.Created++
+			} else {
+				result.Updated++
+			}
+		}
+
+		if mode == ImportReplace {
+			var registered []string
+			if err := tx.Model(&QuorumDB{}).Pluck("did", &registered).Error; err != nil {
+				return err
+			}
+			for _, did := range registered {
+				if imported[did] {
+					continue
+				}
+				if err := unregisterQuorum(tx, did, false); err != nil {
+					return fmt.Errorf("quorum %s: %w", did, err)
+				}
+				result.Removed++
+			}
+		}
+
+		for _, usage := range export.Stats {
+			if err := tx.Where("quorum_d_id = ?", usage.DID).Delete(&QuorumStats{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&QuorumStats{
+				QuorumDID:              usage.DID,
+				TotalTransactions:      usage.TotalTransactions,
+				SuccessfulTransactions: usage.SuccessfulTransactions,
+				TotalAmount:            usage.TotalAmount,
+				LastActive:             usage.LastActive,
+			}).Error; err != nil {
+				return err
+			}
+			result.Stats++
+		}
+
+		cleared := make(map[string]bool)
+		for _, change := range export.BalanceHistory {
+			if !cleared[change.DID] {
+				if err := tx.Where("quorum_d_id = ?", change.DID).Delete(&BalanceHistory{}).Error; err != nil {
+					return err
+				}
+				cleared[change.DID] = true
+			}
+			if err := tx.Create(&BalanceHistory{
+				QuorumDID:    change.DID,
+				OldBalance:   change.OldBalance,
+				NewBalance:   change.NewBalance,
+				ChangeReason: change.Reason,
+				Timestamp:    change.Timestamp,
+			}).Error; err != nil {
+				return err
+			}
+			result.BalanceChanges++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// importQuorum writes one exported quorum within an open transaction, reporting whether
+// its DID was new. An unregistered row with the DID is restored, as registration does,
+// and subject to the same tombstone check.
+func importQuorum(tx *gorm.DB, q *models.ExportedQuorum) (bool, error) {
+	if err := checkTombstone(tx, &q.QuorumRegistrationRequest); err != nil {
+		return false, err
+	}
+
+	var existing QuorumDB
+	found := tx.Unscoped().Select("id", "deleted_at").Where("did = ?", q.DID).Limit(1).Find(&existing)
+	if found.Error != nil {
+		return false, found.Error
+	}
+	created := found.RowsAffected == 0 || existing.DeletedAt.Valid
+
+	row := fromExportedQuorum(q)
+	row.ID = existing.ID
+	if row.CreatedAt.IsZero() {
+		row.CreatedAt = time.Now()
+	}
+	// Inserting a row replaces a false available with the column default, in row too
+	available := row.Available
+	if err := tx.Unscoped().Select("*").Save(&row).Error; err != nil {
+		return false, err
+	}
+	if existing.ID == 0 && !available {
+		if err := tx.Model(&row).Update("available", false).Error; err != nil {
+			return false, err
+		}
+	}
+
+	event := EventReregistered
+	if created {
+		event = EventRegistered
+	}
+	if err := recordAvailabilityEvent(tx, q.DID, event); err != nil {
+		return false, err
+	}
+	if err := replaceCapabilities(tx, q.DID, q.Capabilities); err != nil {
+		return false, err
+	}
+	return created, replaceTokens(tx, q.DID, q.SupportedTokens)
+}
+
+// toExportedQuorum converts a database row into its export representation
+func toExportedQuorum(q QuorumDB) models.ExportedQuorum {
+	var supportedTokens, capabilities []string
+	var metadata map[string]string
+	if q.SupportedTokens != "" {
+		json.Unmarshal([]byte(q.SupportedTokens), &supportedTokens)
+	}
+	if q.Capabilities != "" {
+		json.Unmarshal([]byte(q.Capabilities), &capabilities)
+	}
+	if q.Metadata != "" {
+		json.Unmarshal([]byte(q.Metadata), &metadata)
+	}
+
+	return models.ExportedQuorum{
+		QuorumRegistrationRequest: models.QuorumRegistrationRequest{
+			DID:             q.DID,
+			PeerID:          q.PeerID,
+			Balance:         q.Balance,
+			DIDType:         q.DIDType,
+			SupportedTokens: supportedTokens,
+			Capabilities:    capabilities,
+			Region:          q.Region,
+			Tier:            q.Tier,
+			Namespace:       q.Namespace,
+			Metadata:        metadata,
+
+			MinParticipationBalance: q.MinParticipationBalance,
+			MaxTransactionShare:     q.MaxTransactionShare,
+			PublicKey:               q.PublicKey,
+		},
+		Available:        q.Available,
+		ManuallyDisabled: q.ManuallyDisabled,
+		AssignmentCount:  q.AssignmentCount,
+		LastPing:         q.LastPing,
+		LastAssignment:   q.LastAssignment,
+		RegistrationTime: q.RegistrationTime,
+		CreatedAt:        q.CreatedAt,
+	}
+}
+
+// fromExportedQuorum builds the database row for an exported quorum
+func fromExportedQuorum(q *models.ExportedQuorum) QuorumDB {
+	row := newQuorum(&q.QuorumRegistrationRequest)
+	row.Available = q.Available && !q.ManuallyDisabled
+	row.ManuallyDisabled = q.ManuallyDisabled
+	row.AssignmentCount = q.AssignmentCount
+	row.LastPing = q.LastPing
+	row.LastAssignment = q.LastAssignment
+	row.RegistrationTime = q.RegistrationTime
+	row.CreatedAt = q.CreatedAt
+	return row
+}
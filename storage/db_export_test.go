@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+// exportThroughJSON exports ds with stats and balance history and decodes the document
+// again, as an operator's saved export would be read back
+func exportThroughJSON(t *testing.T, ds *DBStore) *models.PoolExport {
+	t.Helper()
+	export, err := ds.ExportPool(true, true)
+	if err != nil {
+		t.Fatalf("ExportPool: %v", err)
+	}
+	data, err := json.Marshal(export)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded models.PoolExport
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return &decoded
+}
+
+func TestPoolExportImportRoundTrip(t *testing.T) {
+	source := newTestStore(t, DBConfig{})
+	supportsTRI := func(req *models.QuorumRegistrationRequest) { req.SupportedTokens = []string{"RBT", "TRI"} }
+	for _, suffix := range []string{"1", "2", "3"} {
+		registerTestQuorum(t, source, suffix, 100, supportsTRI)
+	}
+	registerTestQuorum(t, source, "4", 100)
+	if err := source.UpdateQuorumBalance(testDID("4"), 80); err != nil {
+		t.Fatal(err)
+	}
+
+	// Assignments give the quorums counts, last assignments and stats to carry over
+	for i := 0; i < 2; i++ {
+		req := selectionRequest(2, 20)
+		req.FTName = "TRI"
+		if _, err := source.SelectQuorums(req); err != nil {
+			t.Fatalf("SelectQuorums: %v", err)
+		}
+	}
+	exported := exportThroughJSON(t, source)
+
+	target := newTestStore(t, DBConfig{})
+	result, err := target.ImportPool(exported, ImportMerge)
+	if err != nil {
+		t.Fatalf("ImportPool: %v", err)
+	}
+	if result.Created != 4 || result.Updated != 0 || result.Removed != 0 {
+		t.Errorf("import result = %+v, want 4 created", result)
+	}
+
+	reexported := exportThroughJSON(t, target)
+	if len(reexported.Quorums) != len(exported.Quorums) {
+		t.Fatalf("imported %d quorums, exported %d", len(reexported.Quorums), len(exported.Quorums))
+	}
+	for i, want := range exported.Quorums {
+		got := reexported.Quorums[i]
+		if got.DID != want.DID || !slices.Equal(got.SupportedTokens, want.SupportedTokens) ||
+			got.Balance != want.Balance || got.Available != want.Available ||
+			got.AssignmentCount != want.AssignmentCount {
+			t.Errorf("quorum %d = %+v, want %+v", i, got, want)
+		}
+		if !got.LastPing.Equal(want.LastPing) || !got.LastAssignment.Equal(want.LastAssignment) ||
+			!got.RegistrationTime.Equal(want.RegistrationTime) || !got.CreatedAt.Equal(want.CreatedAt) {
+			t.Errorf("quorum %s timestamps = %v/%v/%v/%v, want %v/%v/%v/%v", want.DID,
+				got.LastPing, got.LastAssignment, got.RegistrationTime, got.CreatedAt,
+				want.LastPing, want.LastAssignment, want.RegistrationTime, want.CreatedAt)
+		}
+	}
+	if assigned := exported.Quorums[0].AssignmentCount + exported.Quorums[1].AssignmentCount + exported.Quorums[2].AssignmentCount; assigned != 4 {
+		t.Errorf("exported assignment counts total %d, want the 4 made", assigned)
+	}
+
+	if len(reexported.Stats) != len(exported.Stats) || len(exported.Stats) == 0 {
+		t.Fatalf("stats: imported %d, exported %d", len(reexported.Stats), len(exported.Stats))
+	}
+	for i, want := range exported.Stats {
+		got := reexported.Stats[i]
+		if got.DID != want.DID || got.TotalTransactions != want.TotalTransactions ||
+			got.TotalAmount != want.TotalAmount || !got.LastActive.Equal(want.LastActive) {
+			t.Errorf("stats %d = %+v, want %+v", i, got, want)
+		}
+	}
+	if len(reexported.BalanceHistory) != len(exported.BalanceHistory) || len(exported.BalanceHistory) == 0 {
+		t.Errorf("balance history: imported %d, exported %d", len(reexported.BalanceHistory), len(exported.BalanceHistory))
+	}
+
+	// Token support is restored for selection too, not only in the exported column
+	req := selectionRequest(3, 30)
+	req.FTName = "TRI"
+	selection, err := target.SelectQuorums(req)
+	if err != nil {
+		t.Fatalf("TRI selection on the imported pool: %v", err)
+	}
+	if slices.Contains(selectedDIDs(selection), testDID("4")) {
+		t.Error("imported RBT-only quorum selected for TRI")
+	}
+}
+
+func TestPoolImportReplaceRemovesOnlyMissingQuorums(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	kept := []string{registerTestQuorum(t, ds, "1", 100), registerTestQuorum(t, ds, "2", 100)}
+	missing := registerTestQuorum(t, ds, "3", 100)
+
+	export := exportThroughJSON(t, ds)
+	export.Quorums = slices.DeleteFunc(export.Quorums, func(q models.ExportedQuorum) bool { return q.DID == missing })
+
+	result, err := ds.ImportPool(export, ImportReplace)
+	if err != nil {
+		t.Fatalf("ImportPool: %v", err)
+	}
+	if result.Created != 0 || result.Updated != 2 || result.Removed != 1 {
+		t.Errorf("import result = %+v, want 2 updated and 1 removed", result)
+	}
+	if _, err := ds.GetQuorumByDID(missing); !errors.Is(err, ErrQuorumNotFound) {
+		t.Errorf("quorum missing from the document: error = %v, want ErrQuorumNotFound", err)
+	}
+	for _, did := range kept {
+		if quorum, err := ds.GetQuorumByDID(did); err != nil || !quorum.Available || quorum.Balance != 100 {
+			t.Errorf("kept quorum %s = %+v (%v), want it unchanged", did, quorum, err)
+		}
+	}
+}
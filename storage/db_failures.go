@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+// SelectionFailureBufferSize is how many recent selection failures each instance keeps
+const SelectionFailureBufferSize = 100
+
+// failureRing is a fixed-size buffer of the most recent selection failures
+type failureRing struct {
+	mu      sync.Mutex
+	entries []models.SelectionFailure
+	next    int // Slot the next failure overwrites once the buffer is full
+}
+
+func (r *failureRing) add(failure models.SelectionFailure) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.entries) < SelectionFailureBufferSize {
+		r.entries = append(r.entries, failure)
+		return
+	}
+	r.entries[r.next] = failure
+	r.next = (r.next + 1) % SelectionFailureBufferSize
+}
+
+// recent returns up to limit failures, newest first (all buffered when limit <= 0)
+func (r *failureRing) recent(limit int) []models.SelectionFailure {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if limit <= 0 || limit > len(r.entries) {
+		limit = len(r.entries)
+	}
+	result := make([]models.SelectionFailure, 0, limit)
+	for i := 0; i < limit; i++ {
+		// Walk backwards from the most recently written slot
+		idx := (r.next - 1 - i + 2*len(r.entries)) % len(r.entries)
+		result = append(result, r.entries[idx])
+	}
+	return result
+}
+
+// recordSelectionFailure buffers a failed SelectQuorums call for RecentSelectionFailures
+func (ds *DBStore) recordSelectionFailure(req models.QuorumListRequest, err error) {
+	code := models.ErrCodeInsufficientQuorums
+	var poolErr *PoolNotReadyError
+	if errors.As(err, &poolErr) {
+		code = models.ErrCodePoolNotReady
+	} else if errors.Is(err, ErrAssignmentNotRecorded) {
+		code = models.ErrCodeInternal
+	}
+
+	count := req.Count
+	if count <= 0 {
+		count = 7
+	}
+	ds.failures.add(models.SelectionFailure{
+		Timestamp:         time.Now(),
+		TransactionID:     req.TransactionID,
+		RequestedCount:    count,
+		TransactionAmount: req.TransactionAmount,
+		Token:             poolToken(req.FTName),
+		Namespace:         selectionNamespace(req.Namespace),
+		Code:              code,
+		Reason:            err.Error(),
+	})
+}
+
+// RecentSelectionFailures returns up to limit of this instance's most recent selection
+// failures, newest first
+func (ds *DBStore) RecentSelectionFailures(limit int) []models.SelectionFailure {
+	return ds.failures.recent(limit)
+}
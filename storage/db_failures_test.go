@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+func TestRecentSelectionFailures(t *testing.T) {
+	ds := newTestStore(t, DBConfig{TokenMinPoolSizes: map[string]int{"TRI": 2}})
+	registerTestQuorum(t, ds, "1", 100)
+	registerTestQuorum(t, ds, "2", 100)
+
+	fail := func(req models.QuorumListRequest) {
+		t.Helper()
+		if _, err := ds.SelectQuorums(req); err == nil {
+			t.Fatalf("selection %s succeeded, want it to fail", req.TransactionID)
+		}
+	}
+	tooMany := selectionRequest(3, 3)
+	tooMany.TransactionID = "txn-too-many"
+	fail(tooMany)
+	tooMuch := selectionRequest(2, 1000)
+	tooMuch.TransactionID = "txn-too-much"
+	fail(tooMuch)
+	tri := selectionRequest(1, 1)
+	tri.TransactionID = "txn-tri"
+	tri.FTName = "TRI"
+	fail(tri)
+
+	// Successful selections aren't recorded
+	if _, err := ds.SelectQuorums(selectionRequest(2, 2)); err != nil {
+		t.Fatalf("SelectQuorums: %v", err)
+	}
+
+	failures := ds.RecentSelectionFailures(0)
+	want := []struct {
+		transactionID, token, code string
+		requested                  int
+	}{
+		{"txn-tri", "TRI", models.ErrCodePoolNotReady, 1},
+		{"txn-too-much", "RBT", models.ErrCodeInsufficientQuorums, 2},
+		{"txn-too-many", "RBT", models.ErrCodeInsufficientQuorums, 3},
+	}
+	if len(failures) != len(want) {
+		t.Fatalf("failures = %+v, want %d", failures, len(want))
+	}
+	for i, f := range failures {
+		w := want[i]
+		if f.TransactionID != w.transactionID || f.Token != w.token || f.Code != w.code ||
+			f.RequestedCount != w.requested || f.Namespace != models.DefaultNamespace || f.Reason == "" {
+			t.Errorf("failures[%d] = %+v, want %s on %s with %s for %d", i, f, w.transactionID, w.token, w.code, w.requested)
+		}
+	}
+
+	if limited := ds.RecentSelectionFailures(1); len(limited) != 1 || limited[0].TransactionID != "txn-tri" {
+		t.Errorf("limit 1 = %+v, want only the newest failure", limited)
+	}
+}
+
+func TestFailureRingKeepsNewest(t *testing.T) {
+	var ring failureRing
+	total := SelectionFailureBufferSize + 5
+	for i := 0; i < total; i++ {
+		ring.add(models.SelectionFailure{TransactionID: fmt.Sprint(i)})
+	}
+
+	recent := ring.recent(0)
+	if len(recent) != SelectionFailureBufferSize {
+		t.Fatalf("kept %d failures, want %d", len(recent), SelectionFailureBufferSize)
+	}
+	for i, f := range recent {
+		if want := fmt.Sprint(total - 1 - i); f.TransactionID != want {
+			t.Fatalf("recent[%d] = %s, want %s", i, f.TransactionID, want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"math"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+// Forecast lookback bounds: the default trend window and the longest accepted
+const (
+	DefaultForecastWindow = 24 * time.Hour
+	MaxForecastWindow     = 30 * 24 * time.Hour
+)
+
+// ForecastEligibility estimates when the pool will satisfy req. The trend is the net
+// change in eligible quorums over the window before now:
+//   - gained: quorums eligible now that registered, became available again or had
+//     their balance cross the required threshold upward within the window
+//   - lost: quorums in the namespace and token pool, not eligible now, that went stale
+//     or had their balance cross the threshold downward within the window
+//
+// The ETA extrapolates that rate linearly and is nil while the pool isn't growing.
+// It is a rough guide: quorums that unregistered are not counted as lost, and balance
+// crossings are judged against each quorum's current reserved floor.
+func (ds *DBStore) ForecastEligibility(req models.QuorumListRequest, window time.Duration) (*models.EligibilityForecast, error) {
+	defer ds.timeOperation("eligibility_forecast", req)()
+
+	count := req.Count
+	if count <= 0 {
+		count = 7
+	}
+	if window <= 0 {
+		window = DefaultForecastWindow
+	}
+	requiredBalance := ds.RequiredBalance(req.TransactionAmount, count)
+	threshold := ds.balanceThreshold(requiredBalance)
+	now := time.Now()
+	start := now.Add(-window)
+
+	var eligible []QuorumDB
+	if err := applyStages(ds.db.Model(&QuorumDB{}), ds.selectionStages(req, requiredBalance)).
+		Select("did", "registration_time").
+		Find(&eligible).Error; err != nil {
+		return nil, err
+	}
+
+	// The pool a forecast is about: quorums in the namespace supporting the token,
+	// whatever their liveness or balance
+	var pool []QuorumDB
+	poolQuery := ds.db.Model(&QuorumDB{})
+	for _, stage := range ds.selectionStages(req, requiredBalance) {
+		if stage.Name == "namespace" || stage.Name == "token_supported" {
+			poolQuery = stage.Apply(poolQuery)
+		}
+	}
+	if err := poolQuery.Select("did", "min_participation_balance").Find(&pool).Error; err != nil {
+		return nil, err
+	}
+	floors := make(map[string]float64, len(pool))
+	for _, q := range pool {
+		floors[q.DID] = q.MinParticipationBalance
+	}
+
+	// History rows are fetched for the pool in chunks to stay under bind-variable limits
+	gainedDIDs := make(map[string]bool)
+	lostDIDs := make(map[string]bool)
+	dids := make([]string, 0, len(pool))
+	for did := range floors {
+		dids = append(dids, did)
+	}
+	for i := 0; i < len(dids); i += 500 {
+		chunk := dids[i:min(i+500, len(dids))]
+
+		var changes []BalanceHistory
+		if err := ds.db.Where("quorum_d_id IN ? AND timestamp >= ?", chunk, start).
+			Find(&changes).Error; err != nil {
+			return nil, err
+		}
+		for _, change := range changes {
+			floor := floors[change.QuorumDID]
+			before := hasSpareBalance(change.OldBalance-floor, threshold, ds.config.BalancePrecision)
+			after := hasSpareBalance(change.NewBalance-floor, threshold, ds.config.BalancePrecision)
+			switch {
+			case !before && after:
+				gainedDIDs[change.QuorumDID] = true
+			case before && !after:
+				lostDIDs[change.QuorumDID] = true
+			}
+		}
+
+		var events []AvailabilityEvent
+		if err := ds.db.Where("quorum_d_id IN ? AND timestamp >= ?", chunk, start).
+			Where("event IN ?", []string{EventReregistered, EventAvailable, EventStale}).
+			Find(&events).Error; err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			if event.Event == EventStale {
+				lostDIDs[event.QuorumDID] = true
+			} else {
+				gainedDIDs[event.QuorumDID] = true
+			}
+		}
+	}
+
+	forecast := &models.EligibilityForecast{
+		RequestedCount:  count,
+		RequiredBalance: requiredBalance,
+		EligibleCount:   int64(len(eligible)),
+		WindowHours:     window.Hours(),
+	}
+	if needed := int64(count) - forecast.EligibleCount; needed > 0 {
+		forecast.Needed = needed
+	}
+
+	isEligible := make(map[string]bool, len(eligible))
+	for _, q := range eligible {
+		isEligible[q.DID] = true
+		if gainedDIDs[q.DID] || !q.RegistrationTime.Before(start) {
+			forecast.Gained++
+		}
+	}
+	for did := range lostDIDs {
+		if !isEligible[did] {
+			forecast.Lost++
+		}
+	}
+	forecast.GrowthPerHour = float64(forecast.Gained-forecast.Lost) / window.Hours()
+
+	switch {
+	case forecast.Needed == 0:
+		eta := int64(0)
+		forecast.ETASeconds = &eta
+		forecast.EstimatedAt = &now
+	case forecast.GrowthPerHour > 0:
+		eta := int64(math.Ceil(float64(forecast.Needed) / forecast.GrowthPerHour * 3600))
+		at := now.Add(time.Duration(eta) * time.Second)
+		forecast.ETASeconds = &eta
+		forecast.EstimatedAt = &at
+	}
+
+	return forecast, nil
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestForecastEligibilityGrowingPool(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	// Two quorums registered before the window, three within it
+	for i := 1; i <= 5; i++ {
+		did := registerTestQuorum(t, ds, fmt.Sprint(i), 100)
+		if i <= 2 {
+			setQuorumColumn(t, ds, did, "registration_time", time.Now().Add(-48*time.Hour))
+		}
+	}
+
+	forecast, err := ds.ForecastEligibility(selectionRequest(10, 10), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("ForecastEligibility: %v", err)
+	}
+	if forecast.EligibleCount != 5 || forecast.Needed != 5 || forecast.Gained != 3 || forecast.Lost != 0 {
+		t.Errorf("forecast = %+v, want 5 eligible, 5 needed and 3 gained", forecast)
+	}
+	// 3 a day is 0.125 an hour, so 5 more take 40 hours
+	if forecast.ETASeconds == nil || *forecast.ETASeconds != 40*3600 {
+		t.Fatalf("eta = %v, want %d seconds", forecast.ETASeconds, 40*3600)
+	}
+	if forecast.EstimatedAt == nil || !forecast.EstimatedAt.After(time.Now()) {
+		t.Errorf("estimated_at = %v, want a time in the future", forecast.EstimatedAt)
+	}
+
+	// A request the pool already satisfies is due now
+	forecast, err = ds.ForecastEligibility(selectionRequest(5, 5), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forecast.Needed != 0 || forecast.ETASeconds == nil || *forecast.ETASeconds != 0 {
+		t.Errorf("satisfiable forecast = %+v, want needed 0 and an eta of 0", forecast)
+	}
+
+	// Once every quorum predates the window the pool isn't growing, so there is no
+	// estimate rather than a negative one
+	for i := 3; i <= 5; i++ {
+		setQuorumColumn(t, ds, testDID(fmt.Sprint(i)), "registration_time", time.Now().Add(-48*time.Hour))
+	}
+	forecast, err = ds.ForecastEligibility(selectionRequest(10, 10), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forecast.Gained != 0 || forecast.GrowthPerHour != 0 || forecast.ETASeconds != nil || forecast.EstimatedAt != nil {
+		t.Errorf("stagnant forecast = %+v, want no growth and no estimate", forecast)
+	}
+}
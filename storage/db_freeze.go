@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxStalenessFreeze bounds how far ahead staleness checks can be frozen, so a forgotten
+// maintenance window can't disable cleanup indefinitely
+const MaxStalenessFreeze = 24 * time.Hour
+
+// FreezeStaleness suspends heartbeat-driven staleness until the given deadline: cleanup
+// stops marking quorums unavailable and selection ignores last_ping. Checks resume on
+// their own once the deadline passes. The deadline is stored in the database, so the
+// freeze holds on every instance sharing it, whichever one runs cleanup.
+func (ds *DBStore) FreezeStaleness(until time.Time) error {
+	return ds.putSetting(settingStalenessFrozenUntil, until.UTC().Format(time.RFC3339Nano))
+}
+
+// StalenessFrozenUntil returns the freeze deadline and whether staleness is currently frozen
+func (ds *DBStore) StalenessFrozenUntil() (time.Time, bool, error) {
+	value, ok, err := ds.getSetting(settingStalenessFrozenUntil)
+	if err != nil || !ok {
+		return time.Time{}, false, err
+	}
+	until, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("corrupt staleness freeze deadline %q: %w", value, err)
+	}
+	return until, time.Now().Before(until), nil
+}
+
+// applyHeartbeatFilter restricts a quorum query to recently pinged quorums, unless
+// staleness is frozen for planned maintenance
+func (ds *DBStore) applyHeartbeatFilter(query *gorm.DB) *gorm.DB {
+	_, frozen, err := ds.StalenessFrozenUntil()
+	if err != nil {
+		query.AddError(err)
+		return query
+	}
+	if frozen {
+		return query
+	}
+	return query.Where("last_ping > ?", time.Now().Add(-ds.config.AvailabilityWindow))
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCleanupIsNoOpWhileFrozen(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	did := registerTestQuorum(t, ds, "1", 100)
+	setQuorumColumn(t, ds, did, "last_ping", time.Now().Add(-time.Hour))
+
+	if err := ds.FreezeStaleness(time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	marked, err := ds.CleanupStaleQuorums()
+	if err != nil || marked != 0 {
+		t.Fatalf("cleanup while frozen marked %d (%v), want none", marked, err)
+	}
+	quorum, err := ds.GetQuorumByDID(did)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !quorum.Available {
+		t.Error("frozen cleanup marked the quorum unavailable")
+	}
+	// The missed heartbeats don't keep it out of selection during the window either
+	if _, err := ds.SelectQuorums(selectionRequest(1, 1)); err != nil {
+		t.Errorf("selection while frozen: %v", err)
+	}
+
+	// Past the deadline cleanup resumes on its own
+	if err := ds.FreezeStaleness(time.Now().Add(-time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if marked, err = ds.CleanupStaleQuorums(); err != nil || marked != 1 {
+		t.Fatalf("cleanup after the freeze marked %d (%v), want 1", marked, err)
+	}
+	if quorum, err = ds.GetQuorumByDID(did); err != nil || quorum.Available {
+		t.Errorf("quorum after the freeze = %+v (%v), want it unavailable", quorum, err)
+	}
+	if _, err := ds.SelectQuorums(selectionRequest(1, 1)); err == nil {
+		t.Error("stale quorum selected after the freeze ended")
+	}
+}
+
+func TestFreezeIsSharedBetweenInstances(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	did := registerTestQuorum(t, ds, "1", 100)
+	setQuorumColumn(t, ds, did, "last_ping", time.Now().Add(-time.Hour))
+
+	// A replica on the same database, freshly started, sees a freeze set through another
+	replica, err := NewDBStore(ds.config)
+	if err != nil {
+		t.Fatalf("NewDBStore: %v", err)
+	}
+	t.Cleanup(func() { replica.Close() })
+
+	until := time.Now().Add(time.Hour)
+	if err := ds.FreezeStaleness(until); err != nil {
+		t.Fatal(err)
+	}
+	if got, frozen, err := replica.StalenessFrozenUntil(); err != nil || !frozen || !got.Equal(until) {
+		t.Errorf("replica freeze = %v, %v (%v); want frozen until %v", got, frozen, err, until)
+	}
+	if marked, err := replica.CleanupStaleQuorums(); err != nil || marked != 0 {
+		t.Errorf("replica cleanup during the freeze marked %d (%v), want none", marked, err)
+	}
+	if _, err := replica.SelectQuorums(selectionRequest(1, 1)); err != nil {
+		t.Errorf("replica selection during the freeze: %v", err)
+	}
+}
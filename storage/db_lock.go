@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+)
+
+// cleanupLockKey identifies the stale-quorum cleanup job in Postgres' advisory lock key space
+const cleanupLockKey int64 = 0x61647669736f7279 // "advisory"
+
+// cleanupLockName is the MySQL named lock for the same job
+const cleanupLockName = "advisory_node_cleanup"
+
+// cleanupLocker tries to take the cluster-wide cleanup lock without waiting. When it is
+// acquired, release gives it up again.
+type cleanupLocker func(ctx context.Context) (release func(), acquired bool, err error)
+
+// WithCleanupLock runs fn only if this instance holds the cluster-wide cleanup lock, so
+// replicas sharing one database don't all run cleanup at once. It reports whether fn ran.
+//
+// On Postgres this is a session-level pg_try_advisory_lock, and on MySQL a named
+// GET_LOCK (see sessionCleanupLock). SQLite deployments are assumed to be single-instance
+// (the file can't be shared between hosts), so the lock is always granted there.
+func (ds *DBStore) WithCleanupLock(ctx context.Context, fn func()) (bool, error) {
+	if ds.cleanupLock == nil {
+		fn()
+		return true, nil
+	}
+
+	release, acquired, err := ds.cleanupLock(ctx)
+	if err != nil || !acquired {
+		return false, err
+	}
+	defer release()
+
+	fn()
+	return true, nil
+}
+
+// usesSessionCleanupLock reports whether dbType takes the cleanup lock with
+// sessionCleanupLock
+func usesSessionCleanupLock(dbType string) bool {
+	return dbType == "postgres" || dbType == "mysql"
+}
+
+// sessionCleanupLock takes the Postgres advisory lock or MySQL named lock for cleanup.
+// Both belong to a session, so the lock is held on a dedicated connection until
+// released, while cleanup itself runs through the pool; configurePool therefore refuses
+// a pool of a single connection.
+func (ds *DBStore) sessionCleanupLock(ctx context.Context) (func(), bool, error) {
+	var lockQuery, unlockQuery string
+	var lockArg interface{}
+	switch ds.config.Type {
+	case "postgres":
+		lockQuery, unlockQuery, lockArg = "SELECT pg_try_advisory_lock($1)", "SELECT pg_advisory_unlock($1)", cleanupLockKey
+	case "mysql":
+		lockQuery, unlockQuery, lockArg = "SELECT GET_LOCK(?, 0) = 1", "SELECT RELEASE_LOCK(?)", cleanupLockName
+	}
+
+	sqlDB, err := ds.db.DB()
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Advisory locks belong to a session, so lock and unlock must use the same connection
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get connection for cleanup lock: %v", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, lockQuery, lockArg).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("failed to acquire cleanup lock: %v", err)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+	return func() {
+		conn.ExecContext(context.Background(), unlockQuery, lockArg)
+		conn.Close()
+	}, true, nil
+}
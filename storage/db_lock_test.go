@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sharedTestDatabase returns the connection settings of a Postgres or MySQL database
+// named by TEST_DB_TYPE, TEST_DB_HOST, TEST_DB_PORT, TEST_DB_NAME, TEST_DB_USER and
+// TEST_DB_PASSWORD, skipping the test when none is configured
+func sharedTestDatabase(t *testing.T) DBConfig {
+	t.Helper()
+	dbType := os.Getenv("TEST_DB_TYPE")
+	if dbType != "postgres" && dbType != "mysql" {
+		t.Skip("set TEST_DB_TYPE=postgres or mysql and TEST_DB_* to run against a shared database")
+	}
+	port, _ := strconv.Atoi(os.Getenv("TEST_DB_PORT"))
+	return DBConfig{
+		Type:        dbType,
+		Host:        os.Getenv("TEST_DB_HOST"),
+		Port:        port,
+		Database:    os.Getenv("TEST_DB_NAME"),
+		Username:    os.Getenv("TEST_DB_USER"),
+		Password:    os.Getenv("TEST_DB_PASSWORD"),
+		SSLMode:     "disable",
+		AutoMigrate: true,
+	}
+}
+
+func TestWithCleanupLockOneInstanceAtATime(t *testing.T) {
+	config := sharedTestDatabase(t)
+
+	// Two instances sharing one database, as replicas behind a load balancer would
+	instances := make([]*DBStore, 2)
+	for i := range instances {
+		ds, err := NewDBStore(config)
+		if err != nil {
+			t.Fatalf("NewDBStore: %v", err)
+		}
+		t.Cleanup(func() { ds.Close() })
+		instances[i] = ds
+	}
+	ctx := context.Background()
+
+	holding := make(chan struct{})
+	release := make(chan struct{})
+	firstDone := make(chan error, 1)
+	go func() {
+		ran, err := instances[0].WithCleanupLock(ctx, func() {
+			close(holding)
+			<-release
+		})
+		if err == nil && !ran {
+			t.Error("first instance didn't get the free lock")
+		}
+		firstDone <- err
+	}()
+
+	select {
+	case <-holding:
+	case <-time.After(5 * time.Second):
+		t.Fatal("first instance never started cleanup")
+	}
+	ran, err := instances[1].WithCleanupLock(ctx, func() {
+		t.Error("second instance cleaned up while the first held the lock")
+	})
+	if err != nil || ran {
+		t.Errorf("second instance: ran = %v, err = %v; want it to skip", ran, err)
+	}
+
+	close(release)
+	if err := <-firstDone; err != nil {
+		t.Fatalf("first instance: %v", err)
+	}
+
+	// Once released, the next run goes to whichever instance asks
+	cleaned := false
+	ran, err = instances[1].WithCleanupLock(ctx, func() { cleaned = true })
+	if err != nil || !ran || !cleaned {
+		t.Errorf("second instance after release: ran = %v, err = %v", ran, err)
+	}
+}
+
+func TestWithCleanupLockSQLite(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+
+	// SQLite is single-instance, so the lock is always granted
+	for i := 0; i < 2; i++ {
+		cleaned := false
+		ran, err := ds.WithCleanupLock(context.Background(), func() { cleaned = true })
+		if err != nil || !ran || !cleaned {
+			t.Errorf("run %d: ran = %v, cleaned = %v, err = %v", i, ran, cleaned, err)
+		}
+	}
+}
+
+// sharedCleanupLock stands in for the database lock two instances would share: a mutex
+// taken without waiting, held like sessionCleanupLock's on a dedicated connection from
+// ds's pool, so cleanup under it has to get by on the rest of the pool
+func sharedCleanupLock(ds *DBStore, mu *sync.Mutex) cleanupLocker {
+	return func(ctx context.Context) (func(), bool, error) {
+		if !mu.TryLock() {
+			return nil, false, nil
+		}
+		sqlDB, err := ds.db.DB()
+		if err != nil {
+			mu.Unlock()
+			return nil, false, err
+		}
+		conn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			mu.Unlock()
+			return nil, false, err
+		}
+		return func() {
+			conn.Close()
+			mu.Unlock()
+		}, true, nil
+	}
+}
+
+func TestWithCleanupLockTwoInstances(t *testing.T) {
+	// Two instances with the smallest pool configurePool accepts for a session lock
+	var lock sync.Mutex
+	instances := make([]*DBStore, 2)
+	for i := range instances {
+		ds := newTestStore(t, DBConfig{MaxOpenConns: 2})
+		ds.cleanupLock = sharedCleanupLock(ds, &lock)
+		registerTestQuorum(t, ds, "1", 100)
+		instances[i] = ds
+	}
+	ctx := context.Background()
+
+	holding := make(chan struct{})
+	release := make(chan struct{})
+	firstDone := make(chan error, 1)
+	go func() {
+		ran, err := instances[0].WithCleanupLock(ctx, func() {
+			// Cleanup queries through the pool while the lock holds a connection
+			if _, err := instances[0].CleanupStaleQuorums(); err != nil {
+				t.Errorf("cleanup under the lock: %v", err)
+			}
+			close(holding)
+			<-release
+		})
+		if err == nil && !ran {
+			t.Error("first instance didn't get the free lock")
+		}
+		firstDone <- err
+	}()
+
+	select {
+	case <-holding:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cleanup under the lock never finished")
+	}
+	ran, err := instances[1].WithCleanupLock(ctx, func() {
+		t.Error("second instance cleaned up while the first held the lock")
+	})
+	if err != nil || ran {
+		t.Errorf("second instance: ran = %v, err = %v; want it to skip", ran, err)
+	}
+
+	close(release)
+	if err := <-firstDone; err != nil {
+		t.Fatalf("first instance: %v", err)
+	}
+
+	// Once released, the next run goes to whichever instance asks
+	cleaned := false
+	ran, err = instances[1].WithCleanupLock(ctx, func() { cleaned = true })
+	if err != nil || !ran || !cleaned {
+		t.Errorf("second instance after release: ran = %v, err = %v", ran, err)
+	}
+}
+
+func TestSessionCleanupLockNeedsTwoConnections(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	for _, tc := range []struct {
+		config  DBConfig
+		wantErr bool
+	}{
+		{DBConfig{Type: "postgres", MaxOpenConns: 1}, true},
+		{DBConfig{Type: "mysql", MaxOpenConns: 1}, true},
+		{DBConfig{Type: "postgres", MaxOpenConns: 2}, false},
+		{DBConfig{Type: "mysql"}, false},
+		{DBConfig{Type: "sqlite", MaxOpenConns: 1}, false},
+	} {
+		if err := configurePool(ds.db, tc.config); (err != nil) != tc.wantErr {
+			t.Errorf("%s with %d open connections: error = %v, want error %v", tc.config.Type, tc.config.MaxOpenConns, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SchemaMigration records an applied migration in schema_migrations
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:128;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for SchemaMigration
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// migration is one versioned schema change
+type migration struct {
+	version int
+	name    string
+	up      func(tx *gorm.DB) error
+}
+
+// migrations are applied in order, each at most once. Append new ones with the next
+// version and never edit an applied one. Version 1 creates the tables from the current
+// models, so a later migration adding a column or index must check it is missing first:
+// on a fresh database version 1 already created it.
+var migrations = []migration{
+	{version: 1, name: "create schema", up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(
+			&QuorumDB{},
+			&TransactionHistory{},
+			&QuorumStats{},
+			&BalanceHistory{},
+			&AvailabilityEvent{},
+			&WebhookDeadLetter{},
+			&WebhookSubscription{},
+			&QuorumTombstone{},
+			&QuorumCapability{},
+			&QuorumReservation{},
+			&QuorumAssignment{},
+			&QuorumToken{},
+		)
+	}},
+	{version: 2, name: "backfill quorum tokens", up: migrateQuorumTokens},
+	{version: 3, name: "create api keys", up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&APIKey{})
+	}},
+	{version: 4, name: "record transaction tokens", up: func(tx *gorm.DB) error {
+		if tx.Migrator().HasColumn(&TransactionHistory{}, "Token") {
+			return nil
+		}
+		return tx.Migrator().AddColumn(&TransactionHistory{}, "Token")
+	}},
+	{version: 5, name: "index assignments by quorum and time", up: func(tx *gorm.DB) error {
+		if tx.Migrator().HasIndex(&QuorumAssignment{}, "idx_quorum_assignments_did_time") {
+			return nil
+		}
+		return tx.Migrator().CreateIndex(&QuorumAssignment{}, "idx_quorum_assignments_did_time")
+	}},
+	{version: 6, name: "create service settings", up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&ServiceSetting{})
+	}},
+}
+
+// SchemaVersion is the database schema version this build expects
+func SchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// MigrateDatabase connects to the database in config and applies every pending
+// migration in order, each in its own transaction. On MySQL, where DDL commits
+// implicitly, a failed migration may be partly applied; migrations are written to be
+// safe to run again.
+func MigrateDatabase(config DBConfig) error {
+	db, err := openDatabase(config)
+	if err != nil {
+		return err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+	return migrate(db)
+}
+
+// migrate applies the migrations newer than the database's schema version
+func migrate(db *gorm.DB) error {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %v", err)
+	}
+	current, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: m.version, Name: m.name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %v", m.version, m.name, err)
+		}
+		log.Printf("🗄️  Applied migration %d: %s\n", m.version, m.name)
+	}
+	return nil
+}
+
+// schemaVersion returns the highest applied migration, 0 for a database that has never
+// been migrated
+func schemaVersion(db *gorm.DB) (int, error) {
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return 0, nil
+	}
+
+	var version int
+	if err := db.Model(&SchemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error; err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %v", err)
+	}
+	return version, nil
+}
+
+// checkSchemaVersion refuses a database whose schema is behind this build. One ahead of
+// it is accepted, since migrations only add to the schema and a rolling deploy runs the
+// previous build against the migrated database for a while.
+func checkSchemaVersion(db *gorm.DB) error {
+	current, err := schemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	switch expected := SchemaVersion(); {
+	case current < expected:
+		return fmt.Errorf("database schema is at version %d but this build needs version %d; run with -migrate to apply the pending migrations", current, expected)
+	case current > expected:
+		log.Printf("⚠️  Database schema is at version %d, newer than this build's %d\n", current, expected)
+	}
+	return nil
+}
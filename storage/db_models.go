@@ -1,52 +1,87 @@
 package storage
 
 import (
+	"encoding/json"
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // QuorumDB represents the database model for quorum information
 type QuorumDB struct {
-	ID               uint      `gorm:"primaryKey"`
-	DID              string    `gorm:"column:did;uniqueIndex;not null;size:59"`
-	PeerID           string    `gorm:"column:peer_id;index;not null"`
-	Balance          float64   `gorm:"column:balance;default:0"`
-	DIDType          int       `gorm:"column:did_type;not null"`
-	Available        bool      `gorm:"column:available;default:true;index"`
+	ID        uint    `gorm:"primaryKey"`
+	DID       string  `gorm:"column:did;uniqueIndex;not null;size:59"`
+	PeerID    string  `gorm:"column:peer_id;index;not null;size:128"`
+	Balance   float64 `gorm:"column:balance;default:0"`
+	DIDType   int     `gorm:"column:did_type;not null"`
+	Available bool    `gorm:"column:available;default:true;index"`
+	// Set when an operator takes the quorum out of rotation; registration and availability
+	// confirmations leave it unavailable until an operator re-enables it
+	ManuallyDisabled bool      `gorm:"column:manually_disabled;default:false"`
 	LastPing         time.Time `gorm:"column:last_ping;index"`
 	AssignmentCount  int64     `gorm:"column:assignment_count;default:0"`
 	LastAssignment   time.Time `gorm:"column:last_assignment"`
 	RegistrationTime time.Time `gorm:"column:registration_time"`
 	SupportedTokens  string    `gorm:"column:supported_tokens;type:text"` // JSON array of supported token types
-	CreatedAt        time.Time `gorm:"column:created_at"`
-	UpdatedAt        time.Time `gorm:"column:updated_at"`
+	Capabilities     string    `gorm:"column:capabilities;type:text"`     // JSON array of capability flags (filtered via quorum_capabilities)
+	Region           string    `gorm:"column:region;size:64;index"`
+	Tier             string    `gorm:"column:tier;size:32;index"` // Collateral tier (e.g. "gold"); empty means untiered
+	Namespace        string    `gorm:"column:namespace;size:64;not null;default:'default';index"`
+	Metadata         string    `gorm:"column:metadata;type:text"` // JSON object of operator labels
+	// Reserved floor set by the node; selection requires balance - floor >= required balance
+	MinParticipationBalance float64 `gorm:"column:min_participation_balance;default:0"`
+	// Largest required balance the node will back per transaction; 0 means no cap
+	MaxTransactionShare float64 `gorm:"column:max_transaction_share;default:0"`
+	// Base64 Ed25519 key the quorum's registration, heartbeat and balance requests are
+	// verified against; empty for quorums that registered without one
+	PublicKey string    `gorm:"column:public_key"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+	// Set when the quorum is unregistered; GORM leaves soft-deleted rows out of every query
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
 }
 
 // TransactionHistory tracks quorum assignments for transactions
 type TransactionHistory struct {
 	ID                uint    `gorm:"primaryKey"`
-	TransactionID     string  `gorm:"index;not null"`
+	TransactionID     string  `gorm:"index;not null;size:255"`
 	TransactionAmount float64 `gorm:"not null"`
 	QuorumDIDs        string  `gorm:"type:text"` // JSON array of assigned quorum DIDs
 	RequiredBalance   float64 // 1/5th of transaction amount
+	Namespace         string  `gorm:"size:64;not null;default:'default'"` // Pool the quorums were selected from
+	Token             string  `gorm:"size:64"`                            // Token the quorums were selected for; empty on rows from before schema version 4
+	Status            string  `gorm:"size:16;index"`                      // success or failed once reported; empty while in flight
+	CompletedAt       *time.Time
 	Timestamp         time.Time
 	CreatedAt         time.Time
 }
 
+// AssignedDIDs decodes QuorumDIDs, returning an empty list when it is unset or malformed
+func (t TransactionHistory) AssignedDIDs() []string {
+	dids := []string{}
+	if t.QuorumDIDs != "" {
+		json.Unmarshal([]byte(t.QuorumDIDs), &dids)
+	}
+	return dids
+}
+
 // QuorumStats for analytics and monitoring
 type QuorumStats struct {
 	ID                uint   `gorm:"primaryKey"`
-	QuorumDID         string `gorm:"index;not null"`
+	QuorumDID         string `gorm:"index;not null;size:59"`
 	TotalTransactions int64
-	TotalAmount       float64
-	LastActive        time.Time
-	CreatedAt         time.Time
-	UpdatedAt         time.Time
+	// SuccessfulTransactions counts assignments whose transaction was reported as completed successfully
+	SuccessfulTransactions int64 `gorm:"default:0"`
+	TotalAmount            float64
+	LastActive             time.Time
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
 }
 
 // BalanceHistory tracks balance changes
 type BalanceHistory struct {
 	ID           uint   `gorm:"primaryKey"`
-	QuorumDID    string `gorm:"index;not null"`
+	QuorumDID    string `gorm:"index;not null;size:59"`
 	OldBalance   float64
 	NewBalance   float64
 	ChangeReason string
@@ -54,6 +89,62 @@ type BalanceHistory struct {
 	CreatedAt    time.Time
 }
 
+// AvailabilityEvent logs a quorum's availability transitions (registration, staleness, removal)
+type AvailabilityEvent struct {
+	ID        uint   `gorm:"primaryKey"`
+	QuorumDID string `gorm:"index;not null;size:59"`
+	Event     string `gorm:"size:32;not null"`
+	Timestamp time.Time
+	CreatedAt time.Time
+}
+
+// Availability event types
+const (
+	EventRegistered   = "registered"
+	EventReregistered = "re-registered"
+	EventAvailable    = "available"
+	EventStale        = "stale"
+	EventUnregistered = "unregistered"
+	EventDisabled     = "disabled"
+)
+
+// WebhookDeadLetter records a webhook event that could not be delivered
+type WebhookDeadLetter struct {
+	ID        uint   `gorm:"primaryKey"`
+	URL       string `gorm:"not null"`
+	EventType string `gorm:"size:64;index;not null"`
+	Payload   string `gorm:"type:text"` // JSON body that would have been POSTed
+	Attempts  int
+	LastError string `gorm:"type:text"`
+	CreatedAt time.Time
+}
+
+// WebhookSubscription is a URL that receives the quorum lifecycle events it lists
+type WebhookSubscription struct {
+	ID        uint   `gorm:"primaryKey"`
+	URL       string `gorm:"not null"`
+	Events    string `gorm:"not null"` // Comma-separated webhook event types
+	CreatedAt time.Time
+}
+
+// QuorumTombstone marks a DID deregistered with a signature; re-registering it must be
+// signed with the same key
+type QuorumTombstone struct {
+	ID        uint   `gorm:"primaryKey"`
+	DID       string `gorm:"column:did;uniqueIndex;not null;size:59"`
+	PublicKey string `gorm:"not null"` // Base64 Ed25519 key that signed the deregistration
+	Signature string `gorm:"type:text"`
+	SignedAt  time.Time
+	CreatedAt time.Time
+}
+
+// QuorumCapability is the normalized capability list used for selection filtering
+type QuorumCapability struct {
+	ID         uint   `gorm:"primaryKey"`
+	QuorumDID  string `gorm:"column:quorum_did;not null;size:59;uniqueIndex:idx_quorum_capability"`
+	Capability string `gorm:"column:capability;not null;size:32;uniqueIndex:idx_quorum_capability;index"`
+}
+
 // TableName specifies the table name for QuorumDB
 func (QuorumDB) TableName() string {
 	return "quorums"
@@ -73,3 +164,8 @@ func (QuorumStats) TableName() string {
 func (BalanceHistory) TableName() string {
 	return "balance_history"
 }
+
+// TableName specifies the table name for QuorumCapability
+func (QuorumCapability) TableName() string {
+	return "quorum_capabilities"
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// mysqlDSN builds a go-sql-driver DSN from config. Times are parsed into time.Time and
+// stored as UTC, and the connection uses utf8mb4 so any token or metadata text round-trips.
+func mysqlDSN(config DBConfig) string {
+	params := url.Values{}
+	params.Set("charset", "utf8mb4")
+	params.Set("parseTime", "true")
+	params.Set("loc", "UTC")
+	if tls := mysqlTLS(config.SSLMode); tls != "" {
+		params.Set("tls", tls)
+	}
+
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?%s",
+		config.Username, config.Password, config.Host, config.Port, config.Database, params.Encode())
+}
+
+// mysqlTLS maps a PostgreSQL-style sslmode onto the driver's tls parameter: require
+// encrypts without verifying the server certificate, verify-ca and verify-full verify it
+func mysqlTLS(sslMode string) string {
+	switch sslMode {
+	case "require", "prefer", "allow":
+		return "skip-verify"
+	case "verify-ca", "verify-full":
+		return "true"
+	default:
+		return ""
+	}
+}
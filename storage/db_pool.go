@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"fmt"
+)
+
+// PoolNotReadyError is returned by SelectQuorums when fewer quorums support the
+// requested token than its configured minimum pool size
+type PoolNotReadyError struct {
+	Token     string
+	Namespace string
+	Available int // Available, recently pinged quorums supporting Token, ignoring balance
+	Required  int
+}
+
+func (e *PoolNotReadyError) Error() string {
+	return fmt.Sprintf("%s pool in namespace %s is not ready: %d quorums available, at least %d required",
+		e.Token, e.Namespace, e.Available, e.Required)
+}
+
+// poolToken names the token a selection draws from; no ft_name means RBT
+func poolToken(ftName string) string {
+	if ftName == "" {
+		return "RBT"
+	}
+	return ftName
+}
+
+// minPoolSize returns the minimum pool size for a token: its TokenMinPoolSizes entry
+// when set, MinPoolSize otherwise
+func (ds *DBStore) minPoolSize(token string) int {
+	if size, ok := ds.config.TokenMinPoolSizes[token]; ok {
+		return size
+	}
+	return ds.config.MinPoolSize
+}
+
+// checkPoolReady refuses selection while the token's pool in the namespace is below
+// its minimum size. The pool counts available, recently pinged quorums supporting the
+// token regardless of balance, so it measures network size rather than funding.
+func (ds *DBStore) checkPoolReady(namespace, ftName string) error {
+	token := poolToken(ftName)
+	required := ds.minPoolSize(token)
+	if required <= 0 {
+		return nil
+	}
+
+	var stages []selectionStage
+	for _, stage := range ds.baseStages(namespace, 0) {
+		if stage.Name != "sufficient_balance" {
+			stages = append(stages, stage)
+		}
+	}
+
+	var available int64
+	if err := ds.applyTokenFilter(applyStages(ds.db.Model(&QuorumDB{}), stages), ftName).
+		Count(&available).Error; err != nil {
+		return err
+	}
+
+	if int(available) < required {
+		return &PoolNotReadyError{Token: token, Namespace: namespace, Available: int(available), Required: required}
+	}
+	return nil
+}
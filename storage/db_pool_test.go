@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+func TestTokenMinPoolSize(t *testing.T) {
+	ds := newTestStore(t, DBConfig{MinPoolSize: 4, TokenMinPoolSizes: map[string]int{"TRI": 3}})
+	supportsTRI := func(req *models.QuorumRegistrationRequest) { req.SupportedTokens = []string{"RBT", "TRI"} }
+	for i := 1; i <= 5; i++ {
+		if i <= 2 {
+			registerTestQuorum(t, ds, fmt.Sprint(i), 100, supportsTRI)
+		} else {
+			registerTestQuorum(t, ds, fmt.Sprint(i), 100)
+		}
+	}
+
+	// Five quorums support RBT, so its pool meets MinPoolSize
+	if _, err := ds.SelectQuorums(selectionRequest(2, 2)); err != nil {
+		t.Fatalf("RBT selection: %v", err)
+	}
+
+	// Only two support TRI, below its minimum of three, even though a TRI selection of
+	// two could be filled
+	req := selectionRequest(2, 2)
+	req.FTName = "TRI"
+	_, err := ds.SelectQuorums(req)
+	var poolErr *PoolNotReadyError
+	if !errors.As(err, &poolErr) {
+		t.Fatalf("TRI selection error = %v, want a PoolNotReadyError", err)
+	}
+	if poolErr.Token != "TRI" || poolErr.Namespace != models.DefaultNamespace || poolErr.Available != 2 || poolErr.Required != 3 {
+		t.Errorf("pool error = %+v, want TRI with 2 of 3 available", poolErr)
+	}
+
+	// A third TRI quorum makes the pool ready, its own minimum overriding MinPoolSize
+	registerTestQuorum(t, ds, "6", 100, supportsTRI)
+	if _, err := ds.SelectQuorums(req); err != nil {
+		t.Errorf("TRI selection with a ready pool: %v", err)
+	}
+}
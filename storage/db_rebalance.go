@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"math"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// Rebalance describes a corrective decay applied to one namespace's assignment counts
+type Rebalance struct {
+	Namespace       string
+	ImbalanceBefore float64 // Most- to least-assigned ratio of weighted counts before the decay
+	ImbalanceAfter  float64
+	Adjusted        int // Quorums whose assignment_count was lowered
+}
+
+// RebalanceAssignments narrows assignment skew left behind by outages or churn. For each
+// namespace whose most- to least-assigned available quorum ratio exceeds
+// MaxAssignmentImbalance, every quorum's excess over the least-assigned one is halved.
+// Counts are compared after dividing by tier weight, so intended tier skew is preserved,
+// and ratios use count+1 so a never-assigned quorum doesn't make the ratio infinite.
+// It is a no-op when MaxAssignmentImbalance is 0.
+func (ds *DBStore) RebalanceAssignments() ([]Rebalance, error) {
+	if ds.config.MaxAssignmentImbalance <= 0 {
+		return nil, nil
+	}
+
+	var rebalances []Rebalance
+	err := ds.db.Transaction(func(tx *gorm.DB) error {
+		var quorums []QuorumDB
+		if err := tx.Select("id", "namespace", "tier", "assignment_count").
+			Where("available = ?", true).
+			Find(&quorums).Error; err != nil {
+			return err
+		}
+
+		byNamespace := make(map[string][]QuorumDB)
+		for _, q := range quorums {
+			byNamespace[q.Namespace] = append(byNamespace[q.Namespace], q)
+		}
+		namespaces := make([]string, 0, len(byNamespace))
+		for namespace := range byNamespace {
+			namespaces = append(namespaces, namespace)
+		}
+		sort.Strings(namespaces)
+
+		for _, namespace := range namespaces {
+			pool := byNamespace[namespace]
+			before := ds.assignmentImbalance(pool)
+			if len(pool) < 2 || before <= ds.config.MaxAssignmentImbalance {
+				continue
+			}
+
+			floor := math.Inf(1)
+			for _, q := range pool {
+				floor = math.Min(floor, ds.weightedCount(q))
+			}
+
+			rebalance := Rebalance{Namespace: namespace, ImbalanceBefore: before}
+			for i, q := range pool {
+				weight := ds.tierWeight(q.Tier)
+				target := int64(math.Round((floor + (ds.weightedCount(q)-floor)/2) * weight))
+				if target >= q.AssignmentCount {
+					continue
+				}
+				if err := tx.Model(&QuorumDB{}).Where("id = ?", q.ID).
+					Update("assignment_count", target).Error; err != nil {
+					return err
+				}
+				pool[i].AssignmentCount = target
+				rebalance.Adjusted++
+			}
+			rebalance.ImbalanceAfter = ds.assignmentImbalance(pool)
+			rebalances = append(rebalances, rebalance)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rebalances, nil
+}
+
+// ResetAssignments sets the assignment count of the quorum did back to 0, or of every
+// quorum when did is empty, so capacity added since can be balanced against the rest
+// from a level start. It returns how many counts were changed; resetting a single DID
+// that does not exist returns ErrQuorumNotFound.
+func (ds *DBStore) ResetAssignments(did string) (int64, error) {
+	query := ds.db.Model(&QuorumDB{})
+	if did != "" {
+		var found int64
+		if err := ds.db.Model(&QuorumDB{}).Where("did = ?", did).Count(&found).Error; err != nil {
+			return 0, err
+		}
+		if found == 0 {
+			return 0, ErrQuorumNotFound
+		}
+		query = query.Where("did = ?", did)
+	}
+
+	result := query.Where("assignment_count <> ?", 0).Update("assignment_count", 0)
+	return result.RowsAffected, result.Error
+}
+
+// weightedCount is a quorum's assignment count normalized by its tier weight
+func (ds *DBStore) weightedCount(q QuorumDB) float64 {
+	return float64(q.AssignmentCount) / ds.tierWeight(q.Tier)
+}
+
+// assignmentImbalance returns the most- to least-assigned ratio of (weighted count + 1)
+func (ds *DBStore) assignmentImbalance(pool []QuorumDB) float64 {
+	if len(pool) == 0 {
+		return 1
+	}
+	lowest, highest := math.Inf(1), math.Inf(-1)
+	for _, q := range pool {
+		count := ds.weightedCount(q)
+		lowest = math.Min(lowest, count)
+		highest = math.Max(highest, count)
+	}
+	return (highest + 1) / (lowest + 1)
+}
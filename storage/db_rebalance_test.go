@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+func TestRebalanceReducesImbalance(t *testing.T) {
+	ds := newTestStore(t, DBConfig{MaxAssignmentImbalance: 3})
+	counts := map[string]int{"1": 0, "2": 10, "3": 40}
+	dids := make(map[string]string)
+	for suffix, count := range counts {
+		dids[suffix] = registerTestQuorum(t, ds, suffix, 100)
+		setQuorumColumn(t, ds, dids[suffix], "assignment_count", count)
+	}
+
+	rebalances, err := ds.RebalanceAssignments()
+	if err != nil {
+		t.Fatalf("RebalanceAssignments: %v", err)
+	}
+	if len(rebalances) != 1 {
+		t.Fatalf("rebalances = %+v, want one for the default namespace", rebalances)
+	}
+	r := rebalances[0]
+	if r.Namespace != models.DefaultNamespace || r.ImbalanceBefore != 41 || r.Adjusted != 2 || r.ImbalanceAfter >= r.ImbalanceBefore {
+		t.Errorf("rebalance = %+v, want 2 quorums adjusted from an imbalance of 41 to less", r)
+	}
+
+	// Each excess over the least-assigned quorum is halved
+	for suffix, want := range map[string]int{"1": 0, "2": 5, "3": 20} {
+		quorum, err := ds.GetQuorumByDID(dids[suffix])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if quorum.AssignmentCount != want {
+			t.Errorf("quorum %s count = %d, want %d", suffix, quorum.AssignmentCount, want)
+		}
+	}
+
+	// Repeated passes bring the pool within the limit
+	for i := 0; i < 10; i++ {
+		if rebalances, err = ds.RebalanceAssignments(); err != nil {
+			t.Fatal(err)
+		}
+		if len(rebalances) == 0 {
+			return
+		}
+	}
+	t.Errorf("pool still beyond the limit after 10 passes: %+v", rebalances)
+}
+
+func TestResetAssignments(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	dids := make(map[string]string)
+	for suffix, count := range map[string]int{"1": 3, "2": 5, "3": 0} {
+		dids[suffix] = registerTestQuorum(t, ds, suffix, 100)
+		setQuorumColumn(t, ds, dids[suffix], "assignment_count", count)
+	}
+	counts := func() map[string]int {
+		t.Helper()
+		got := make(map[string]int)
+		for suffix, did := range dids {
+			quorum, err := ds.GetQuorumByDID(did)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got[suffix] = quorum.AssignmentCount
+		}
+		return got
+	}
+
+	reset, err := ds.ResetAssignments(dids["1"])
+	if err != nil || reset != 1 {
+		t.Fatalf("ResetAssignments(1) = %d, %v; want 1 reset", reset, err)
+	}
+	if got := counts(); got["1"] != 0 || got["2"] != 5 {
+		t.Errorf("counts after resetting one quorum = %v, want only 1 reset", got)
+	}
+
+	if _, err := ds.ResetAssignments(testDID("9")); !errors.Is(err, ErrQuorumNotFound) {
+		t.Errorf("ResetAssignments(unknown) error = %v, want ErrQuorumNotFound", err)
+	}
+
+	// Resetting everything only counts the quorums that weren't already at 0
+	reset, err = ds.ResetAssignments("")
+	if err != nil || reset != 1 {
+		t.Fatalf("ResetAssignments(all) = %d, %v; want 1 reset", reset, err)
+	}
+	for suffix, count := range counts() {
+		if count != 0 {
+			t.Errorf("quorum %s count = %d after resetting all, want 0", suffix, count)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// DefaultMaxReplicaLag is how far a read replica may fall behind before readiness fails
+const DefaultMaxReplicaLag = 30 * time.Second
+
+// ErrNoReplica is returned by ReplicationLag when no read replica is configured
+var ErrNoReplica = errors.New("no read replica configured")
+
+// replicationLagQuery measures how far the replica's replay trails the primary. A replica
+// that has replayed everything it received is caught up even if its last replayed
+// transaction is old (an idle primary), so lag is only measured while WAL is pending.
+const replicationLagQuery = `SELECT CASE
+	WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+END`
+
+// openReplica connects to the read replica; schemas are migrated on the primary only
+func openReplica(replicaURL string) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(replicaURL), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to read replica: %v", err)
+	}
+	return db, nil
+}
+
+// HasReplica reports whether a read replica is configured
+func (ds *DBStore) HasReplica() bool {
+	return ds.replica != nil
+}
+
+// MaxReplicaLag returns the replication lag beyond which the service reports not ready
+func (ds *DBStore) MaxReplicaLag() time.Duration {
+	return ds.config.MaxReplicaLag
+}
+
+// ReplicationLag returns how far the read replica trails the primary
+func (ds *DBStore) ReplicationLag(ctx context.Context) (time.Duration, error) {
+	if ds.replica == nil {
+		return 0, ErrNoReplica
+	}
+
+	var seconds float64
+	if err := ds.replica.WithContext(ctx).Raw(replicationLagQuery).Scan(&seconds).Error; err != nil {
+		return 0, fmt.Errorf("failed to measure replication lag: %v", err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// reader returns the connection for read-only listings: the replica when configured,
+// the primary otherwise. Selection always reads the primary.
+func (ds *DBStore) reader() *gorm.DB {
+	if ds.replica != nil {
+		return ds.replica
+	}
+	return ds.db
+}
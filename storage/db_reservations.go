@@ -0,0 +1,237 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Errors returned by ReserveQuorums
+var (
+	ErrQuorumReserved      = errors.New("quorum is reserved by another transaction")
+	ErrInsufficientBalance = errors.New("quorum lacks spare balance for the reservation")
+	ErrQuorumUnavailable   = errors.New("quorum is not available")
+)
+
+// QuorumReservation holds one quorum out of selection for a time window, either
+// scheduled in advance or taken by a transaction for the duration of its consensus
+type QuorumReservation struct {
+	ID              uint   `gorm:"primaryKey"`
+	ReservationID   string `gorm:"index;not null;size:255"`
+	QuorumDID       string `gorm:"column:quorum_did;index;not null;size:59"`
+	Namespace       string `gorm:"size:64;not null"`
+	RequiredBalance float64
+	StartsAt        time.Time `gorm:"index;not null"`
+	EndsAt          time.Time `gorm:"index;not null"`
+	CreatedAt       time.Time
+}
+
+// MaxReservationWindow bounds how long a scheduled reservation may hold a set
+const MaxReservationWindow = 7 * 24 * time.Hour
+
+// Transaction reservation TTLs: the default when none is given, and the upper bound
+const (
+	DefaultReservationTTL = 5 * time.Minute
+	MaxReservationTTL     = time.Hour
+)
+
+// notReservedStage excludes quorums held by any reservation whose window covers now.
+// Reservations release themselves at their end time; no cleanup is needed for a held
+// quorum to become selectable again.
+func notReservedStage() selectionStage {
+	return selectionStage{
+		Name:        "not_reserved",
+		Description: "Not held by an active reservation",
+		Apply: func(query *gorm.DB) *gorm.DB {
+			now := time.Now()
+			return query.Where("did NOT IN (?)", query.Session(&gorm.Session{NewDB: true}).
+				Model(&QuorumReservation{}).
+				Select("quorum_did").
+				Where("starts_at <= ? AND ends_at > ?", now, now))
+		},
+	}
+}
+
+// ReserveScheduled picks count qualified quorums with no reservation overlapping
+// [req.Start, req.End) and records a reservation for each. Eligibility (liveness,
+// balance, token support) is judged as of now; the load-balance order is the same one
+// regular selection uses, but assignment counts are left untouched. Candidates are
+// locked as in selection, so overlapping reservations can't book the same quorum.
+func (ds *DBStore) ReserveScheduled(req models.ScheduledReservationRequest) (*models.ScheduledReservation, error) {
+	defer ds.timeOperation("reserve_scheduled", req)()
+
+	count := req.Count
+	if count <= 0 {
+		count = 7
+	}
+	namespace := selectionNamespace(req.Namespace)
+	requiredBalance := ds.RequiredBalance(req.TransactionAmount, count)
+
+	reservation := &models.ScheduledReservation{
+		ReservationID:   fmt.Sprintf("rsv_%d", time.Now().UnixNano()),
+		Start:           req.Start,
+		End:             req.End,
+		RequiredBalance: requiredBalance,
+		Namespace:       namespace,
+	}
+	overlapping := func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&QuorumReservation{}).
+			Select("quorum_did").
+			Where("starts_at < ? AND ends_at > ?", req.End, req.Start)
+	}
+
+	err := ds.assignmentTransaction(func(tx *gorm.DB) error {
+		// Quorums reserved now but free during the requested window still qualify, so
+		// the not_reserved stage gives way to an overlap check on the window itself
+		query := tx.Model(&QuorumDB{})
+		for _, stage := range ds.baseStages(namespace, requiredBalance) {
+			if stage.Name != "not_reserved" {
+				query = stage.Apply(query)
+			}
+		}
+		query = ds.applyTokenFilter(query, req.FTName).
+			Where("did NOT IN (?)", overlapping(tx.Session(&gorm.Session{NewDB: true}))).
+			Clauses(clause.Locking{Strength: "UPDATE"})
+
+		var quorums []QuorumDB
+		if err := ds.loadBalanceOrder(query).Limit(count).Find(&quorums).Error; err != nil {
+			return err
+		}
+
+		// A reservation committed while this one waited on the row locks isn't visible to
+		// the query above, so the window is checked again now that the rows are held
+		dids := make([]string, 0, len(quorums))
+		for _, q := range quorums {
+			dids = append(dids, q.DID)
+		}
+		var booked []string
+		if len(dids) > 0 {
+			if err := overlapping(tx.Session(&gorm.Session{NewDB: true})).
+				Where("quorum_did IN ?", dids).
+				Distinct().
+				Pluck("quorum_did", &booked).Error; err != nil {
+				return err
+			}
+		}
+		if len(booked) > 0 {
+			return fmt.Errorf("%w: %v", ErrQuorumReserved, booked)
+		}
+		if len(quorums) < count {
+			return fmt.Errorf("not enough unreserved quorums with required balance. Found %d, need %d (required balance: %.4f)",
+				len(quorums), count, requiredBalance)
+		}
+
+		rows := make([]QuorumReservation, 0, len(quorums))
+		reservation.Quorums = make([]models.QuorumData, 0, len(quorums))
+		for _, q := range quorums {
+			rows = append(rows, QuorumReservation{
+				ReservationID:   reservation.ReservationID,
+				QuorumDID:       q.DID,
+				Namespace:       namespace,
+				RequiredBalance: requiredBalance,
+				StartsAt:        req.Start,
+				EndsAt:          req.End,
+			})
+			reservation.Quorums = append(reservation.Quorums, toQuorumData(q))
+		}
+		return tx.Create(&rows).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// ReserveQuorums holds dids for txnID until ttl elapses, so concurrent selections
+// cannot hand the same quorums to another transaction. Each quorum must have spare
+// balance for its share of amount, be available and not disabled by an operator, and
+// must not be held under a different ID; reserving again under the same txnID refreshes
+// the expiry. Either every DID is reserved or none. The quorum rows are locked before the
+// check, so concurrent reservations of the same DIDs take turns rather than both passing.
+func (ds *DBStore) ReserveQuorums(txnID string, dids []string, amount float64, ttl time.Duration) (time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	requiredBalance := ds.RequiredBalance(amount, len(dids))
+
+	err := ds.assignmentTransaction(func(tx *gorm.DB) error {
+		var quorums []QuorumDB
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("did IN ?", dids).Order("did").Find(&quorums).Error; err != nil {
+			return err
+		}
+		found := make(map[string]QuorumDB, len(quorums))
+		for _, q := range quorums {
+			found[q.DID] = q
+		}
+		for _, did := range dids {
+			q, ok := found[did]
+			if !ok {
+				return fmt.Errorf("%w: %s", ErrQuorumNotFound, did)
+			}
+			if q.ManuallyDisabled {
+				return fmt.Errorf("%w: %s", ErrQuorumDisabled, did)
+			}
+			if !q.Available {
+				return fmt.Errorf("%w: %s", ErrQuorumUnavailable, did)
+			}
+			if !hasSpareBalance(q.Balance-q.MinParticipationBalance, requiredBalance, ds.config.BalancePrecision) {
+				return fmt.Errorf("%w: %s has %.4f spare, needs %.4f",
+					ErrInsufficientBalance, did, q.Balance-q.MinParticipationBalance, requiredBalance)
+			}
+		}
+
+		var held []string
+		if err := tx.Model(&QuorumReservation{}).
+			Where("quorum_did IN ?", dids).
+			Where("reservation_id <> ?", txnID).
+			Where("starts_at < ? AND ends_at > ?", expiresAt, now).
+			Distinct().
+			Pluck("quorum_did", &held).Error; err != nil {
+			return err
+		}
+		if len(held) > 0 {
+			return fmt.Errorf("%w: %v", ErrQuorumReserved, held)
+		}
+
+		// Replace any earlier hold by the same transaction rather than stacking rows
+		if err := tx.Where("reservation_id = ? AND quorum_did IN ?", txnID, dids).
+			Delete(&QuorumReservation{}).Error; err != nil {
+			return err
+		}
+
+		rows := make([]QuorumReservation, 0, len(dids))
+		for _, did := range dids {
+			rows = append(rows, QuorumReservation{
+				ReservationID:   txnID,
+				QuorumDID:       did,
+				Namespace:       found[did].Namespace,
+				RequiredBalance: requiredBalance,
+				StartsAt:        now,
+				EndsAt:          expiresAt,
+			})
+		}
+		return tx.Create(&rows).Error
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return expiresAt, nil
+}
+
+// ReleaseQuorums frees every quorum held under txnID, returning how many were released
+func (ds *DBStore) ReleaseQuorums(txnID string) (int64, error) {
+	result := ds.db.Where("reservation_id = ?", txnID).Delete(&QuorumReservation{})
+	return result.RowsAffected, result.Error
+}
+
+// PurgeExpiredReservations deletes reservations whose window has ended
+func (ds *DBStore) PurgeExpiredReservations() (int64, error) {
+	result := ds.db.Where("ends_at <= ?", time.Now()).Delete(&QuorumReservation{})
+	return result.RowsAffected, result.Error
+}
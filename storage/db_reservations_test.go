@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+func TestScheduledReservationWindow(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	for _, suffix := range []string{"1", "2", "3", "4"} {
+		registerTestQuorum(t, ds, suffix, 100)
+	}
+	start := time.Now().Add(time.Hour)
+	end := start.Add(time.Hour)
+
+	reservation, err := ds.ReserveScheduled(models.ScheduledReservationRequest{TransactionAmount: 2, Count: 2, Start: start, End: end})
+	if err != nil {
+		t.Fatalf("ReserveScheduled: %v", err)
+	}
+	var held []string
+	for _, q := range reservation.Quorums {
+		_, did, _ := strings.Cut(q.Address, ".")
+		held = append(held, did)
+	}
+	if len(held) != 2 {
+		t.Fatalf("reserved %v, want 2 quorums", held)
+	}
+
+	// Before the window opens the whole pool is selectable
+	if _, err := ds.SelectQuorums(selectionRequest(4, 4)); err != nil {
+		t.Fatalf("selection before the window: %v", err)
+	}
+
+	// A second set overlapping the window can't reuse the held quorums
+	if _, err := ds.ReserveScheduled(models.ScheduledReservationRequest{TransactionAmount: 3, Count: 3, Start: start.Add(30 * time.Minute), End: end.Add(time.Hour)}); err == nil {
+		t.Error("overlapping reservation of 3 succeeded with only 2 quorums free")
+	}
+	if _, err := ds.ReserveScheduled(models.ScheduledReservationRequest{TransactionAmount: 4, Count: 4, Start: end, End: end.Add(time.Hour)}); err != nil {
+		t.Errorf("reservation starting at the window's end: %v", err)
+	}
+
+	// moveWindow shifts the first reservation's window relative to now
+	moveWindow := func(startsAt, endsAt time.Time) {
+		t.Helper()
+		if err := ds.db.Model(&QuorumReservation{}).Where("reservation_id = ?", reservation.ReservationID).
+			Updates(map[string]interface{}{"starts_at": startsAt, "ends_at": endsAt}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// During the window the held quorums are excluded from other selections
+	moveWindow(time.Now().Add(-time.Minute), time.Now().Add(time.Hour))
+	if _, err := ds.SelectQuorums(selectionRequest(3, 3)); err == nil {
+		t.Error("selection of 3 succeeded during the window with only 2 quorums free")
+	}
+	result, err := ds.SelectQuorums(selectionRequest(2, 2))
+	if err != nil {
+		t.Fatalf("selection during the window: %v", err)
+	}
+	for _, did := range selectedDIDs(result) {
+		if slices.Contains(held, did) {
+			t.Errorf("selected reserved quorum %s during the window", did)
+		}
+	}
+
+	// Once the window ends they are released without any cleanup
+	moveWindow(time.Now().Add(-2*time.Hour), time.Now().Add(-time.Minute))
+	if _, err := ds.SelectQuorums(selectionRequest(4, 4)); err != nil {
+		t.Errorf("selection after the window: %v", err)
+	}
+}
+
+func TestConcurrentScheduledReservationsDontShareQuorums(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	for _, suffix := range []string{"1", "2", "3", "4"} {
+		registerTestQuorum(t, ds, suffix, 100)
+	}
+	start := time.Now().Add(time.Hour)
+	req := models.ScheduledReservationRequest{TransactionAmount: 2, Count: 2, Start: start, End: start.Add(time.Hour)}
+
+	var wg sync.WaitGroup
+	reservations := make(chan *models.ScheduledReservation, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if reservation, err := ds.ReserveScheduled(req); err == nil {
+				reservations <- reservation
+			}
+		}()
+	}
+	wg.Wait()
+	close(reservations)
+
+	booked := make(map[string]string)
+	for reservation := range reservations {
+		for _, q := range reservation.Quorums {
+			_, did, _ := strings.Cut(q.Address, ".")
+			if other, ok := booked[did]; ok {
+				t.Errorf("quorum %s booked by both %s and %s for one window", did, other, reservation.ReservationID)
+			}
+			booked[did] = reservation.ReservationID
+		}
+	}
+	if len(booked) != 4 {
+		t.Errorf("booked %d quorums, want all 4 across two reservations", len(booked))
+	}
+}
+
+func TestReserveQuorumsRefusesHeldAndDisabledQuorums(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	first, second := registerTestQuorum(t, ds, "1", 100), registerTestQuorum(t, ds, "2", 100)
+	disabled := registerTestQuorum(t, ds, "3", 100)
+	offline := registerTestQuorum(t, ds, "4", 100)
+	if err := ds.SetAvailability(disabled, false); err != nil {
+		t.Fatal(err)
+	}
+	setQuorumColumn(t, ds, offline, "available", false)
+
+	if _, err := ds.ReserveQuorums("txn-a", []string{first, disabled}, 2, time.Minute); !errors.Is(err, ErrQuorumDisabled) {
+		t.Errorf("reserving a disabled quorum: error = %v, want ErrQuorumDisabled", err)
+	}
+	if _, err := ds.ReserveQuorums("txn-a", []string{first, offline}, 2, time.Minute); !errors.Is(err, ErrQuorumUnavailable) {
+		t.Errorf("reserving an unavailable quorum: error = %v, want ErrQuorumUnavailable", err)
+	}
+
+	// Concurrent reservations of the same pair: exactly one transaction holds it
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(txnID string) {
+			defer wg.Done()
+			_, err := ds.ReserveQuorums(txnID, []string{first, second}, 2, time.Minute)
+			switch {
+			case err == nil:
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			case !errors.Is(err, ErrQuorumReserved):
+				t.Errorf("%s: %v", txnID, err)
+			}
+		}(fmt.Sprintf("txn-%d", i))
+	}
+	wg.Wait()
+	if succeeded != 1 {
+		t.Errorf("%d concurrent reservations of one pair succeeded, want 1", succeeded)
+	}
+}
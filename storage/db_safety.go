@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// MaxBalanceSafetyMultiplier bounds the balance safety multiplier, so a typo can't
+// make every quorum ineligible
+const MaxBalanceSafetyMultiplier = 10.0
+
+// ValidateBalanceSafetyMultiplier checks that multiplier is within [1, MaxBalanceSafetyMultiplier]
+func ValidateBalanceSafetyMultiplier(multiplier float64) error {
+	if multiplier < 1 || multiplier > MaxBalanceSafetyMultiplier {
+		return fmt.Errorf("balance safety multiplier must be between 1 and %v, got %v", MaxBalanceSafetyMultiplier, multiplier)
+	}
+	return nil
+}
+
+// SetBalanceSafetyMultiplier changes the factor the sufficient_balance stage applies to
+// each quorum's required balance. 1 restores the normal check. The multiplier is stored
+// in the database, so every instance sharing it applies the same headroom, and it
+// replaces the configured value until changed again.
+func (ds *DBStore) SetBalanceSafetyMultiplier(multiplier float64) error {
+	if err := ValidateBalanceSafetyMultiplier(multiplier); err != nil {
+		return err
+	}
+	return ds.putSetting(settingBalanceSafetyMultiplier, strconv.FormatFloat(multiplier, 'g', -1, 64))
+}
+
+// BalanceSafetyMultiplier returns the factor currently applied to required balances: the
+// one last set through SetBalanceSafetyMultiplier, or else the configured one. If the
+// stored value can't be read, the configured one is used.
+func (ds *DBStore) BalanceSafetyMultiplier() float64 {
+	value, ok, err := ds.getSetting(settingBalanceSafetyMultiplier)
+	if err != nil {
+		log.Printf("⚠️  Failed to read the balance safety multiplier, using %v: %v\n", ds.config.BalanceSafetyMultiplier, err)
+	}
+	if err != nil || !ok {
+		return ds.config.BalanceSafetyMultiplier
+	}
+	multiplier, err := strconv.ParseFloat(value, 64)
+	if err == nil {
+		err = ValidateBalanceSafetyMultiplier(multiplier)
+	}
+	if err != nil {
+		log.Printf("⚠️  Ignoring corrupt balance safety multiplier %q, using %v: %v\n", value, ds.config.BalanceSafetyMultiplier, err)
+		return ds.config.BalanceSafetyMultiplier
+	}
+	return multiplier
+}
+
+// EffectiveRequiredBalance is the per-quorum balance the balance check demands for a
+// share of requiredBalance: the share, raised to the configured MinQuorumBalance so tiny
+// transactions don't admit near-empty quorums. The safety multiplier applies on top.
+func (ds *DBStore) EffectiveRequiredBalance(requiredBalance float64) float64 {
+	return max(requiredBalance, ds.config.MinQuorumBalance)
+}
+
+// balanceThreshold is the spare balance (above the reserved floor) a quorum needs to
+// pass the sufficient_balance stage for a share of requiredBalance, rounded up to the
+// balance precision like the share itself
+func (ds *DBStore) balanceThreshold(requiredBalance float64) float64 {
+	return roundBalanceUp(ds.EffectiveRequiredBalance(requiredBalance)*ds.BalanceSafetyMultiplier(), ds.config.BalancePrecision)
+}
+
+// RequiredBalance is each of count quorums' share of amount, rounded up to the
+// configured balance precision
+func (ds *DBStore) RequiredBalance(amount float64, count int) float64 {
+	return RequiredBalanceShare(amount, count, ds.config.BalancePrecision)
+}
+
+// BalancePrecision is the number of decimal places required balances are rounded up to
+func (ds *DBStore) BalancePrecision() int {
+	return ds.config.BalancePrecision
+}
+
+// balanceTolerance is the slack spare balances are compared with thresholds under
+func (ds *DBStore) balanceTolerance() float64 {
+	return balanceTolerance(ds.config.BalancePrecision)
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBalanceSafetyMultiplierTightensEligibility(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	for suffix, balance := range map[string]float64{"1": 10, "2": 15, "3": 20, "4": 30} {
+		registerTestQuorum(t, ds, suffix, balance)
+	}
+
+	// Two quorums sharing 20 need 10 each, times the multiplier
+	for _, tt := range []struct {
+		multiplier float64
+		want       int64
+	}{
+		{1, 4},
+		{1.5, 3},
+		{2, 2},
+		{3.5, 0},
+		{1, 4},
+	} {
+		if err := ds.SetBalanceSafetyMultiplier(tt.multiplier); err != nil {
+			t.Fatalf("SetBalanceSafetyMultiplier(%v): %v", tt.multiplier, err)
+		}
+		eligible, _, err := ds.CountEligible(selectionRequest(2, 20))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if eligible != tt.want {
+			t.Errorf("multiplier %v: %d eligible, want %d", tt.multiplier, eligible, tt.want)
+		}
+	}
+
+	// A selection the multiplier makes impossible says so
+	if err := ds.SetBalanceSafetyMultiplier(2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ds.SelectQuorums(selectionRequest(3, 30)); err == nil || !strings.Contains(err.Error(), "2.00 safety multiplier") {
+		t.Errorf("selection error = %v, want the multiplier reported", err)
+	}
+
+	for _, invalid := range []float64{0.5, MaxBalanceSafetyMultiplier + 1} {
+		if err := ds.SetBalanceSafetyMultiplier(invalid); err == nil {
+			t.Errorf("SetBalanceSafetyMultiplier(%v) succeeded", invalid)
+		}
+	}
+	if got := ds.BalanceSafetyMultiplier(); got != 2 {
+		t.Errorf("multiplier after rejected changes = %v, want 2", got)
+	}
+
+	// The configured multiplier is the starting value
+	configured := newTestStore(t, DBConfig{BalanceSafetyMultiplier: 1.5})
+	if got := configured.BalanceSafetyMultiplier(); got != 1.5 {
+		t.Errorf("configured multiplier = %v, want 1.5", got)
+	}
+}
+
+func TestBalanceSafetyMultiplierIsSharedBetweenInstances(t *testing.T) {
+	ds := newTestStore(t, DBConfig{BalanceSafetyMultiplier: 1.5})
+	replica, err := NewDBStore(ds.config)
+	if err != nil {
+		t.Fatalf("NewDBStore: %v", err)
+	}
+	t.Cleanup(func() { replica.Close() })
+
+	// A multiplier set through one instance replaces the configured one on every instance
+	if err := ds.SetBalanceSafetyMultiplier(2); err != nil {
+		t.Fatal(err)
+	}
+	if got := replica.BalanceSafetyMultiplier(); got != 2 {
+		t.Errorf("replica multiplier = %v, want 2", got)
+	}
+	if err := replica.SetBalanceSafetyMultiplier(1); err != nil {
+		t.Fatal(err)
+	}
+	if got := ds.BalanceSafetyMultiplier(); got != 1 {
+		t.Errorf("multiplier after the replica cleared it = %v, want 1", got)
+	}
+}
@@ -0,0 +1,1025 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gklps/advisory-node/audit"
+	"github.com/gklps/advisory-node/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrTransactionNotFound is returned when a transaction ID has no recorded assignment
+var ErrTransactionNotFound = errors.New("transaction not found")
+
+// ErrTokenMismatch is returned when a replacement asks for a different token than the
+// transaction's quorums were selected for
+var ErrTokenMismatch = errors.New("token does not match the transaction")
+
+// ErrAssignmentNotRecorded is returned when a selection's assignment bookkeeping could
+// not be persisted; the selection is abandoned rather than returned
+var ErrAssignmentNotRecorded = errors.New("assignment not recorded")
+
+// SelectionResult is the outcome of a successful quorum selection
+type SelectionResult struct {
+	TransactionID   string
+	RequiredBalance float64
+	// Balance the check demanded: RequiredBalance raised to the minimum quorum balance
+	EffectiveRequiredBalance float64
+	SafetyMultiplier         float64 // Factor the balance check applied to EffectiveRequiredBalance
+	Quorums                  []models.QuorumData
+	Selected                 []models.SelectedQuorum // Quorums with the details they were ranked by
+}
+
+// selectionStage is one named predicate of the selection pipeline
+type selectionStage struct {
+	Name        string
+	Description string
+	Apply       func(query *gorm.DB) *gorm.DB
+}
+
+// baseStages are the namespace, liveness, balance and reservation predicates every
+// selection applies
+func (ds *DBStore) baseStages(namespace string, requiredBalance float64) []selectionStage {
+	effective := ds.EffectiveRequiredBalance(requiredBalance)
+	multiplier := ds.BalanceSafetyMultiplier()
+	threshold := ds.balanceThreshold(requiredBalance)
+	balanceDescription := fmt.Sprintf("Balance above reserved floor >= %.4f", threshold)
+	if multiplier != 1 {
+		balanceDescription = fmt.Sprintf("Balance above reserved floor >= %.4f (%.4f x %.2f safety multiplier)",
+			threshold, effective, multiplier)
+	}
+	if effective != requiredBalance {
+		balanceDescription += fmt.Sprintf(", minimum quorum balance %.4f", effective)
+	}
+
+	return []selectionStage{
+		{
+			Name:        "namespace",
+			Description: fmt.Sprintf("In namespace %s", namespace),
+			Apply: func(query *gorm.DB) *gorm.DB {
+				return query.Where("namespace = ?", namespace)
+			},
+		},
+		{
+			Name:        "available",
+			Description: "Marked available",
+			Apply: func(query *gorm.DB) *gorm.DB {
+				return query.Where("available = ?", true)
+			},
+		},
+		{
+			Name:        "recent_heartbeat",
+			Description: ds.heartbeatStageDescription(),
+			Apply:       ds.applyHeartbeatFilter,
+		},
+		{
+			Name:        "sufficient_balance",
+			Description: balanceDescription,
+			Apply: func(query *gorm.DB) *gorm.DB {
+				// Only quorums with sufficient spare balance, plus any safety headroom
+				return query.Where("balance - min_participation_balance >= ?", threshold-ds.balanceTolerance())
+			},
+		},
+		{
+			Name:        "within_share_cap",
+			Description: fmt.Sprintf("No max_transaction_share below %.4f", requiredBalance),
+			Apply: func(query *gorm.DB) *gorm.DB {
+				// Respect the node's own per-transaction risk limit
+				return query.Where("max_transaction_share = 0 OR max_transaction_share >= ?", requiredBalance)
+			},
+		},
+		notReservedStage(),
+	}
+}
+
+// heartbeatStageDescription describes the recent_heartbeat stage, noting an active freeze
+func (ds *DBStore) heartbeatStageDescription() string {
+	if until, frozen, err := ds.StalenessFrozenUntil(); err == nil && frozen {
+		return fmt.Sprintf("Heartbeat check frozen until %s", until.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("Pinged within the last %s", ds.config.AvailabilityWindow)
+}
+
+// selectionStages returns every predicate selection applies for req, in order: the
+// base stages followed by the token filter and whichever optional filters are set
+func (ds *DBStore) selectionStages(req models.QuorumListRequest, requiredBalance float64) []selectionStage {
+	stages := ds.baseStages(selectionNamespace(req.Namespace), requiredBalance)
+
+	token := req.FTName
+	if token == "" {
+		token = "RBT"
+	}
+	tokenDescription := fmt.Sprintf("Supports %s", token)
+	if emptyTokensSupport(ds.config.EmptyTokensMeans, req.FTName) {
+		tokenDescription += " (or declares no tokens)"
+	}
+	stages = append(stages, selectionStage{
+		Name:        "token_supported",
+		Description: tokenDescription,
+		Apply: func(query *gorm.DB) *gorm.DB {
+			return ds.applyTokenFilter(query, req.FTName)
+		},
+	})
+
+	if len(req.Requires) > 0 {
+		stages = append(stages, selectionStage{
+			Name:        "capabilities",
+			Description: fmt.Sprintf("Has capabilities [%s]", strings.Join(req.Requires, ", ")),
+			Apply: func(query *gorm.DB) *gorm.DB {
+				return applyCapabilityFilter(query, req.Requires)
+			},
+		})
+	}
+
+	if req.MinTier != "" {
+		stages = append(stages, selectionStage{
+			Name:        "min_tier",
+			Description: fmt.Sprintf("Collateral tier %s or higher", req.MinTier),
+			Apply: func(query *gorm.DB) *gorm.DB {
+				return query.Where("tier IN ?", ds.tiersAtLeast(req.MinTier))
+			},
+		})
+	}
+
+	if req.MinSuccessfulTxns > 0 {
+		stages = append(stages, selectionStage{
+			Name:        "proven",
+			Description: fmt.Sprintf("At least %d successful transactions", req.MinSuccessfulTxns),
+			Apply: func(query *gorm.DB) *gorm.DB {
+				// Only "proven" quorums with enough successfully completed transactions
+				return query.Where("did IN (?)", ds.db.Model(&QuorumStats{}).
+					Select("quorum_d_id").
+					Where("successful_transactions >= ?", req.MinSuccessfulTxns))
+			},
+		})
+	}
+
+	if req.DIDType != nil {
+		didType := *req.DIDType
+		stages = append(stages, selectionStage{
+			Name:        "did_type",
+			Description: fmt.Sprintf("DID type %d", didType),
+			Apply: func(query *gorm.DB) *gorm.DB {
+				return query.Where("did_type = ?", didType)
+			},
+		})
+	}
+
+	// Filter by last character if provided (only for non-TRI tokens to maintain TRI consistency)
+	if req.LastCharTID != "" && req.FTName != "TRI" {
+		stages = append(stages, selectionStage{
+			Name:        "last_char_tid",
+			Description: fmt.Sprintf("DID ends with %q", req.LastCharTID),
+			Apply: func(query *gorm.DB) *gorm.DB {
+				return query.Where("did LIKE ?", "%"+req.LastCharTID)
+			},
+		})
+	}
+
+	return stages
+}
+
+// applyStages chains stage predicates onto query
+func applyStages(query *gorm.DB, stages []selectionStage) *gorm.DB {
+	for _, stage := range stages {
+		query = stage.Apply(query)
+	}
+	return query
+}
+
+// eligibleQuorumsQuery builds the base selection query on db (the store or an open
+// transaction): available, recently pinged quorums in namespace holding at least
+// requiredBalance above their own reserved floor
+func (ds *DBStore) eligibleQuorumsQuery(db *gorm.DB, namespace string, requiredBalance float64) *gorm.DB {
+	return applyStages(db.Model(&QuorumDB{}), ds.baseStages(selectionNamespace(namespace), requiredBalance))
+}
+
+// selectionNamespace maps an unset namespace to the default pool
+func selectionNamespace(namespace string) string {
+	if namespace == "" {
+		return models.DefaultNamespace
+	}
+	return namespace
+}
+
+// applyTokenFilter restricts a quorum query to quorums supporting ftName (RBT when
+// empty), matched against quorum_tokens. Token names are stored upper-cased, so ftName
+// matches case-insensitively on every dialect. Quorums without a token list match
+// according to the EmptyTokensMeans policy.
+func (ds *DBStore) applyTokenFilter(query *gorm.DB, ftName string) *gorm.DB {
+	token := strings.ToUpper(strings.TrimSpace(ftName))
+	if token == "" {
+		token = "RBT"
+	}
+
+	tokens := func() *gorm.DB {
+		return query.Session(&gorm.Session{NewDB: true}).Model(&QuorumToken{}).Select("quorum_did")
+	}
+	if emptyTokensSupport(ds.config.EmptyTokensMeans, ftName) {
+		return query.Where("did IN (?) OR did NOT IN (?)", tokens().Where("token = ?", token), tokens())
+	}
+	return query.Where("did IN (?)", tokens().Where("token = ?", token))
+}
+
+// applyCapabilityFilter restricts a quorum query to quorums having every listed capability
+func applyCapabilityFilter(query *gorm.DB, requires []string) *gorm.DB {
+	if len(requires) == 0 {
+		return query
+	}
+	return query.Where("did IN (?)", query.Session(&gorm.Session{NewDB: true}).
+		Model(&QuorumCapability{}).
+		Select("quorum_did").
+		Where("capability IN ?", requires).
+		Group("quorum_did").
+		Having("COUNT(DISTINCT capability) = ?", len(requires)))
+}
+
+// loadBalanceOrder applies the ORDER BY used for load-balanced selection: least-assigned
+// (relative to collateral tier weight) first, optionally preferring fresher heartbeats
+// among quorums with equal weighted counts
+func (ds *DBStore) loadBalanceOrder(query *gorm.DB) *gorm.DB {
+	query = query.Order(ds.weightedAssignmentOrder())
+	if ds.config.PreferFreshHeartbeats {
+		query = query.Order("last_ping DESC")
+	}
+	return query.Order("last_assignment ASC")
+}
+
+// sortByLoad re-applies loadBalanceOrder to candidates already read. Under FOR UPDATE,
+// Postgres sorts before waiting on row locks and then re-checks each locked row against
+// the version committed by the selection that held it (EvalPlanQual), without sorting
+// again, so rows can come back out of order. Sorting in Go ranks on the counts actually
+// returned, whatever the database's locking behavior.
+func (ds *DBStore) sortByLoad(candidates []QuorumDB) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		wa, wb := float64(a.AssignmentCount)/ds.tierWeight(a.Tier), float64(b.AssignmentCount)/ds.tierWeight(b.Tier)
+		if wa != wb {
+			return wa < wb
+		}
+		if ds.config.PreferFreshHeartbeats && !a.LastPing.Equal(b.LastPing) {
+			return a.LastPing.After(b.LastPing)
+		}
+		return a.LastAssignment.Before(b.LastAssignment)
+	})
+}
+
+// SelectQuorums selects and assigns quorums for a transaction, applying every
+// optional filter carried by the request on top of the balance and token checks
+func (ds *DBStore) SelectQuorums(req models.QuorumListRequest) (*SelectionResult, error) {
+	defer ds.timeOperation("select_quorums", req)()
+
+	result, err := ds.selectQuorums(req)
+	if err != nil {
+		ds.recordSelectionFailure(req, err)
+		return nil, err
+	}
+	if ds.config.Auditor != nil {
+		ds.auditSelection(req, result)
+	}
+	return result, nil
+}
+
+// auditSelection hands a successful selection to the configured auditor. The stage
+// breakdown is re-evaluated after the fact; none of the stages depend on assignment
+// counts, so it reflects the pool the selection saw barring concurrent changes.
+func (ds *DBStore) auditSelection(req models.QuorumListRequest, result *SelectionResult) {
+	record := audit.Record{
+		Timestamp:       time.Now(),
+		TransactionID:   result.TransactionID,
+		Request:         req,
+		RequiredBalance: result.RequiredBalance,
+		Quorums:         result.Quorums,
+	}
+
+	var err error
+	record.TotalQuorums, record.Stages, err = ds.stageBreakdown(req, result.RequiredBalance)
+	if err != nil {
+		// Still audit the decision itself; the breakdown is supplementary
+		log.Printf("⚠️  Failed to compute stage breakdown for audit of %s: %v\n", result.TransactionID, err)
+	}
+
+	ds.config.Auditor.AuditSelection(record)
+}
+
+// assignmentTransaction runs a select-then-assign operation in one transaction. Its
+// selection query locks the rows it reads (FOR UPDATE) on Postgres and MySQL. SQLite has
+// no row locks, and a second transaction upgrading from read to write fails with
+// SQLITE_BUSY, so there these operations are serialized in process instead; SQLite
+// deployments are single-instance (see WithCleanupLock).
+func (ds *DBStore) assignmentTransaction(fn func(tx *gorm.DB) error) error {
+	if ds.config.Type == "sqlite" {
+		ds.assignMu.Lock()
+		defer ds.assignMu.Unlock()
+	}
+	return ds.db.Transaction(fn)
+}
+
+func (ds *DBStore) selectQuorums(req models.QuorumListRequest) (*SelectionResult, error) {
+	count := req.Count
+	transactionAmount := req.TransactionAmount
+	ftName := req.FTName
+
+	if count <= 0 {
+		count = 7
+	}
+
+	// Calculate required balance (transaction amount divided by number of quorums)
+	requiredBalance := ds.RequiredBalance(transactionAmount, count)
+	effective := ds.EffectiveRequiredBalance(requiredBalance)
+	multiplier := ds.BalanceSafetyMultiplier()
+
+	if err := ds.checkPoolReady(selectionNamespace(req.Namespace), ftName); err != nil {
+		return nil, err
+	}
+
+	// The transaction ID seeds the tie-break, so it is fixed before ranking
+	transactionID := req.TransactionID
+	if transactionID == "" {
+		transactionID = fmt.Sprintf("txn_%d", time.Now().UnixNano())
+	}
+
+	var result []models.QuorumData
+	var selected []models.SelectedQuorum
+
+	// Ranking and assignment happen in one transaction with the candidates locked, so
+	// concurrent selections can't rank on counts another is about to bump. Assignment
+	// bookkeeping (counts and usage stats) and the history record persist together or
+	// not at all, so a failed write can't leave load balancing drifting behind what was
+	// handed out.
+	err := ds.assignmentTransaction(func(tx *gorm.DB) error {
+		query := applyStages(tx.Model(&QuorumDB{}), ds.selectionStages(req, requiredBalance)).
+			Clauses(clause.Locking{Strength: "UPDATE"})
+		candidates, err := ds.rankCandidates(query, req, count, transactionID)
+		if err != nil {
+			return err
+		}
+		quorums, err := pickQuorums(candidates, count, req.MinRegions)
+		if err != nil {
+			return err
+		}
+
+		if len(quorums) < count {
+			if multiplier != 1 {
+				return fmt.Errorf("not enough quorums with required balance. Found %d, need %d (required balance: %.4f x %.2f safety multiplier)",
+					len(quorums), count, effective, multiplier)
+			}
+			return fmt.Errorf("not enough quorums with required balance. Found %d, need %d (required balance: %.4f)",
+				len(quorums), count, effective)
+		}
+
+		result = make([]models.QuorumData, 0, count)
+		selected = make([]models.SelectedQuorum, 0, count)
+		quorumDIDs := make([]string, 0, count)
+		for _, q := range quorums {
+			result = append(result, toQuorumData(q))
+			selected = append(selected, toSelectedQuorum(q))
+			quorumDIDs = append(quorumDIDs, q.DID)
+		}
+
+		now := time.Now()
+		var failed []string
+		assignments := make([]QuorumAssignment, 0, len(quorums))
+		for _, q := range quorums {
+			if err := tx.Model(&q).Updates(map[string]interface{}{
+				"assignment_count": gorm.Expr("assignment_count + 1"),
+				"last_assignment":  now,
+			}).Error; err != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", q.DID, err))
+				continue
+			}
+			if err := recordAssignmentStats(tx, q.DID, requiredBalance, now); err != nil {
+				failed = append(failed, fmt.Sprintf("%s stats: %v", q.DID, err))
+			}
+			assignments = append(assignments, QuorumAssignment{TransactionID: transactionID, QuorumDID: q.DID, Timestamp: now})
+		}
+		if len(failed) > 0 {
+			return fmt.Errorf("%w: update failed for %d quorums: %s", ErrAssignmentNotRecorded, len(failed), strings.Join(failed, "; "))
+		}
+		if err := tx.Create(&assignments).Error; err != nil {
+			return fmt.Errorf("%w: assignments: %v", ErrAssignmentNotRecorded, err)
+		}
+
+		quorumDIDsJSON, _ := json.Marshal(quorumDIDs)
+		history := TransactionHistory{
+			TransactionID:     transactionID,
+			TransactionAmount: transactionAmount,
+			QuorumDIDs:        string(quorumDIDsJSON),
+			RequiredBalance:   requiredBalance,
+			Namespace:         selectionNamespace(req.Namespace),
+			Token:             poolToken(req.FTName),
+			Timestamp:         now,
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			return fmt.Errorf("%w: transaction history: %v", ErrAssignmentNotRecorded, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SelectionResult{
+		TransactionID:            transactionID,
+		RequiredBalance:          requiredBalance,
+		EffectiveRequiredBalance: effective,
+		SafetyMultiplier:         multiplier,
+		Quorums:                  result,
+		Selected:                 selected,
+	}, nil
+}
+
+// rankCandidates loads the quorums matching query, which has every selection stage
+// applied, in the order selection takes them: least loaded first (or, for TRI, by
+// selection key) with ties broken by transactionID, then the requested region first and
+// newcomers held to the ramp's share
+func (ds *DBStore) rankCandidates(query *gorm.DB, req models.QuorumListRequest, count int, transactionID string) ([]QuorumDB, error) {
+	if req.FTName != "TRI" {
+		// For other tokens, use load balancing
+		query = ds.loadBalanceOrder(query)
+	}
+
+	// Ranking, region spreading and the new-node ramp need the whole candidate
+	// list, not just the first count
+	var candidates []QuorumDB
+	if err := query.Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	if req.FTName == "TRI" {
+		// TRI validator sets must agree across nodes, so they are ranked by the
+		// selection key rather than by load
+		rendezvousOrder(candidates, triSelectionKey(req))
+	} else {
+		ds.sortByLoad(candidates)
+		ds.breakTies(candidates, transactionID)
+	}
+	if req.Region != "" {
+		// Before the ramp, so its cap still holds when newcomers are local
+		candidates = preferRegion(candidates, req.Region)
+	}
+	if ds.config.NewNodeRamp > 0 {
+		candidates = rampNewQuorums(candidates, count, time.Now().Add(-ds.config.NewNodeRamp), ds.config.NewNodeMaxShare)
+	}
+	return candidates, nil
+}
+
+// pickQuorums takes the first count ranked candidates, spread across minRegions regions
+// when that is above 1. Fewer than count are returned when there aren't enough.
+func pickQuorums(candidates []QuorumDB, count, minRegions int) ([]QuorumDB, error) {
+	if minRegions > 1 {
+		return spreadAcrossRegions(candidates, count, minRegions)
+	}
+	return candidates[:min(count, len(candidates))], nil
+}
+
+// breakTies reorders runs of equally ranked candidates (same weighted assignment count,
+// last assignment and, with PreferFreshHeartbeats, last ping) by a hash of the
+// transaction ID and DID. A retry with the same transaction ID against an unchanged
+// pool then picks the same quorums, while different transactions spread across ties.
+func (ds *DBStore) breakTies(candidates []QuorumDB, transactionID string) {
+	sameRank := func(a, b QuorumDB) bool {
+		if float64(a.AssignmentCount)/ds.tierWeight(a.Tier) != float64(b.AssignmentCount)/ds.tierWeight(b.Tier) {
+			return false
+		}
+		if ds.config.PreferFreshHeartbeats && !a.LastPing.Equal(b.LastPing) {
+			return false
+		}
+		return a.LastAssignment.Equal(b.LastAssignment)
+	}
+
+	for start := 0; start < len(candidates); {
+		end := start + 1
+		for end < len(candidates) && sameRank(candidates[start], candidates[end]) {
+			end++
+		}
+		if end-start > 1 {
+			run := candidates[start:end]
+			sort.SliceStable(run, func(i, j int) bool {
+				return tieBreakKey(transactionID, run[i].DID) < tieBreakKey(transactionID, run[j].DID)
+			})
+		}
+		start = end
+	}
+}
+
+// tieBreakKey hashes a DID with the transaction ID into a stable ordering key
+func tieBreakKey(transactionID, did string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(transactionID))
+	h.Write([]byte{0})
+	h.Write([]byte(did))
+	return h.Sum64()
+}
+
+// triSelectionKey is the key TRI validator sets are hashed on: the caller-supplied
+// transaction ID, else last_char_tid. Without either every TRI selection shares one
+// stable set.
+func triSelectionKey(req models.QuorumListRequest) string {
+	if req.TransactionID != "" {
+		return req.TransactionID
+	}
+	return req.LastCharTID
+}
+
+// rendezvousOrder sorts candidates by highest random weight for key (rendezvous
+// hashing). Each quorum's rank depends only on key and its own DID, so the first count
+// are the same on every node with the same pool; a quorum joining or leaving only
+// changes the set if it ranks (or ranked) within it, displacing a single member.
+func rendezvousOrder(candidates []QuorumDB, key string) {
+	sort.Slice(candidates, func(i, j int) bool {
+		wi, wj := tieBreakKey(key, candidates[i].DID), tieBreakKey(key, candidates[j].DID)
+		if wi != wj {
+			return wi > wj
+		}
+		return candidates[i].DID < candidates[j].DID
+	})
+}
+
+// toQuorumData formats a quorum as expected by RubixGo (PeerID.DID)
+func toQuorumData(q QuorumDB) models.QuorumData {
+	return models.QuorumData{
+		Type:    2,
+		Address: q.PeerID + "." + q.DID,
+	}
+}
+
+// toSelectedQuorum formats a quorum with the details selection ranked it by
+func toSelectedQuorum(q QuorumDB) models.SelectedQuorum {
+	data := toQuorumData(q)
+	return models.SelectedQuorum{
+		Type:            data.Type,
+		Address:         data.Address,
+		DID:             q.DID,
+		Balance:         q.Balance,
+		AssignmentCount: int(q.AssignmentCount),
+		DIDType:         q.DIDType,
+		Region:          q.Region,
+	}
+}
+
+// preferRegion moves ranked candidates in region ahead of the others, keeping rank order
+// within both, so quorums from other regions only fill the slots region can't
+func preferRegion(candidates []QuorumDB, region string) []QuorumDB {
+	ordered := make([]QuorumDB, 0, len(candidates))
+	var elsewhere []QuorumDB
+	for _, q := range candidates {
+		if q.Region == region {
+			ordered = append(ordered, q)
+		} else {
+			elsewhere = append(elsewhere, q)
+		}
+	}
+	return append(ordered, elsewhere...)
+}
+
+// spreadAcrossRegions picks count quorums from ranked candidates so that at least
+// minRegions distinct regions are represented. The best-ranked quorum of each region
+// is taken first, then the remaining slots are filled in rank order.
+func spreadAcrossRegions(candidates []QuorumDB, count int, minRegions int) ([]QuorumDB, error) {
+	selected := make([]QuorumDB, 0, min(count, len(candidates)))
+	taken := make(map[int]bool)
+	seenRegions := make(map[string]bool)
+
+	for i, q := range candidates {
+		if len(seenRegions) >= minRegions || len(selected) >= count {
+			break
+		}
+		if q.Region == "" || seenRegions[q.Region] {
+			continue
+		}
+		seenRegions[q.Region] = true
+		taken[i] = true
+		selected = append(selected, q)
+	}
+
+	if len(seenRegions) < minRegions {
+		return nil, fmt.Errorf("not enough regions in eligible pool. Found %d, need %d", len(seenRegions), minRegions)
+	}
+
+	for i, q := range candidates {
+		if len(selected) >= count {
+			break
+		}
+		if !taken[i] {
+			selected = append(selected, q)
+		}
+	}
+
+	return selected, nil
+}
+
+// DefaultNewNodeMaxShare is the share of a selection that quorums still inside the
+// new-node ramp may fill together when no share is configured
+const DefaultNewNodeMaxShare = 0.25
+
+// rampNewQuorums reorders ranked candidates so that quorums registered after cutoff
+// fill at most maxShare of the first count slots (always allowing one). Newcomers over
+// the cap move to the end of the list rather than being dropped, so a pool made up
+// mostly of new quorums can still fill the selection.
+func rampNewQuorums(candidates []QuorumDB, count int, cutoff time.Time, maxShare float64) []QuorumDB {
+	maxNew := max(1, int(float64(count)*maxShare))
+
+	ranked := make([]QuorumDB, 0, len(candidates))
+	var deferred []QuorumDB
+	newTaken := 0
+	for _, q := range candidates {
+		if q.CreatedAt.After(cutoff) {
+			if newTaken >= maxNew {
+				deferred = append(deferred, q)
+				continue
+			}
+			newTaken++
+		}
+		ranked = append(ranked, q)
+	}
+
+	return append(ranked, deferred...)
+}
+
+// SelectReplacement picks a single quorum to replace a failed member of an already
+// assigned set. The replacement must meet the transaction's original required balance
+// and token filter, and may not be the failed quorum, any quorum already assigned to
+// the transaction, or any DID listed in exclude. The transaction history is updated to
+// reflect the swap.
+func (ds *DBStore) SelectReplacement(req *models.QuorumReplaceRequest) (*models.QuorumData, error) {
+	defer ds.timeOperation("select_replacement", req)()
+
+	var replacement QuorumDB
+
+	err := ds.assignmentTransaction(func(tx *gorm.DB) error {
+		var history TransactionHistory
+		if err := tx.Where("transaction_id = ?", req.TransactionID).
+			Order("created_at DESC").
+			First(&history).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrTransactionNotFound
+			}
+			return err
+		}
+
+		// The replacement must support the token the set was selected for; rows recorded
+		// before tokens were fall back to the request's
+		token := history.Token
+		if token == "" {
+			token = req.FTName
+		} else if req.FTName != "" && !strings.EqualFold(strings.TrimSpace(req.FTName), token) {
+			return fmt.Errorf("%w: %s was selected for %s, not %s", ErrTokenMismatch, req.TransactionID, token, req.FTName)
+		}
+
+		assigned := history.AssignedDIDs()
+
+		excluded := append([]string{req.FailedDID}, req.Exclude...)
+		excluded = append(excluded, assigned...)
+
+		query := ds.loadBalanceOrder(ds.applyTokenFilter(ds.eligibleQuorumsQuery(tx, history.Namespace, history.RequiredBalance), token).
+			Where("did NOT IN ?", excluded)).
+			Clauses(clause.Locking{Strength: "UPDATE"})
+		if err := query.First(&replacement).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("no qualified replacement quorum available (required balance: %.4f)", history.RequiredBalance)
+			}
+			return err
+		}
+
+		if err := tx.Model(&replacement).Updates(map[string]interface{}{
+			"assignment_count": gorm.Expr("assignment_count + 1"),
+			"last_assignment":  time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+		if err := recordAssignmentStats(tx, replacement.DID, history.RequiredBalance, time.Now()); err != nil {
+			return err
+		}
+		if err := tx.Create(&QuorumAssignment{
+			TransactionID: req.TransactionID,
+			QuorumDID:     replacement.DID,
+			Timestamp:     time.Now(),
+		}).Error; err != nil {
+			return err
+		}
+
+		// Swap the failed DID for the replacement in the recorded set
+		updated := make([]string, 0, len(assigned)+1)
+		swapped := false
+		for _, did := range assigned {
+			if did == req.FailedDID {
+				did = replacement.DID
+				swapped = true
+			}
+			updated = append(updated, did)
+		}
+		if !swapped {
+			updated = append(updated, replacement.DID)
+		}
+		quorumDIDsJSON, _ := json.Marshal(updated)
+
+		return tx.Model(&history).Update("QuorumDIDs", string(quorumDIDsJSON)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := toQuorumData(replacement)
+	return &data, nil
+}
+
+// DiagnoseAvailability evaluates the selection pipeline for req one stage at a time,
+// reporting how many quorums survive each successive filter. It is read-only: nothing
+// is assigned and no transaction history is recorded.
+func (ds *DBStore) DiagnoseAvailability(req models.QuorumListRequest) (*models.AvailabilityDiagnosis, error) {
+	defer ds.timeOperation("diagnose_availability", req)()
+
+	count := req.Count
+	if count <= 0 {
+		count = 7
+	}
+	requiredBalance := ds.RequiredBalance(req.TransactionAmount, count)
+
+	diagnosis := &models.AvailabilityDiagnosis{
+		RequestedCount:  count,
+		RequiredBalance: requiredBalance,
+	}
+
+	var err error
+	diagnosis.TotalQuorums, diagnosis.Stages, err = ds.stageBreakdown(req, requiredBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	diagnosis.EligibleCount = diagnosis.TotalQuorums
+	if len(diagnosis.Stages) > 0 {
+		diagnosis.EligibleCount = diagnosis.Stages[len(diagnosis.Stages)-1].Remaining
+	}
+	diagnosis.Satisfiable = diagnosis.EligibleCount >= int64(count)
+	return diagnosis, nil
+}
+
+// stageBreakdown counts the quorum pool and how many quorums survive each successive
+// selection stage for req
+func (ds *DBStore) stageBreakdown(req models.QuorumListRequest, requiredBalance float64) (int64, []models.DiagnosisStage, error) {
+	query := ds.db.Model(&QuorumDB{}).Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, nil, err
+	}
+
+	remaining := total
+	var stages []models.DiagnosisStage
+	for _, stage := range ds.selectionStages(req, requiredBalance) {
+		query = stage.Apply(query).Session(&gorm.Session{})
+
+		var passed int64
+		if err := query.Count(&passed).Error; err != nil {
+			return 0, nil, err
+		}
+
+		stages = append(stages, models.DiagnosisStage{
+			Stage:       stage.Name,
+			Description: stage.Description,
+			Remaining:   passed,
+			Excluded:    remaining - passed,
+		})
+		remaining = passed
+	}
+
+	return total, stages, nil
+}
+
+// ExplainSelection runs the selection for req without assigning anything. Every quorum
+// in the request's namespace is checked against each selection stage on its own, and
+// the ones passing all of them are ranked and picked as selection would. Quorums are
+// listed selected first, then the other eligible ones in rank order, then the excluded
+// ones with the fewest failed gates first, up to limit.
+func (ds *DBStore) ExplainSelection(req models.QuorumListRequest, limit int) (*models.SelectionExplanation, error) {
+	defer ds.timeOperation("explain_selection", req)()
+
+	count := req.Count
+	if count <= 0 {
+		count = 7
+	}
+	requiredBalance := ds.RequiredBalance(req.TransactionAmount, count)
+	namespace := selectionNamespace(req.Namespace)
+
+	// Without a transaction ID, ties are broken as for a selection that didn't send one
+	transactionID := req.TransactionID
+	if transactionID == "" {
+		transactionID = fmt.Sprintf("txn_%d", time.Now().UnixNano())
+	}
+
+	explanation := &models.SelectionExplanation{
+		RequestedCount:  count,
+		RequiredBalance: requiredBalance,
+		TransactionID:   transactionID,
+		Namespace:       namespace,
+	}
+
+	var quorums []QuorumDB
+	if err := ds.db.Where("namespace = ?", namespace).Order("did ASC").Find(&quorums).Error; err != nil {
+		return nil, err
+	}
+	explanation.TotalQuorums = len(quorums)
+
+	// Gates are the selection stages other than the namespace, which every listed quorum
+	// is already in
+	stages := ds.selectionStages(req, requiredBalance)
+	var gates []selectionStage
+	passed := make(map[string]map[string]bool, len(stages))
+	for _, stage := range stages {
+		if stage.Name == "namespace" {
+			continue
+		}
+		var dids []string
+		if err := stage.Apply(ds.db.Model(&QuorumDB{}).Where("namespace = ?", namespace)).
+			Pluck("did", &dids).Error; err != nil {
+			return nil, err
+		}
+		passing := make(map[string]bool, len(dids))
+		for _, did := range dids {
+			passing[did] = true
+		}
+		gates = append(gates, stage)
+		passed[stage.Name] = passing
+		explanation.Gates = append(explanation.Gates, models.GateSummary{
+			Gate:        stage.Name,
+			Description: stage.Description,
+			Passed:      len(passing),
+		})
+	}
+
+	candidates, err := ds.rankCandidates(applyStages(ds.db.Model(&QuorumDB{}), stages), req, count, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	rank := make(map[string]int, len(candidates))
+	for i, q := range candidates {
+		rank[q.DID] = i + 1
+	}
+	explanation.EligibleCount = len(candidates)
+
+	// A selection that would fail assigns nothing, so no quorum is reported as selected
+	var selected map[string]bool
+	var poolErr *PoolNotReadyError
+	if err := ds.checkPoolReady(namespace, req.FTName); errors.As(err, &poolErr) {
+		explanation.Error = err.Error()
+	} else if err != nil {
+		return nil, err
+	} else if picked, err := pickQuorums(candidates, count, req.MinRegions); err != nil {
+		explanation.Error = err.Error()
+	} else if len(picked) < count {
+		explanation.Error = fmt.Sprintf("not enough quorums passed every gate. Found %d, need %d", len(picked), count)
+	} else {
+		selected = make(map[string]bool, len(picked))
+		for _, q := range picked {
+			selected[q.DID] = true
+		}
+	}
+	explanation.SelectedCount = len(selected)
+	explanation.Satisfiable = explanation.Error == ""
+
+	result := make([]models.QuorumExplanation, 0, len(quorums))
+	for _, q := range quorums {
+		entry := models.QuorumExplanation{
+			DID:             q.DID,
+			Decision:        models.DecisionExcluded,
+			Rank:            rank[q.DID],
+			Gates:           make([]models.GateResult, 0, len(gates)),
+			Balance:         q.Balance,
+			AssignmentCount: q.AssignmentCount,
+			LastPing:        q.LastPing,
+			Region:          q.Region,
+		}
+		for _, gate := range gates {
+			ok := passed[gate.Name][q.DID]
+			entry.Gates = append(entry.Gates, models.GateResult{Gate: gate.Name, Passed: ok})
+			if !ok {
+				entry.FailedGates = append(entry.FailedGates, gate.Name)
+			}
+		}
+		switch {
+		case selected[q.DID]:
+			entry.Decision = models.DecisionSelected
+		case entry.Rank > 0:
+			entry.Decision = models.DecisionNotSelected
+		}
+		result = append(result, entry)
+	}
+
+	order := map[string]int{models.DecisionSelected: 0, models.DecisionNotSelected: 1, models.DecisionExcluded: 2}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if order[a.Decision] != order[b.Decision] {
+			return order[a.Decision] < order[b.Decision]
+		}
+		if a.Decision != models.DecisionExcluded {
+			return a.Rank < b.Rank
+		}
+		return len(a.FailedGates) < len(b.FailedGates)
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+		explanation.Truncated = true
+	}
+	explanation.Quorums = result
+	return explanation, nil
+}
+
+// CountEligible counts the quorums that currently pass the selection filters for req and
+// returns the balance the check demanded of each. Nothing is assigned and no history is
+// recorded, so planners can probe the pool without skewing load balancing.
+func (ds *DBStore) CountEligible(req models.QuorumListRequest) (int64, float64, error) {
+	defer ds.timeOperation("count_eligible", req)()
+
+	count := req.Count
+	if count <= 0 {
+		count = 7
+	}
+	requiredBalance := ds.RequiredBalance(req.TransactionAmount, count)
+
+	var eligible int64
+	if err := applyStages(ds.db.Model(&QuorumDB{}), ds.selectionStages(req, requiredBalance)).
+		Count(&eligible).Error; err != nil {
+		return 0, 0, err
+	}
+	return eligible, ds.EffectiveRequiredBalance(requiredBalance), nil
+}
+
+// EligibleQuorums returns every quorum that currently passes the selection filters for
+// req, ordered by DID, with the per-quorum balance they were checked against as
+// CountEligible reports it. Nothing is assigned and no history is recorded.
+func (ds *DBStore) EligibleQuorums(req models.QuorumListRequest) ([]models.QuorumData, float64, error) {
+	defer ds.timeOperation("eligible_quorums", req)()
+
+	count := req.Count
+	if count <= 0 {
+		count = 7
+	}
+	requiredBalance := ds.RequiredBalance(req.TransactionAmount, count)
+
+	var quorums []QuorumDB
+	if err := applyStages(ds.db.Model(&QuorumDB{}), ds.selectionStages(req, requiredBalance)).
+		Order("did ASC").
+		Find(&quorums).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]models.QuorumData, 0, len(quorums))
+	for _, q := range quorums {
+		result = append(result, toQuorumData(q))
+	}
+	return result, ds.EffectiveRequiredBalance(requiredBalance), nil
+}
+
+// GetNearEligible returns quorums that pass every selection filter for req except the
+// balance check, holding spare balance (above their reserved floor) within tolerance
+// below the required balance. Results are ordered by shortfall, smallest first.
+func (ds *DBStore) GetNearEligible(req models.QuorumListRequest, tolerance float64) ([]models.NearEligibleQuorum, error) {
+	defer ds.timeOperation("near_eligible", map[string]interface{}{"request": req, "tolerance": tolerance})()
+
+	count := req.Count
+	if count <= 0 {
+		count = 7
+	}
+	requiredBalance := ds.RequiredBalance(req.TransactionAmount, count)
+	// Shortfalls are measured against the balance check as applied, minimum quorum
+	// balance and safety headroom included
+	threshold := ds.balanceThreshold(requiredBalance)
+
+	query := ds.db.Model(&QuorumDB{})
+	for _, stage := range ds.selectionStages(req, requiredBalance) {
+		if stage.Name == "sufficient_balance" {
+			query = query.
+				Where("balance - min_participation_balance < ?", threshold-ds.balanceTolerance()).
+				Where("balance - min_participation_balance >= ?", threshold-tolerance-ds.balanceTolerance())
+			continue
+		}
+		query = stage.Apply(query)
+	}
+
+	var quorums []QuorumDB
+	if err := query.Order("balance - min_participation_balance DESC").Find(&quorums).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]models.NearEligibleQuorum, 0, len(quorums))
+	for _, q := range quorums {
+		result = append(result, models.NearEligibleQuorum{
+			DID:                     q.DID,
+			PeerID:                  q.PeerID,
+			Balance:                 q.Balance,
+			MinParticipationBalance: q.MinParticipationBalance,
+			RequiredBalance:         threshold,
+			Shortfall:               threshold - (q.Balance - q.MinParticipationBalance),
+		})
+	}
+	return result, nil
+}
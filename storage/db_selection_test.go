@@ -0,0 +1,740 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+	"gorm.io/gorm"
+)
+
+func TestSelectQuorumsSpreadsAcrossRegions(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+
+	regionOf := make(map[string]string)
+	inRegion := func(region string) func(*models.QuorumRegistrationRequest) {
+		return func(req *models.QuorumRegistrationRequest) {
+			req.Region = region
+			regionOf[req.DID] = region
+		}
+	}
+	for _, suffix := range []string{"1", "2", "3", "4"} {
+		registerTestQuorum(t, ds, suffix, 100, inRegion("us-east"))
+	}
+	// The other regions' quorums are busier, so plain selection never reaches them
+	for suffix, region := range map[string]string{"5": "eu-west", "6": "ap-south"} {
+		did := registerTestQuorum(t, ds, suffix, 100, inRegion(region))
+		setQuorumColumn(t, ds, did, "assignment_count", 10)
+	}
+
+	result, err := ds.SelectQuorums(selectionRequest(3, 3))
+	if err != nil {
+		t.Fatalf("SelectQuorums: %v", err)
+	}
+	for _, did := range selectedDIDs(result) {
+		if regionOf[did] != "us-east" {
+			t.Fatalf("plain selection took %s from %s, want the least loaded us-east quorums", did, regionOf[did])
+		}
+	}
+
+	req := selectionRequest(3, 3)
+	req.MinRegions = 3
+	result, err = ds.SelectQuorums(req)
+	if err != nil {
+		t.Fatalf("SelectQuorums with min_regions=3: %v", err)
+	}
+	regions := make(map[string]bool)
+	for _, did := range selectedDIDs(result) {
+		regions[regionOf[did]] = true
+	}
+	if len(result.Quorums) != 3 || len(regions) != 3 {
+		t.Errorf("min_regions=3 selected %v, want one quorum from each of three regions", result.Quorums)
+	}
+
+	// Two quorums from three regions can't span them all
+	req = selectionRequest(2, 2)
+	req.MinRegions = 3
+	if _, err := ds.SelectQuorums(req); err == nil {
+		t.Error("min_regions=3 succeeded for a selection of 2")
+	}
+
+	req = selectionRequest(5, 5)
+	req.MinRegions = 4
+	if _, err := ds.SelectQuorums(req); err == nil {
+		t.Error("min_regions=4 succeeded on a pool spanning three regions")
+	}
+}
+
+func TestSelectReplacementAvoidsAssignedAndFailed(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	for _, suffix := range []string{"1", "2", "3", "4", "5"} {
+		registerTestQuorum(t, ds, suffix, 100)
+	}
+
+	req := selectionRequest(3, 3)
+	req.TransactionID = "txn-replace"
+	result, err := ds.SelectQuorums(req)
+	if err != nil {
+		t.Fatalf("SelectQuorums: %v", err)
+	}
+	assigned := selectedDIDs(result)
+	failed := assigned[1]
+
+	// Of the two unassigned quorums, exclude one so only the other qualifies
+	var spare []string
+	for _, suffix := range []string{"1", "2", "3", "4", "5"} {
+		if did := testDID(suffix); !slices.Contains(assigned, did) {
+			spare = append(spare, did)
+		}
+	}
+	replacement, err := ds.SelectReplacement(&models.QuorumReplaceRequest{
+		TransactionID: "txn-replace",
+		FailedDID:     failed,
+		Exclude:       spare[:1],
+	})
+	if err != nil {
+		t.Fatalf("SelectReplacement: %v", err)
+	}
+	if !strings.HasSuffix(replacement.Address, "."+spare[1]) {
+		t.Errorf("replacement = %s, want %s, the only quorum outside the set and exclude list", replacement.Address, spare[1])
+	}
+
+	var history TransactionHistory
+	if err := ds.db.Where("transaction_id = ?", "txn-replace").First(&history).Error; err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	if err := json.Unmarshal([]byte(history.QuorumDIDs), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{assigned[0], spare[1], assigned[2]}
+	if !slices.Equal(got, want) {
+		t.Errorf("recorded set = %v, want the failed DID swapped in place: %v", got, want)
+	}
+
+	// Everything left is now assigned, failed or excluded
+	if _, err := ds.SelectReplacement(&models.QuorumReplaceRequest{
+		TransactionID: "txn-replace",
+		FailedDID:     assigned[0],
+		Exclude:       []string{spare[0], failed},
+	}); err == nil {
+		t.Error("SelectReplacement found a quorum in an exhausted pool")
+	}
+}
+
+func TestSelectReplacementKeepsTransactionToken(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	supportsTRI := func(req *models.QuorumRegistrationRequest) { req.SupportedTokens = []string{"RBT", "TRI"} }
+	for _, suffix := range []string{"1", "2", "3"} {
+		registerTestQuorum(t, ds, suffix, 100, supportsTRI)
+	}
+	rbtOnly := registerTestQuorum(t, ds, "4", 100)
+
+	req := selectionRequest(2, 2)
+	req.FTName = "TRI"
+	req.TransactionID = "txn-tri"
+	result, err := ds.SelectQuorums(req)
+	if err != nil {
+		t.Fatalf("SelectQuorums: %v", err)
+	}
+	assigned := selectedDIDs(result)
+	var history TransactionHistory
+	if err := ds.db.Where("transaction_id = ?", "txn-tri").First(&history).Error; err != nil {
+		t.Fatal(err)
+	}
+	if history.Token != "TRI" {
+		t.Errorf("recorded token = %q, want TRI", history.Token)
+	}
+
+	// Without a token in the request, the replacement still has to support TRI
+	replacement, err := ds.SelectReplacement(&models.QuorumReplaceRequest{TransactionID: "txn-tri", FailedDID: assigned[0]})
+	if err != nil {
+		t.Fatalf("SelectReplacement: %v", err)
+	}
+	if strings.HasSuffix(replacement.Address, "."+rbtOnly) {
+		t.Errorf("replacement %s doesn't support the transaction's TRI token", replacement.Address)
+	}
+
+	// With the first failed quorum excluded, the only one left supports RBT alone
+	if _, err := ds.SelectReplacement(&models.QuorumReplaceRequest{
+		TransactionID: "txn-tri",
+		FailedDID:     assigned[1],
+		Exclude:       []string{assigned[0]},
+	}); err == nil {
+		t.Error("SelectReplacement returned an RBT-only quorum for a TRI transaction")
+	}
+
+	_, err = ds.SelectReplacement(&models.QuorumReplaceRequest{TransactionID: "txn-tri", FailedDID: assigned[1], FTName: "RBT"})
+	if !errors.Is(err, ErrTokenMismatch) {
+		t.Errorf("SelectReplacement for RBT on a TRI transaction: error = %v, want ErrTokenMismatch", err)
+	}
+}
+
+func TestSelectQuorumsByCapability(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+
+	with := func(tokens, capabilities []string) func(*models.QuorumRegistrationRequest) {
+		return func(req *models.QuorumRegistrationRequest) {
+			req.SupportedTokens = tokens
+			req.Capabilities = capabilities
+		}
+	}
+	lite := registerTestQuorum(t, ds, "1", 100, with([]string{"RBT"}, []string{"supports-lite"}))
+	both := registerTestQuorum(t, ds, "2", 100, with([]string{"RBT", "TRI"}, []string{"supports-lite", "supports-child"}))
+	child := registerTestQuorum(t, ds, "3", 100, with([]string{"TRI"}, []string{"supports-child"}))
+	poor := registerTestQuorum(t, ds, "4", 1, with([]string{"RBT", "TRI"}, []string{"supports-lite", "supports-child"}))
+
+	tests := []struct {
+		name     string
+		requires []string
+		ftName   string
+		share    float64 // required balance per quorum
+		want     []string
+	}{
+		{"one capability", []string{"supports-lite"}, "", 1, []string{lite, both, poor}},
+		{"every listed capability", []string{"supports-lite", "supports-child"}, "", 1, []string{both, poor}},
+		{"with token filter", []string{"supports-child"}, "TRI", 1, []string{both, child, poor}},
+		{"with token and balance filters", []string{"supports-child"}, "TRI", 10, []string{both, child}},
+		{"with balance filter", []string{"supports-lite", "supports-child"}, "", 10, []string{both}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count := len(tt.want)
+			req := selectionRequest(count, tt.share*float64(count))
+			req.Requires = tt.requires
+			req.FTName = tt.ftName
+			result, err := ds.SelectQuorums(req)
+			if err != nil {
+				t.Fatalf("SelectQuorums: %v", err)
+			}
+			got := selectedDIDs(result)
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("selected %v, want %v", got, tt.want)
+			}
+
+			// The filters leave exactly those quorums, so one more can't be found
+			req = selectionRequest(count+1, tt.share*float64(count+1))
+			req.Requires = tt.requires
+			req.FTName = tt.ftName
+			if _, err := ds.SelectQuorums(req); err == nil {
+				t.Errorf("selection of %d succeeded, want only %d quorums to qualify", count+1, count)
+			}
+		})
+	}
+}
+
+func TestSelectQuorumsMinSuccessfulTxns(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	proven := []string{
+		registerTestQuorum(t, ds, "1", 100),
+		registerTestQuorum(t, ds, "2", 100),
+		registerTestQuorum(t, ds, "3", 100),
+	}
+	// Each proven quorum has been assigned once and completed that transaction
+	for _, did := range proven {
+		setQuorumColumn(t, ds, did, "assignment_count", 1)
+		if err := ds.db.Create(&QuorumStats{QuorumDID: did, SuccessfulTransactions: 1}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Brand-new quorums are the least loaded, so plain selection goes to them first
+	fresh := []string{registerTestQuorum(t, ds, "4", 100), registerTestQuorum(t, ds, "5", 100)}
+	result, err := ds.SelectQuorums(selectionRequest(2, 2))
+	if err != nil {
+		t.Fatalf("SelectQuorums: %v", err)
+	}
+	got := selectedDIDs(result)
+	slices.Sort(got)
+	if !slices.Equal(got, fresh) {
+		t.Fatalf("plain selection = %v, want the new quorums %v", got, fresh)
+	}
+
+	req := selectionRequest(3, 3)
+	req.MinSuccessfulTxns = 1
+	result, err = ds.SelectQuorums(req)
+	if err != nil {
+		t.Fatalf("SelectQuorums with min_successful_txns=1: %v", err)
+	}
+	got = selectedDIDs(result)
+	slices.Sort(got)
+	if !slices.Equal(got, proven) {
+		t.Errorf("min_successful_txns=1 selected %v, want only the proven quorums %v", got, proven)
+	}
+
+	req = selectionRequest(4, 4)
+	req.MinSuccessfulTxns = 1
+	if _, err := ds.SelectQuorums(req); err == nil {
+		t.Error("min_successful_txns=1 filled a selection of 4 from 3 proven quorums")
+	}
+	req = selectionRequest(1, 1)
+	req.MinSuccessfulTxns = 2
+	if _, err := ds.SelectQuorums(req); err == nil {
+		t.Error("min_successful_txns=2 selected a quorum with one successful transaction")
+	}
+}
+
+func TestSelectQuorumsPrefersFreshHeartbeats(t *testing.T) {
+	// pool registers three quorums that pinged 4m50s, 2m and 10s ago
+	pool := func(t *testing.T, config DBConfig) (ds *DBStore, stale, recent, fresh string) {
+		ds = newTestStore(t, config)
+		now := time.Now()
+		stale = registerTestQuorum(t, ds, "1", 100)
+		setQuorumColumn(t, ds, stale, "last_ping", now.Add(-4*time.Minute-50*time.Second))
+		recent = registerTestQuorum(t, ds, "2", 100)
+		setQuorumColumn(t, ds, recent, "last_ping", now.Add(-2*time.Minute))
+		fresh = registerTestQuorum(t, ds, "3", 100)
+		setQuorumColumn(t, ds, fresh, "last_ping", now.Add(-10*time.Second))
+		return ds, stale, recent, fresh
+	}
+	// unassign undoes a selection's bookkeeping, so the next one sees the same ties
+	unassign := func(t *testing.T, ds *DBStore, dids ...string) {
+		t.Helper()
+		for _, did := range dids {
+			setQuorumColumn(t, ds, did, "assignment_count", 0)
+			setQuorumColumn(t, ds, did, "last_assignment", time.Time{})
+		}
+	}
+
+	t.Run("ties go to the freshest", func(t *testing.T) {
+		ds, _, recent, fresh := pool(t, DBConfig{PreferFreshHeartbeats: true})
+		// Whatever the transaction ID, the tie-break hash doesn't override freshness
+		for i := 0; i < 5; i++ {
+			req := selectionRequest(2, 2)
+			req.TransactionID = fmt.Sprintf("txn-%d", i)
+			result, err := ds.SelectQuorums(req)
+			if err != nil {
+				t.Fatalf("SelectQuorums: %v", err)
+			}
+			if got := selectedDIDs(result); !slices.Equal(got, []string{fresh, recent}) {
+				t.Fatalf("%s selected %v, want the freshest two in order %v", req.TransactionID, got, []string{fresh, recent})
+			}
+			unassign(t, ds, fresh, recent)
+		}
+	})
+
+	t.Run("assignment counts still come first", func(t *testing.T) {
+		ds, stale, recent, fresh := pool(t, DBConfig{PreferFreshHeartbeats: true})
+		setQuorumColumn(t, ds, fresh, "assignment_count", 1)
+		setQuorumColumn(t, ds, recent, "assignment_count", 1)
+		result, err := ds.SelectQuorums(selectionRequest(1, 1))
+		if err != nil {
+			t.Fatalf("SelectQuorums: %v", err)
+		}
+		if got := selectedDIDs(result); !slices.Equal(got, []string{stale}) {
+			t.Errorf("selected %v, want the least assigned quorum %s despite its older heartbeat", got, stale)
+		}
+	})
+
+	t.Run("off by default", func(t *testing.T) {
+		ds, stale, recent, fresh := pool(t, DBConfig{})
+		// Without the preference an assignment-count tie goes to the quorum assigned
+		// longest ago, however recently it pinged
+		now := time.Now()
+		setQuorumColumn(t, ds, fresh, "last_assignment", now.Add(-time.Minute))
+		setQuorumColumn(t, ds, recent, "last_assignment", now.Add(-time.Minute))
+		setQuorumColumn(t, ds, stale, "last_assignment", now.Add(-time.Hour))
+		result, err := ds.SelectQuorums(selectionRequest(1, 1))
+		if err != nil {
+			t.Fatalf("SelectQuorums: %v", err)
+		}
+		if got := selectedDIDs(result); !slices.Equal(got, []string{stale}) {
+			t.Errorf("selected %v, want %s, the longest unassigned", got, stale)
+		}
+	})
+}
+
+func TestSelectQuorumsRespectsReservedFloor(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	withFloor := func(floor float64) func(*models.QuorumRegistrationRequest) {
+		return func(req *models.QuorumRegistrationRequest) { req.MinParticipationBalance = floor }
+	}
+	open := registerTestQuorum(t, ds, "1", 100)
+	reserved := registerTestQuorum(t, ds, "2", 100, withFloor(95))
+	partial := registerTestQuorum(t, ds, "3", 100, withFloor(80))
+
+	quorum, err := ds.GetQuorumByDID(reserved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quorum.MinParticipationBalance != 95 {
+		t.Errorf("info min_participation_balance = %v, want 95", quorum.MinParticipationBalance)
+	}
+
+	// A 10 share fits within 100 nominally, but only 5 of the reserved quorum's is spare
+	result, err := ds.SelectQuorums(selectionRequest(2, 20))
+	if err != nil {
+		t.Fatalf("SelectQuorums: %v", err)
+	}
+	got := selectedDIDs(result)
+	slices.Sort(got)
+	if want := []string{open, partial}; !slices.Equal(got, want) {
+		t.Errorf("selected %v, want %v without the reserved quorum", got, want)
+	}
+	if _, err := ds.SelectQuorums(selectionRequest(3, 30)); err == nil {
+		t.Error("selection of 3 at a 10 share succeeded with one quorum's floor leaving only 5 spare")
+	}
+
+	// A share equal to the spare balance still fits
+	if _, err := ds.SelectQuorums(selectionRequest(3, 15)); err != nil {
+		t.Errorf("selection of 3 at a 5 share: %v", err)
+	}
+}
+
+func TestDiagnoseAvailabilityStageCounts(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	tokens := func(tokens ...string) func(*models.QuorumRegistrationRequest) {
+		return func(req *models.QuorumRegistrationRequest) { req.SupportedTokens = tokens }
+	}
+
+	// Each of the first seven quorums fails one stage, in pipeline order
+	registerTestQuorum(t, ds, "1", 100, tokens("TRI"), func(req *models.QuorumRegistrationRequest) { req.Namespace = "team-b" })
+	disabled := registerTestQuorum(t, ds, "2", 100, tokens("TRI"))
+	setQuorumColumn(t, ds, disabled, "available", false)
+	silent := registerTestQuorum(t, ds, "3", 100, tokens("TRI"))
+	setQuorumColumn(t, ds, silent, "last_ping", time.Now().Add(-time.Hour))
+	registerTestQuorum(t, ds, "4", 5, tokens("TRI"))
+	registerTestQuorum(t, ds, "5", 100, tokens("TRI"), func(req *models.QuorumRegistrationRequest) { req.MaxTransactionShare = 5 })
+	held := registerTestQuorum(t, ds, "6", 100, tokens("TRI"))
+	if _, err := ds.ReserveQuorums("txn-held", []string{held}, 10, time.Hour); err != nil {
+		t.Fatalf("ReserveQuorums: %v", err)
+	}
+	registerTestQuorum(t, ds, "7", 100, tokens("RBT"))
+	registerTestQuorum(t, ds, "8", 100, tokens("TRI"))
+	registerTestQuorum(t, ds, "9", 100, tokens("RBT", "TRI"))
+
+	req := selectionRequest(2, 20)
+	req.FTName = "TRI"
+	diagnosis, err := ds.DiagnoseAvailability(req)
+	if err != nil {
+		t.Fatalf("DiagnoseAvailability: %v", err)
+	}
+
+	want := []models.DiagnosisStage{
+		{Stage: "namespace", Remaining: 8, Excluded: 1},
+		{Stage: "available", Remaining: 7, Excluded: 1},
+		{Stage: "recent_heartbeat", Remaining: 6, Excluded: 1},
+		{Stage: "sufficient_balance", Remaining: 5, Excluded: 1},
+		{Stage: "within_share_cap", Remaining: 4, Excluded: 1},
+		{Stage: "not_reserved", Remaining: 3, Excluded: 1},
+		{Stage: "token_supported", Remaining: 2, Excluded: 1},
+	}
+	if diagnosis.TotalQuorums != 9 || len(diagnosis.Stages) != len(want) {
+		t.Fatalf("diagnosis = %+v, want 9 quorums through %d stages", diagnosis, len(want))
+	}
+	for i, stage := range diagnosis.Stages {
+		if stage.Stage != want[i].Stage || stage.Remaining != want[i].Remaining || stage.Excluded != want[i].Excluded {
+			t.Errorf("stage %d = %s (%d remaining, %d excluded), want %s (%d, %d)",
+				i, stage.Stage, stage.Remaining, stage.Excluded, want[i].Stage, want[i].Remaining, want[i].Excluded)
+		}
+	}
+	if diagnosis.RequiredBalance != 10 || diagnosis.EligibleCount != 2 || !diagnosis.Satisfiable {
+		t.Errorf("diagnosis = %+v, want 2 eligible at a 10 share, satisfiable", diagnosis)
+	}
+
+	req = selectionRequest(3, 30)
+	req.FTName = "TRI"
+	if diagnosis, err = ds.DiagnoseAvailability(req); err != nil || diagnosis.Satisfiable {
+		t.Errorf("diagnosis for 3 = %+v, %v; want unsatisfiable", diagnosis, err)
+	}
+
+	// Diagnosing assigns nothing
+	var assigned int64
+	if err := ds.db.Model(&QuorumDB{}).Where("assignment_count > 0").Count(&assigned).Error; err != nil || assigned != 0 {
+		t.Errorf("%d quorums assigned after diagnosing (%v)", assigned, err)
+	}
+}
+
+func TestGetNearEligibleReturnsOnlyNearMisses(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	registerTestQuorum(t, ds, "1", 100)
+	registerTestQuorum(t, ds, "2", 10)
+	short := registerTestQuorum(t, ds, "3", 9)
+	edge := registerTestQuorum(t, ds, "4", 7)
+	registerTestQuorum(t, ds, "5", 6.5)
+	floored := registerTestQuorum(t, ds, "6", 50, func(req *models.QuorumRegistrationRequest) { req.MinParticipationBalance = 42 })
+	// Near the threshold but failing another stage
+	disabled := registerTestQuorum(t, ds, "7", 9)
+	setQuorumColumn(t, ds, disabled, "available", false)
+
+	// A 10 share with 3 of tolerance
+	quorums, err := ds.GetNearEligible(selectionRequest(2, 20), 3)
+	if err != nil {
+		t.Fatalf("GetNearEligible: %v", err)
+	}
+
+	want := []struct {
+		did       string
+		shortfall float64
+	}{{short, 1}, {floored, 2}, {edge, 3}}
+	if len(quorums) != len(want) {
+		t.Fatalf("near-eligible = %+v, want %d quorums", quorums, len(want))
+	}
+	for i, q := range quorums {
+		if q.DID != want[i].did || q.Shortfall != want[i].shortfall || q.RequiredBalance != 10 {
+			t.Errorf("near-eligible[%d] = %s short %v of %v, want %s short %v of 10",
+				i, q.DID, q.Shortfall, q.RequiredBalance, want[i].did, want[i].shortfall)
+		}
+	}
+}
+
+func TestNewNodeRampLimitsShare(t *testing.T) {
+	// busyPool has six veterans registered two hours ago with 50 assignments each, and
+	// three quorums that just joined
+	busyPool := func(t *testing.T, config DBConfig) (*DBStore, []string) {
+		ds := newTestStore(t, config)
+		for _, suffix := range []string{"1", "2", "3", "4", "5", "6"} {
+			did := registerTestQuorum(t, ds, suffix, 100)
+			setQuorumColumn(t, ds, did, "created_at", time.Now().Add(-2*time.Hour))
+			setQuorumColumn(t, ds, did, "assignment_count", 50)
+		}
+		fresh := []string{registerTestQuorum(t, ds, "7", 100), registerTestQuorum(t, ds, "8", 100), registerTestQuorum(t, ds, "9", 100)}
+		return ds, fresh
+	}
+	newcomers := func(result *SelectionResult, fresh []string) int {
+		n := 0
+		for _, did := range selectedDIDs(result) {
+			if slices.Contains(fresh, did) {
+				n++
+			}
+		}
+		return n
+	}
+
+	ds, fresh := busyPool(t, DBConfig{})
+	result, err := ds.SelectQuorums(selectionRequest(4, 4))
+	if err != nil {
+		t.Fatalf("SelectQuorums: %v", err)
+	}
+	if n := newcomers(result, fresh); n != 3 {
+		t.Fatalf("without the ramp %d of 4 went to new quorums, want all 3", n)
+	}
+
+	// A quarter of a selection of 4 is one quorum
+	ds, fresh = busyPool(t, DBConfig{NewNodeRamp: time.Hour})
+	result, err = ds.SelectQuorums(selectionRequest(4, 4))
+	if err != nil {
+		t.Fatalf("SelectQuorums with the ramp: %v", err)
+	}
+	if n := newcomers(result, fresh); n != 1 {
+		t.Errorf("with the ramp %d of 4 went to new quorums, want 1", n)
+	}
+
+	// A pool of nothing but newcomers still fills the selection
+	ds = newTestStore(t, DBConfig{NewNodeRamp: time.Hour})
+	for _, suffix := range []string{"1", "2", "3"} {
+		registerTestQuorum(t, ds, suffix, 100)
+	}
+	if _, err := ds.SelectQuorums(selectionRequest(3, 3)); err != nil {
+		t.Errorf("selection from an all-new pool: %v", err)
+	}
+}
+
+func TestSelectQuorumsStableForTransactionID(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	for i := 1; i <= 8; i++ {
+		registerTestQuorum(t, ds, fmt.Sprint(i), 100)
+	}
+	// selectAndUndo selects three quorums for transactionID and resets their bookkeeping,
+	// so every call ranks the same unchanged pool
+	selectAndUndo := func(transactionID string) []string {
+		t.Helper()
+		req := selectionRequest(3, 3)
+		req.TransactionID = transactionID
+		result, err := ds.SelectQuorums(req)
+		if err != nil {
+			t.Fatalf("SelectQuorums(%s): %v", transactionID, err)
+		}
+		dids := selectedDIDs(result)
+		for _, did := range dids {
+			setQuorumColumn(t, ds, did, "assignment_count", 0)
+			setQuorumColumn(t, ds, did, "last_assignment", time.Time{})
+		}
+		return dids
+	}
+
+	first := selectAndUndo("txn-retried")
+	for i := 0; i < 5; i++ {
+		if got := selectAndUndo("txn-retried"); !slices.Equal(got, first) {
+			t.Fatalf("retry %d selected %v, want %v", i+1, got, first)
+		}
+	}
+
+	// Different transactions still spread across the tied pool
+	for i := 0; i < 20; i++ {
+		if got := selectAndUndo(fmt.Sprintf("txn-%d", i)); !slices.Equal(got, first) {
+			return
+		}
+	}
+	t.Error("20 other transaction IDs all selected the same set")
+}
+
+func TestSelectQuorumsRespectsShareCap(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	capped := registerTestQuorum(t, ds, "1", 10000, func(req *models.QuorumRegistrationRequest) {
+		req.MaxTransactionShare = 50
+	})
+	registerTestQuorum(t, ds, "2", 200)
+	registerTestQuorum(t, ds, "3", 200)
+
+	// 300 over 2 quorums is 150 each: the capped quorum could fund it but declines
+	result, err := ds.SelectQuorums(selectionRequest(2, 300))
+	if err != nil {
+		t.Fatalf("SelectQuorums: %v", err)
+	}
+	if slices.Contains(selectedDIDs(result), capped) {
+		t.Errorf("selected %v, want %s excluded by its 50 share cap", selectedDIDs(result), capped)
+	}
+
+	// All three are needed, so the cap leaves the pool short
+	if _, err := ds.SelectQuorums(selectionRequest(3, 450)); err == nil {
+		t.Error("selection needing the capped quorum succeeded")
+	}
+
+	// A share within the cap is fine
+	result, err = ds.SelectQuorums(selectionRequest(3, 150))
+	if err != nil {
+		t.Fatalf("SelectQuorums within the cap: %v", err)
+	}
+	if !slices.Contains(selectedDIDs(result), capped) {
+		t.Errorf("selected %v, want %s for a share of 50", selectedDIDs(result), capped)
+	}
+}
+
+func TestSelectQuorumsFailsWhenAssignmentNotRecorded(t *testing.T) {
+	// Each case fails one write of the selection's bookkeeping
+	tests := []struct {
+		name  string
+		table string
+	}{
+		{"count update", "quorums"},
+		{"history insert", "transaction_history"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := newTestStore(t, DBConfig{})
+			first := registerTestQuorum(t, ds, "1", 100)
+			second := registerTestQuorum(t, ds, "2", 100)
+
+			inject := func(db *gorm.DB) {
+				if db.Statement.Table == tt.table {
+					db.AddError(errors.New("injected failure"))
+				}
+			}
+			var err error
+			if tt.table == "quorums" {
+				err = ds.db.Callback().Update().Before("gorm:update").Register("test:fail", inject)
+			} else {
+				err = ds.db.Callback().Create().Before("gorm:create").Register("test:fail", inject)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := selectionRequest(2, 2)
+			req.TransactionID = "txn-unrecorded"
+			result, err := ds.SelectQuorums(req)
+			if !errors.Is(err, ErrAssignmentNotRecorded) || result != nil {
+				t.Fatalf("SelectQuorums = %v, %v; want ErrAssignmentNotRecorded and no result", result, err)
+			}
+
+			// The whole selection rolled back: no counts, history or assignments
+			for _, did := range []string{first, second} {
+				quorum, err := ds.GetQuorumByDID(did)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if quorum.AssignmentCount != 0 {
+					t.Errorf("%s assignment_count = %d after a failed selection, want 0", did, quorum.AssignmentCount)
+				}
+			}
+			var history, assignments int64
+			ds.db.Model(&TransactionHistory{}).Where("transaction_id = ?", req.TransactionID).Count(&history)
+			ds.db.Model(&QuorumAssignment{}).Where("transaction_id = ?", req.TransactionID).Count(&assignments)
+			if history != 0 || assignments != 0 {
+				t.Errorf("%d history and %d assignment rows after a failed selection, want none", history, assignments)
+			}
+		})
+	}
+}
+
+func TestConcurrentSelectionsDoNotOverAssign(t *testing.T) {
+	checkConcurrentSelections(t, newTestStore(t, DBConfig{}))
+}
+
+func TestConcurrentSelectionsDoNotOverAssignSharedDatabase(t *testing.T) {
+	config := sharedTestDatabase(t)
+
+	// Two instances sharing one database, so selections contend on row locks rather
+	// than in process
+	instances := make([]*DBStore, 2)
+	for i := range instances {
+		ds, err := NewDBStore(config)
+		if err != nil {
+			t.Fatalf("NewDBStore: %v", err)
+		}
+		t.Cleanup(func() { ds.Close() })
+		instances[i] = ds
+	}
+	wipe := instances[0].db.Session(&gorm.Session{AllowGlobalUpdate: true})
+	for _, model := range []interface{}{&QuorumAssignment{}, &TransactionHistory{}, &QuorumStats{}, &QuorumToken{}, &QuorumDB{}} {
+		if err := wipe.Unscoped().Delete(model).Error; err != nil {
+			t.Fatalf("clearing shared database: %v", err)
+		}
+	}
+	checkConcurrentSelections(t, instances...)
+}
+
+// checkConcurrentSelections registers 10 quorums and runs 20 concurrent selections of 2
+// spread over instances, which share one database. Ranked one at a time on current
+// counts, each takes the two least-assigned, leaving every quorum with exactly 4.
+func checkConcurrentSelections(t *testing.T, instances ...*DBStore) {
+	t.Helper()
+	ds := instances[0]
+	var dids []string
+	for i := 1; i <= 10; i++ {
+		dids = append(dids, registerTestQuorum(t, ds, fmt.Sprint(i), 100))
+	}
+
+	const selections = 20
+	start := make(chan struct{})
+	errs := make(chan error, selections)
+	for i := 0; i < selections; i++ {
+		go func(i int) {
+			<-start
+			req := selectionRequest(2, 2)
+			req.TransactionID = fmt.Sprintf("txn-%d", i)
+			_, err := instances[i%len(instances)].SelectQuorums(req)
+			errs <- err
+		}(i)
+	}
+	close(start)
+	for i := 0; i < selections; i++ {
+		if err := <-errs; err != nil {
+			t.Fatalf("concurrent SelectQuorums: %v", err)
+		}
+	}
+
+	for _, did := range dids {
+		quorum, err := ds.GetQuorumByDID(did)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if quorum.AssignmentCount != 4 {
+			t.Errorf("%s has %d assignments, want 4", did, quorum.AssignmentCount)
+		}
+	}
+	var assignments int64
+	if err := ds.db.Model(&QuorumAssignment{}).Count(&assignments).Error; err != nil {
+		t.Fatal(err)
+	}
+	if assignments != 2*selections {
+		t.Errorf("%d assignment rows, want %d", assignments, 2*selections)
+	}
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// ServiceSetting is a runtime setting changed through the admin API. It lives in the
+// database rather than in one instance's memory, so every replica sharing the database
+// applies it and it survives restarts.
+type ServiceSetting struct {
+	Name      string `gorm:"primaryKey;size:64"`
+	Value     string `gorm:"size:255;not null"`
+	UpdatedAt time.Time
+}
+
+// TableName specifies the table name for ServiceSetting
+func (ServiceSetting) TableName() string {
+	return "service_settings"
+}
+
+// Setting names
+const (
+	settingStalenessFrozenUntil    = "staleness_frozen_until"    // RFC 3339 deadline
+	settingBalanceSafetyMultiplier = "balance_safety_multiplier" // decimal factor
+)
+
+// putSetting stores value under name, replacing any earlier value
+func (ds *DBStore) putSetting(name, value string) error {
+	return ds.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&ServiceSetting{Name: name, Value: value}).Error
+}
+
+// getSetting returns the value stored under name, and whether there is one
+func (ds *DBStore) getSetting(name string) (string, bool, error) {
+	var setting ServiceSetting
+	result := ds.db.Where("name = ?", name).Limit(1).Find(&setting)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return "", false, result.Error
+	}
+	return setting.Value, true, nil
+}
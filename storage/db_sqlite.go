@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// checkSQLitePath verifies that the directory holding a SQLite database file exists
+// and is writable (creating it when createDir is set), and that an existing database
+// file can be opened for writing. gorm.Open would otherwise fail with an opaque driver
+// error on a misconfigured volume.
+func checkSQLitePath(path string, createDir bool) error {
+	// In-memory and URI-style databases have no directory to check
+	if path == ":memory:" || strings.HasPrefix(path, "file:") {
+		return nil
+	}
+
+	dir := filepath.Dir(path)
+	info, err := os.Stat(dir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && createDir:
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("SQLite directory %s does not exist and could not be created: %v", dir, err)
+		}
+	case errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("SQLite directory %s does not exist; create it, mount a volume there, or enable -sqlite-create-dir", dir)
+	case err != nil:
+		return fmt.Errorf("cannot access SQLite directory %s: %v", dir, err)
+	case !info.IsDir():
+		return fmt.Errorf("SQLite path %s is not inside a directory: %s is a file", path, dir)
+	}
+
+	// SQLite writes journal files next to the database, so the directory itself must
+	// accept new files
+	probe, err := os.CreateTemp(dir, ".advisory-write-check-*")
+	if err != nil {
+		return fmt.Errorf("SQLite directory %s is not writable (check volume permissions and read-only mounts): %v", dir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	if f, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+		f.Close()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("SQLite database %s is not writable: %v", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewDBStoreUnwritableSQLitePath(t *testing.T) {
+	open := func(path string, createDir bool) error {
+		ds, err := NewDBStore(DBConfig{Type: "sqlite", Database: path, CreateSQLiteDir: createDir, AutoMigrate: true})
+		if err == nil {
+			ds.Close()
+		}
+		return err
+	}
+
+	t.Run("missing directory", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "missing", "advisory.db")
+		if err := open(path, false); err == nil || !strings.Contains(err.Error(), "does not exist") {
+			t.Errorf("error = %v, want the missing directory reported", err)
+		}
+		if err := open(path, true); err != nil {
+			t.Fatalf("with CreateSQLiteDir: %v", err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("database not created in the new directory: %v", err)
+		}
+	})
+
+	t.Run("directory is a file", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "data")
+		if err := os.WriteFile(file, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := open(filepath.Join(file, "advisory.db"), true); err == nil || !strings.Contains(err.Error(), "is a file") {
+			t.Errorf("error = %v, want the file in the way reported", err)
+		}
+	})
+
+	t.Run("read-only directory", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("root can write to read-only directories")
+		}
+		dir := t.TempDir()
+		if err := os.Chmod(dir, 0o555); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { os.Chmod(dir, 0o755) })
+		if err := open(filepath.Join(dir, "advisory.db"), true); err == nil || !strings.Contains(err.Error(), "not writable") {
+			t.Errorf("error = %v, want the directory reported as not writable", err)
+		}
+	})
+
+	t.Run("read-only database file", func(t *testing.T) {
+		if os.Geteuid() == 0 {
+			t.Skip("root can write to read-only files")
+		}
+		path := filepath.Join(t.TempDir(), "advisory.db")
+		if err := os.WriteFile(path, nil, 0o444); err != nil {
+			t.Fatal(err)
+		}
+		if err := open(path, true); err == nil || !strings.Contains(err.Error(), "not writable") {
+			t.Errorf("error = %v, want the database reported as not writable", err)
+		}
+	})
+}
@@ -1,12 +1,21 @@
 package storage
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"os"
+	"sync"
 	"time"
 
+	"github.com/gklps/advisory-node/audit"
+	"github.com/gklps/advisory-node/events"
 	"github.com/gklps/advisory-node/models"
+	"github.com/gklps/advisory-node/webhooks"
+	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -15,27 +24,213 @@ import (
 
 // DBStore implements database storage for quorums
 type DBStore struct {
-	db *gorm.DB
+	db      *gorm.DB
+	replica *gorm.DB // optional read replica for listings (see reader)
+	config  DBConfig
+
+	// Recent selection failures for alerting (see RecentSelectionFailures)
+	failures failureRing
+
+	// Serializes select-and-assign and registration transactions on SQLite (see
+	// assignmentTransaction and upsertTransaction)
+	assignMu sync.Mutex
+
+	// Receives quorum_stale events from cleanup (see SetWebhookPublisher)
+	publisher *webhooks.Publisher
+
+	// Receives every committed availability event (see SetEventBroadcaster)
+	broadcaster *events.Broadcaster
+
+	// Takes the cluster-wide cleanup lock; nil where the lock is always granted (see
+	// WithCleanupLock)
+	cleanupLock cleanupLocker
 }
 
+// DefaultAvailabilityWindow is how recently a quorum must have pinged to be selectable
+const DefaultAvailabilityWindow = 5 * time.Minute
+
+// DefaultStaleThreshold is how long without a ping before cleanup marks a quorum unavailable
+const DefaultStaleThreshold = 10 * time.Minute
+
+// ErrQuorumNotFound is returned when a well-formed DID has no registered quorum
+var ErrQuorumNotFound = errors.New("quorum not found")
+
+// ErrQuorumDisabled is returned when confirming the availability of a quorum an operator
+// has taken out of rotation
+var ErrQuorumDisabled = errors.New("quorum was disabled by an operator")
+
 // DBConfig holds database configuration
 type DBConfig struct {
-	Type     string // "sqlite" or "postgres"
+	Type     string // "sqlite", "postgres" or "mysql"
 	Host     string
 	Port     int
 	Database string
 	Username string
 	Password string
 	SSLMode  string
+
+	AvailabilityWindow    time.Duration // Heartbeat timeout for selection (default 5m)
+	StaleThreshold        time.Duration // Heartbeat age at which cleanup marks a quorum unavailable (default 10m)
+	PreferFreshHeartbeats bool          // Break assignment-count ties in favor of the most recent last_ping
+
+	// Collateral tier -> selection weight; higher-weighted tiers receive proportionally more
+	// assignments (default DefaultTierWeights)
+	TierWeights map[string]float64
+
+	// Quorums registered within NewNodeRamp take at most NewNodeMaxShare of each selection,
+	// so a freshly joined node with assignment_count 0 can't dominate consensus (0 disables)
+	NewNodeRamp     time.Duration
+	NewNodeMaxShare float64 // default DefaultNewNodeMaxShare
+
+	// Store operations and SQL statements slower than this are logged
+	// (default DefaultSlowQueryThreshold)
+	SlowQueryThreshold time.Duration
+
+	// Minimum number of available quorums supporting a token before selection for it is
+	// allowed; TokenMinPoolSizes overrides MinPoolSize per token (0 disables)
+	MinPoolSize       int
+	TokenMinPoolSizes map[string]int
+
+	// Most- to least-assigned ratio beyond which RebalanceAssignments decays the
+	// busiest quorums' counts (0 disables)
+	MaxAssignmentImbalance float64
+
+	// Optional PostgreSQL read replica serving read-only listings, and the replication
+	// lag beyond which it is reported as not ready (default DefaultMaxReplicaLag)
+	ReplicaURL    string
+	MaxReplicaLag time.Duration
+
+	// Create the SQLite database's directory when it does not exist
+	CreateSQLiteDir bool
+
+	// Apply pending migrations when the store is created, instead of refusing a
+	// database whose schema is behind (see MigrateDatabase)
+	AutoMigrate bool
+
+	// Factor applied to each quorum's required balance in the balance check, demanding
+	// extra collateral headroom during high-risk periods (default 1)
+	BalanceSafetyMultiplier float64
+
+	// Balance every selected quorum must hold above its reserved floor, however small
+	// the transaction's per-quorum share (0 disables)
+	MinQuorumBalance float64
+
+	// Decimal places required balances are rounded up to (default DefaultBalancePrecision)
+	BalancePrecision int
+
+	// Which tokens a quorum declaring none supports: EmptyTokensRBTOnly (default),
+	// EmptyTokensAll or EmptyTokensNone
+	EmptyTokensMeans string
+
+	// Receives the decision context of every successful selection (nil disables)
+	Auditor audit.SelectionAuditor
+
+	// Connection pool limits of the primary database; 0 keeps database/sql's defaults
+	// (unlimited open connections, 2 idle, no lifetime)
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
 }
 
-// NewDBStore creates a new database store
+// NewDBStore creates a new database store. The database schema must be at
+// SchemaVersion, unless config.AutoMigrate is set to apply pending migrations first.
 func NewDBStore(config DBConfig) (*DBStore, error) {
+	if config.SlowQueryThreshold <= 0 {
+		config.SlowQueryThreshold = DefaultSlowQueryThreshold
+	}
+
+	db, err := openDatabase(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.AutoMigrate {
+		err = migrate(db)
+	} else {
+		err = checkSchemaVersion(db)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if config.AvailabilityWindow <= 0 {
+		config.AvailabilityWindow = DefaultAvailabilityWindow
+	}
+	if config.StaleThreshold <= 0 {
+		config.StaleThreshold = DefaultStaleThreshold
+	}
+	if config.StaleThreshold < config.AvailabilityWindow {
+		return nil, fmt.Errorf("stale threshold (%s) must not be shorter than the availability window (%s)",
+			config.StaleThreshold, config.AvailabilityWindow)
+	}
+	if len(config.TierWeights) == 0 {
+		config.TierWeights = DefaultTierWeights()
+	}
+	if err := validateTierWeights(config.TierWeights); err != nil {
+		return nil, err
+	}
+	if config.NewNodeMaxShare == 0 {
+		config.NewNodeMaxShare = DefaultNewNodeMaxShare
+	}
+	if config.NewNodeMaxShare < 0 || config.NewNodeMaxShare > 1 {
+		return nil, fmt.Errorf("new node max share must be between 0 and 1, got %v", config.NewNodeMaxShare)
+	}
+	if config.MaxAssignmentImbalance != 0 && config.MaxAssignmentImbalance <= 1 {
+		return nil, fmt.Errorf("max assignment imbalance must be greater than 1, got %v", config.MaxAssignmentImbalance)
+	}
+	if config.MaxReplicaLag <= 0 {
+		config.MaxReplicaLag = DefaultMaxReplicaLag
+	}
+	if config.BalanceSafetyMultiplier == 0 {
+		config.BalanceSafetyMultiplier = 1
+	}
+	if err := ValidateBalanceSafetyMultiplier(config.BalanceSafetyMultiplier); err != nil {
+		return nil, err
+	}
+	if config.MinQuorumBalance < 0 {
+		return nil, fmt.Errorf("min quorum balance must not be negative, got %v", config.MinQuorumBalance)
+	}
+	if config.BalancePrecision == 0 {
+		config.BalancePrecision = DefaultBalancePrecision
+	}
+	if err := ValidateBalancePrecision(config.BalancePrecision); err != nil {
+		return nil, err
+	}
+	if config.EmptyTokensMeans == "" {
+		config.EmptyTokensMeans = EmptyTokensRBTOnly
+	}
+	if err := ValidateEmptyTokensMeans(config.EmptyTokensMeans); err != nil {
+		return nil, err
+	}
+
+	var replica *gorm.DB
+	if config.ReplicaURL != "" {
+		if replica, err = openReplica(config.ReplicaURL); err != nil {
+			return nil, err
+		}
+	}
+
+	ds := &DBStore{db: db, replica: replica, config: config}
+	if usesSessionCleanupLock(config.Type) {
+		ds.cleanupLock = ds.sessionCleanupLock
+	}
+	return ds, nil
+}
+
+// openDatabase connects to the database in config and applies its connection pool limits
+func openDatabase(config DBConfig) (*gorm.DB, error) {
 	var db *gorm.DB
 	var err error
 
 	gormConfig := &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		// Individual SQL statements over the threshold are flagged [SLOW SQL]
+		Logger: logger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), logger.Config{
+			SlowThreshold: config.SlowQueryThreshold,
+			LogLevel:      logger.Info,
+			Colorful:      true,
+		}),
+		// Map driver errors such as unique violations to gorm.ErrDuplicatedKey
+		TranslateError: true,
 	}
 
 	switch config.Type {
@@ -45,6 +240,9 @@ func NewDBStore(config DBConfig) (*DBStore, error) {
 		if dbPath == "" {
 			dbPath = "advisory_node.db"
 		}
+		if err := checkSQLitePath(dbPath, config.CreateSQLiteDir); err != nil {
+			return nil, err
+		}
 		db, err = gorm.Open(sqlite.Open(dbPath), gormConfig)
 
 	case "postgres":
@@ -53,6 +251,9 @@ func NewDBStore(config DBConfig) (*DBStore, error) {
 			config.Host, config.Port, config.Username, config.Password, config.Database, config.SSLMode)
 		db, err = gorm.Open(postgres.Open(dsn), gormConfig)
 
+	case "mysql":
+		db, err = gorm.Open(mysql.Open(mysqlDSN(config)), gormConfig)
+
 	default:
 		return nil, fmt.Errorf("unsupported database type: %s", config.Type)
 	}
@@ -60,40 +261,171 @@ func NewDBStore(config DBConfig) (*DBStore, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %v", err)
 	}
+	if err := configurePool(db, config); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// configurePool applies the connection pool limits set in config
+func configurePool(db *gorm.DB, config DBConfig) error {
+	if config.MaxOpenConns < 0 || config.MaxIdleConns < 0 || config.ConnMaxLifetime < 0 {
+		return fmt.Errorf("connection pool limits must not be negative")
+	}
+	// The cleanup lock holds a connection while cleanup queries through the pool, which
+	// would wait forever for a second connection (see WithCleanupLock)
+	if config.MaxOpenConns == 1 && usesSessionCleanupLock(config.Type) {
+		return fmt.Errorf("%s needs at least 2 open connections: the cleanup lock holds one while cleanup uses another", config.Type)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to configure connection pool: %v", err)
+	}
+	if config.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	}
+	if config.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	}
+	if config.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
+	}
+	return nil
+}
+
+// PoolStats returns the primary database's connection pool statistics
+func (ds *DBStore) PoolStats() sql.DBStats {
+	sqlDB, err := ds.db.DB()
+	if err != nil {
+		return sql.DBStats{}
+	}
+	return sqlDB.Stats()
+}
 
-	// Auto migrate schemas
-	err = db.AutoMigrate(
-		&QuorumDB{},
-		&TransactionHistory{},
-		&QuorumStats{},
-		&BalanceHistory{},
-	)
+// Ping checks that the database connection is alive
+func (ds *DBStore) Ping(ctx context.Context) error {
+	sqlDB, err := ds.db.DB()
 	if err != nil {
-		return nil, fmt.Errorf("failed to migrate database: %v", err)
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Close closes the database connections, including the read replica's
+func (ds *DBStore) Close() error {
+	if ds.replica != nil {
+		if sqlDB, err := ds.replica.DB(); err == nil {
+			sqlDB.Close()
+		}
 	}
 
-	return &DBStore{db: db}, nil
+	sqlDB, err := ds.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
 
 // RegisterQuorum registers a new quorum or updates an existing one
 func (ds *DBStore) RegisterQuorum(req *models.QuorumRegistrationRequest) error {
+	return ds.upsertTransaction(func(tx *gorm.DB) error {
+		return registerQuorum(tx, req)
+	})
+}
+
+// RegistrationResult is the outcome of one registration in a batch; Err is nil on success
+type RegistrationResult struct {
+	DID string
+	Err error
+}
+
+// RegisterQuorumsBatch registers every request inside a single transaction. Each
+// registration runs in its own savepoint, so one that fails is rolled back and reported
+// in its result without undoing the others. Results are in request order; the error is
+// only set when the batch as a whole could not be committed, in which case nothing is
+// registered.
+func (ds *DBStore) RegisterQuorumsBatch(reqs []*models.QuorumRegistrationRequest) ([]RegistrationResult, error) {
+	results := make([]RegistrationResult, len(reqs))
+
+	err := ds.poolTransaction(func(tx *gorm.DB) error {
+		for i, req := range reqs {
+			register := func(sp *gorm.DB) error {
+				return registerQuorum(sp, req)
+			}
+
+			// Retry a lost insert race as an update, as upsertTransaction does
+			err := savepoint(tx, register)
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				err = savepoint(tx, register)
+			}
+			results[i] = RegistrationResult{DID: req.DID, Err: err}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// upsertTransaction runs fn in a transaction, retrying once if it hits a unique
+// violation. Two concurrent first registrations of a DID can both find no row and
+// insert; the loser's retry then sees the winner's row and updates it instead. On
+// SQLite the race surfaces as SQLITE_BUSY rather than a unique violation, so there
+// these transactions are serialized in process, as in assignmentTransaction.
+func (ds *DBStore) upsertTransaction(fn func(tx *gorm.DB) error) error {
+	if ds.config.Type == "sqlite" {
+		ds.assignMu.Lock()
+		defer ds.assignMu.Unlock()
+	}
+	err := ds.poolTransaction(fn)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		err = ds.poolTransaction(fn)
+	}
+	return err
+}
+
+// registerQuorum upserts a quorum and its capabilities within an open transaction
+func registerQuorum(tx *gorm.DB, req *models.QuorumRegistrationRequest) error {
+	if err := checkTombstone(tx, req); err != nil {
+		return err
+	}
+
 	var existingQuorum QuorumDB
 
-	// Check if quorum exists
-	result := ds.db.Where("did = ?", req.DID).First(&existingQuorum)
+	// Serialize supported tokens and capabilities to JSON
+	supportedTokensJSON, _ := json.Marshal(req.SupportedTokens)
+	capabilitiesJSON, _ := json.Marshal(req.Capabilities)
 
-	if result.Error == nil {
-		// Serialize supported tokens to JSON
-		supportedTokensJSON, _ := json.Marshal(req.SupportedTokens)
+	// Check if quorum exists, including unregistered rows the DID's unique index still holds
+	result := tx.Unscoped().Where("did = ?", req.DID).First(&existingQuorum)
 
+	if result.Error == nil && existingQuorum.DeletedAt.Valid {
+		return restoreQuorum(tx, existingQuorum, req)
+	}
+	if result.Error == nil {
 		// Update existing quorum
 		updates := map[string]interface{}{
 			"peer_id":          req.PeerID,
 			"balance":          req.Balance,
 			"did_type":         req.DIDType,
-			"available":        true,
+			"available":        !existingQuorum.ManuallyDisabled,
 			"last_ping":        time.Now(),
 			"supported_tokens": string(supportedTokensJSON),
+			"capabilities":     string(capabilitiesJSON),
+			"region":           req.Region,
+			"tier":             req.Tier,
+			"namespace":        selectionNamespace(req.Namespace),
+			"metadata":         marshalMetadata(req.Metadata),
+
+			"min_participation_balance": req.MinParticipationBalance,
+			"max_transaction_share":     req.MaxTransactionShare,
+		}
+		// An omitted key keeps the registered one rather than disabling signing
+		if req.PublicKey != "" {
+			updates["public_key"] = req.PublicKey
 		}
 
 		// Track balance change if different
@@ -105,17 +437,63 @@ func (ds *DBStore) RegisterQuorum(req *models.QuorumRegistrationRequest) error {
 				ChangeReason: "Registration update",
 				Timestamp:    time.Now(),
 			}
-			ds.db.Create(&balanceHistory)
+			if err := tx.Create(&balanceHistory).Error; err != nil {
+				return err
+			}
 		}
 
-		return ds.db.Model(&existingQuorum).Updates(updates).Error
+		if err := tx.Model(&existingQuorum).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := recordAvailabilityEvent(tx, req.DID, EventReregistered); err != nil {
+			return err
+		}
+		if err := replaceCapabilities(tx, req.DID, req.Capabilities); err != nil {
+			return err
+		}
+		return replaceTokens(tx, req.DID, req.SupportedTokens)
+	}
+
+	quorum := newQuorum(req)
+	if err := tx.Create(&quorum).Error; err != nil {
+		return err
+	}
+	if err := recordAvailabilityEvent(tx, req.DID, EventRegistered); err != nil {
+		return err
 	}
+	if err := replaceCapabilities(tx, req.DID, req.Capabilities); err != nil {
+		return err
+	}
+	return replaceTokens(tx, req.DID, req.SupportedTokens)
+}
 
-	// Serialize supported tokens to JSON
+// restoreQuorum registers a soft-deleted quorum afresh on its old row: every field is
+// reset as for a new registration, so assignment counts and the new-node ramp start over.
+// Only an operator's disable survives, so unregistering can't bring a quorum back.
+func restoreQuorum(tx *gorm.DB, deleted QuorumDB, req *models.QuorumRegistrationRequest) error {
+	quorum := newQuorum(req)
+	quorum.ID = deleted.ID
+	quorum.CreatedAt = time.Now()
+	quorum.ManuallyDisabled = deleted.ManuallyDisabled
+	quorum.Available = !deleted.ManuallyDisabled
+	if err := tx.Unscoped().Select("*").Save(&quorum).Error; err != nil {
+		return err
+	}
+	if err := recordAvailabilityEvent(tx, req.DID, EventRegistered); err != nil {
+		return err
+	}
+	if err := replaceCapabilities(tx, req.DID, req.Capabilities); err != nil {
+		return err
+	}
+	return replaceTokens(tx, req.DID, req.SupportedTokens)
+}
+
+// newQuorum builds the row for a first registration of req
+func newQuorum(req *models.QuorumRegistrationRequest) QuorumDB {
 	supportedTokensJSON, _ := json.Marshal(req.SupportedTokens)
+	capabilitiesJSON, _ := json.Marshal(req.Capabilities)
 
-	// Create new quorum
-	quorum := QuorumDB{
+	return QuorumDB{
 		DID:              req.DID,
 		PeerID:           req.PeerID,
 		Balance:          req.Balance,
@@ -124,271 +502,722 @@ func (ds *DBStore) RegisterQuorum(req *models.QuorumRegistrationRequest) error {
 		LastPing:         time.Now(),
 		RegistrationTime: time.Now(),
 		SupportedTokens:  string(supportedTokensJSON),
+		Capabilities:     string(capabilitiesJSON),
+		Region:           req.Region,
+		Tier:             req.Tier,
+		Namespace:        selectionNamespace(req.Namespace),
+		Metadata:         marshalMetadata(req.Metadata),
+
+		MinParticipationBalance: req.MinParticipationBalance,
+		MaxTransactionShare:     req.MaxTransactionShare,
+		PublicKey:               req.PublicKey,
 	}
-
-	return ds.db.Create(&quorum).Error
 }
 
-// GetAvailableQuorums returns available quorums with balance validation and token filtering
-func (ds *DBStore) GetAvailableQuorums(count int, lastCharTID string, transactionAmount float64, ftName string) ([]models.QuorumData, error) {
-	if count <= 0 {
-		count = 7
+// GetPublicKey returns the public key registered for did, empty when it has none
+func (ds *DBStore) GetPublicKey(did string) (string, error) {
+	var quorum QuorumDB
+	if err := ds.db.Select("public_key").Where("did = ?", did).First(&quorum).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrQuorumNotFound
+		}
+		return "", err
 	}
+	return quorum.PublicKey, nil
+}
 
-	// Calculate required balance (transaction amount divided by number of quorums)
-	requiredBalance := transactionAmount / float64(count)
+// KeyedDIDs returns which of dids belong to quorums registered with a public key
+func (ds *DBStore) KeyedDIDs(dids []string) ([]string, error) {
+	var keyed []string
+	err := ds.db.Model(&QuorumDB{}).
+		Where("did IN ? AND public_key IS NOT NULL AND public_key <> ''", dids).
+		Pluck("did", &keyed).Error
+	return keyed, err
+}
 
-	// Build query
-	query := ds.db.Model(&QuorumDB{}).
-		Where("available = ?", true).
-		Where("last_ping > ?", time.Now().Add(-5*time.Minute)).
-		Where("balance >= ?", requiredBalance) // Only quorums with sufficient balance
+// scopeNamespace restricts a quorum query to namespace; an empty namespace means all
+func scopeNamespace(query *gorm.DB, namespace string) *gorm.DB {
+	if namespace == "" {
+		return query
+	}
+	return query.Where("namespace = ?", namespace)
+}
 
-	// Filter by token type if provided
-	if ftName != "" {
-		// For TRI tokens, use a deterministic set of validators
-		if ftName == "TRI" {
-			// Filter quorums that support TRI tokens
-			query = query.Where("supported_tokens LIKE ?", "%\"TRI\"%")
-			// For TRI, ensure consistent validator selection by ordering by DID
-			query = query.Order("did ASC")
-		} else {
-			// For other tokens, filter by supported tokens or default to RBT-compatible
-			query = query.Where("supported_tokens LIKE ? OR supported_tokens = '' OR supported_tokens IS NULL", "%\""+ftName+"\"%")
+// findQuorum loads a quorum by DID, returning ErrQuorumNotFound when none is registered
+func findQuorum(db *gorm.DB, did string) (QuorumDB, error) {
+	var quorum QuorumDB
+	if err := db.Where("did = ?", did).First(&quorum).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return quorum, ErrQuorumNotFound
 		}
-	} else {
-		// Default behavior - no token filtering
-		query = query.Where("supported_tokens LIKE '%\"RBT\"%' OR supported_tokens = '' OR supported_tokens IS NULL")
+		return quorum, err
 	}
+	return quorum, nil
+}
 
-	// Filter by last character if provided (only for non-TRI tokens to maintain TRI consistency)
-	if lastCharTID != "" && ftName != "TRI" {
-		query = query.Where("did LIKE ?", "%"+lastCharTID)
+// recordAvailabilityEvent appends an entry to the availability event log
+func recordAvailabilityEvent(tx *gorm.DB, did, event string) error {
+	now := time.Now()
+	if err := tx.Create(&AvailabilityEvent{
+		QuorumDID: did,
+		Event:     event,
+		Timestamp: now,
+	}).Error; err != nil {
+		return err
 	}
+	recordPoolChange(tx, did, event, now)
+	return nil
+}
 
-	// Get quorums with appropriate ordering
-	var quorums []QuorumDB
-	var err error
+// replaceCapabilities rewrites the capability rows used for selection filtering
+func replaceCapabilities(tx *gorm.DB, did string, capabilities []string) error {
+	if err := tx.Where("quorum_did = ?", did).Delete(&QuorumCapability{}).Error; err != nil {
+		return err
+	}
+	if len(capabilities) == 0 {
+		return nil
+	}
 
-	if ftName == "TRI" {
-		// For TRI tokens, maintain consistent ordering (already set DID ASC above)
-		err = query.Limit(count).Find(&quorums).Error
-	} else {
-		// For other tokens, use load balancing
-		err = query.Order("assignment_count ASC, last_assignment ASC").
-			Limit(count).
-			Find(&quorums).Error
+	rows := make([]QuorumCapability, 0, len(capabilities))
+	for _, capability := range capabilities {
+		rows = append(rows, QuorumCapability{QuorumDID: did, Capability: capability})
 	}
+	return tx.Create(&rows).Error
+}
 
+// GetAvailableQuorums returns available quorums with balance validation and token
+// filtering, restricted to didType when it is non-nil
+func (ds *DBStore) GetAvailableQuorums(count int, lastCharTID string, transactionAmount float64, ftName string, didType *int) ([]models.QuorumData, error) {
+	result, err := ds.SelectQuorums(models.QuorumListRequest{
+		Count:             count,
+		LastCharTID:       lastCharTID,
+		TransactionAmount: transactionAmount,
+		FTName:            ftName,
+		DIDType:           didType,
+	})
 	if err != nil {
 		return nil, err
 	}
+	return result.Quorums, nil
+}
 
-	if len(quorums) < count {
-		return nil, fmt.Errorf("not enough quorums with required balance. Found %d, need %d (required balance: %.4f)",
-			len(quorums), count, requiredBalance)
-	}
+// UpdateQuorumBalance updates the balance for a quorum, recording the change in its
+// balance history. It returns ErrQuorumNotFound for an unknown DID; any other error is a
+// database failure.
+func (ds *DBStore) UpdateQuorumBalance(did string, newBalance float64) error {
+	return ds.db.Transaction(func(tx *gorm.DB) error {
+		quorum, err := findQuorum(tx, did)
+		if err != nil {
+			return err
+		}
 
-	// Update assignment metadata and create response
-	result := make([]models.QuorumData, 0, count)
-	quorumDIDs := make([]string, 0, count)
+		// Track balance change
+		if quorum.Balance != newBalance {
+			balanceHistory := BalanceHistory{
+				QuorumDID:    did,
+				OldBalance:   quorum.Balance,
+				NewBalance:   newBalance,
+				ChangeReason: "Balance update",
+				Timestamp:    time.Now(),
+			}
+			if err := tx.Create(&balanceHistory).Error; err != nil {
+				return err
+			}
+		}
 
-	for _, q := range quorums {
-		// Update assignment count and time
-		ds.db.Model(&q).Updates(map[string]interface{}{
-			"assignment_count": q.AssignmentCount + 1,
-			"last_assignment":  time.Now(),
-		})
+		return tx.Model(&quorum).Update("balance", newBalance).Error
+	})
+}
 
-		result = append(result, models.QuorumData{
-			Type:    2,
-			Address: q.PeerID + "." + q.DID,
-		})
+// ConfirmAvailability confirms that a quorum is available
+func (ds *DBStore) ConfirmAvailability(did string) error {
+	return ds.poolTransaction(func(tx *gorm.DB) error {
+		return confirmAvailability(tx, did)
+	})
+}
 
-		quorumDIDs = append(quorumDIDs, q.DID)
+// confirmAvailability marks a quorum available within an open transaction
+func confirmAvailability(tx *gorm.DB, did string) error {
+	// First check if the quorum exists
+	quorum, err := findQuorum(tx, did)
+	if err != nil {
+		return err
+	}
+	if quorum.ManuallyDisabled {
+		return ErrQuorumDisabled
 	}
 
-	// Record transaction history
-	quorumDIDsJSON, _ := json.Marshal(quorumDIDs)
-	history := TransactionHistory{
-		TransactionID:     fmt.Sprintf("txn_%d", time.Now().UnixNano()),
-		TransactionAmount: transactionAmount,
-		QuorumDIDs:        string(quorumDIDsJSON),
-		RequiredBalance:   requiredBalance,
-		Timestamp:         time.Now(),
+	// Update the quorum availability
+	if err := tx.Model(&QuorumDB{}).
+		Where("did = ?", did).
+		Updates(map[string]interface{}{
+			"available": true,
+			"last_ping": time.Now(),
+		}).Error; err != nil {
+		return err
 	}
-	ds.db.Create(&history)
 
-	return result, nil
+	if !quorum.Available {
+		return recordAvailabilityEvent(tx, did, EventAvailable)
+	}
+	return nil
 }
 
-// UpdateQuorumBalance updates the balance for a quorum
-func (ds *DBStore) UpdateQuorumBalance(did string, newBalance float64) error {
+// OnboardQuorum registers a quorum and confirms its availability in one transaction,
+// returning the stored profile. Nothing is written if either step fails.
+func (ds *DBStore) OnboardQuorum(req *models.QuorumRegistrationRequest) (*models.QuorumInfo, error) {
 	var quorum QuorumDB
+	err := ds.upsertTransaction(func(tx *gorm.DB) error {
+		if err := registerQuorum(tx, req); err != nil {
+			return err
+		}
+		if err := confirmAvailability(tx, req.DID); err != nil {
+			return err
+		}
 
-	if err := ds.db.Where("did = ?", did).First(&quorum).Error; err != nil {
-		return fmt.Errorf("quorum not found: %v", err)
+		var err error
+		quorum, err = findQuorum(tx, req.DID)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Track balance change
-	if quorum.Balance != newBalance {
-		balanceHistory := BalanceHistory{
-			QuorumDID:    did,
-			OldBalance:   quorum.Balance,
-			NewBalance:   newBalance,
-			ChangeReason: "Balance update",
-			Timestamp:    time.Now(),
+	info := ds.toQuorumInfo(quorum)
+	return &info, nil
+}
+
+// SetAvailability lets an operator take a quorum out of rotation or put it back. A
+// disabled quorum keeps heartbeating, but registration and availability confirmations
+// leave it unavailable until it is enabled here again.
+func (ds *DBStore) SetAvailability(did string, available bool) error {
+	return ds.poolTransaction(func(tx *gorm.DB) error {
+		quorum, err := findQuorum(tx, did)
+		if err != nil {
+			return err
 		}
-		ds.db.Create(&balanceHistory)
-	}
+		wasAvailable := quorum.Available
 
-	return ds.db.Model(&quorum).Update("balance", newBalance).Error
+		if err := tx.Model(&quorum).Updates(map[string]interface{}{
+			"available":         available,
+			"manually_disabled": !available,
+		}).Error; err != nil {
+			return err
+		}
+
+		switch {
+		case available && !wasAvailable:
+			return recordAvailabilityEvent(tx, did, EventAvailable)
+		case !available && wasAvailable:
+			return recordAvailabilityEvent(tx, did, EventDisabled)
+		}
+		return nil
+	})
 }
 
-// ConfirmAvailability confirms that a quorum is available
-func (ds *DBStore) ConfirmAvailability(did string) error {
-	// First check if the quorum exists
-	var quorum QuorumDB
-	if err := ds.db.Where("did = ?", did).First(&quorum).Error; err != nil {
-		return fmt.Errorf("quorum not found: %v", err)
+// UpdateHeartbeat updates the last ping time for a quorum. It never changes
+// availability, so a quorum disabled by an operator stays disabled.
+func (ds *DBStore) UpdateHeartbeat(did string) error {
+	result := ds.db.Model(&QuorumDB{}).
+		Where("did = ?", did).
+		Update("last_ping", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrQuorumNotFound
 	}
+	return nil
+}
 
-	// Update the quorum availability
-	return ds.db.Model(&QuorumDB{}).
-		Where("did = ?", did).
-		Updates(map[string]interface{}{
-			"available": true,
+// UpdateHeartbeatBatch records a heartbeat for many quorums with a single update, for
+// nodes hosting many DIDs. It returns how many quorums were updated and the DIDs that
+// aren't registered, which the caller should register again.
+func (ds *DBStore) UpdateHeartbeatBatch(dids []string) (int, []string, error) {
+	defer ds.timeOperation("update_heartbeat_batch", map[string]interface{}{"count": len(dids)})()
+
+	var found []string
+	if err := ds.db.Model(&QuorumDB{}).Where("did IN ?", dids).Pluck("did", &found).Error; err != nil {
+		return 0, nil, err
+	}
+
+	registered := make(map[string]bool, len(found))
+	for _, did := range found {
+		registered[did] = true
+	}
+	var missing []string
+	for _, did := range dids {
+		if !registered[did] {
+			missing = append(missing, did)
+		}
+	}
+	if len(found) == 0 {
+		return 0, missing, nil
+	}
+
+	result := ds.db.Model(&QuorumDB{}).
+		Where("did IN ?", found).
+		Update("last_ping", time.Now())
+	if result.Error != nil {
+		return 0, nil, result.Error
+	}
+	return int(result.RowsAffected), missing, nil
+}
+
+// UpdateHeartbeatDetails records a heartbeat together with any balance, region or
+// metadata updates it carries, applying all of them in a single transaction
+func (ds *DBStore) UpdateHeartbeatDetails(req *models.HeartbeatRequest) error {
+	return ds.db.Transaction(func(tx *gorm.DB) error {
+		quorum, err := findQuorum(tx, req.DID)
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
 			"last_ping": time.Now(),
-		}).Error
+		}
+
+		if req.Balance != nil {
+			updates["balance"] = *req.Balance
+			if quorum.Balance != *req.Balance {
+				balanceHistory := BalanceHistory{
+					QuorumDID:    req.DID,
+					OldBalance:   quorum.Balance,
+					NewBalance:   *req.Balance,
+					ChangeReason: "Heartbeat update",
+					Timestamp:    time.Now(),
+				}
+				if err := tx.Create(&balanceHistory).Error; err != nil {
+					return err
+				}
+			}
+		}
+		if req.Region != nil {
+			updates["region"] = *req.Region
+		}
+		if req.Metadata != nil {
+			updates["metadata"] = marshalMetadata(req.Metadata)
+		}
+
+		return tx.Model(&quorum).Updates(updates).Error
+	})
 }
 
-// UpdateHeartbeat updates the last ping time for a quorum
-func (ds *DBStore) UpdateHeartbeat(did string) error {
-	return ds.db.Model(&QuorumDB{}).
-		Where("did = ?", did).
-		Update("last_ping", time.Now()).Error
+// UnregisterQuorum removes a quorum from the pool. The row is soft-deleted, so audits
+// can still resolve the DID in its history, unless hard is set.
+func (ds *DBStore) UnregisterQuorum(did string, hard bool) error {
+	return ds.poolTransaction(func(tx *gorm.DB) error {
+		return unregisterQuorum(tx, did, hard)
+	})
 }
 
-// UnregisterQuorum removes a quorum from the pool
-func (ds *DBStore) UnregisterQuorum(did string) error {
-	return ds.db.Where("did = ?", did).Delete(&QuorumDB{}).Error
+// unregisterQuorum deletes a quorum, its capabilities and its tokens within an open
+// transaction. A hard delete also purges a row soft-deleted earlier.
+func unregisterQuorum(tx *gorm.DB, did string, hard bool) error {
+	if err := tx.Where("quorum_did = ?", did).Delete(&QuorumCapability{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("quorum_did = ?", did).Delete(&QuorumToken{}).Error; err != nil {
+		return err
+	}
+	query := tx.Where("did = ?", did)
+	if hard {
+		query = query.Unscoped()
+	}
+	result := query.Delete(&QuorumDB{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrQuorumNotFound
+	}
+	return recordAvailabilityEvent(tx, did, EventUnregistered)
 }
 
 // GetQuorumByDID returns a specific quorum by DID
 func (ds *DBStore) GetQuorumByDID(did string) (*models.QuorumInfo, error) {
-	var quorum QuorumDB
-
-	if err := ds.db.Where("did = ?", did).First(&quorum).Error; err != nil {
-		return nil, errors.New("quorum not found")
+	quorum, err := findQuorum(ds.db, did)
+	if err != nil {
+		return nil, err
 	}
 
-	// Deserialize supported tokens from JSON
-	var supportedTokens []string
-	if quorum.SupportedTokens != "" {
-		json.Unmarshal([]byte(quorum.SupportedTokens), &supportedTokens)
-	}
-
-	return &models.QuorumInfo{
-		DID:              quorum.DID,
-		PeerID:           quorum.PeerID,
-		Balance:          quorum.Balance,
-		DIDType:          quorum.DIDType,
-		Available:        quorum.Available,
-		LastPing:         quorum.LastPing,
-		AssignmentCount:  int(quorum.AssignmentCount),
-		LastAssignment:   quorum.LastAssignment,
-		RegistrationTime: quorum.RegistrationTime,
-		SupportedTokens:  supportedTokens,
-	}, nil
+	info := ds.toQuorumInfo(quorum)
+	return &info, nil
 }
 
 // GetAllQuorums returns all registered quorums
 func (ds *DBStore) GetAllQuorums() ([]models.QuorumInfo, error) {
+	return ds.GetNamespaceQuorums("")
+}
+
+// GetNamespaceQuorums returns the quorums registered in namespace, or every quorum
+// when namespace is empty
+func (ds *DBStore) GetNamespaceQuorums(namespace string) ([]models.QuorumInfo, error) {
 	var quorums []QuorumDB
 
-	if err := ds.db.Order("registration_time DESC").Find(&quorums).Error; err != nil {
+	if err := scopeNamespace(ds.reader(), namespace).Order("registration_time DESC").Find(&quorums).Error; err != nil {
 		return nil, err
 	}
 
 	var result []models.QuorumInfo
 	for _, q := range quorums {
-		var supportedTokens []string
-		if q.SupportedTokens != "" {
-			json.Unmarshal([]byte(q.SupportedTokens), &supportedTokens)
-		}
-
-		result = append(result, models.QuorumInfo{
-			DID:              q.DID,
-			PeerID:           q.PeerID,
-			Balance:          q.Balance,
-			DIDType:          q.DIDType,
-			Available:        q.Available,
-			LastPing:         q.LastPing,
-			AssignmentCount:  int(q.AssignmentCount),
-			LastAssignment:   q.LastAssignment,
-			RegistrationTime: q.RegistrationTime,
-			SupportedTokens:  supportedTokens,
-		})
+		result = append(result, ds.toQuorumInfo(q))
 	}
 
 	return result, nil
 }
 
+// QuorumListFilter selects a page of registered quorums. Unset fields don't filter; a
+// Limit of 0 returns every matching quorum.
+type QuorumListFilter struct {
+	Limit      int
+	Offset     int
+	Namespace  string
+	Available  *bool
+	DIDType    *int
+	MinBalance float64 // Total balance, ignoring the reserved floor
+	Token      string  // Supported token, with the empty-tokens policy applied
+	SortBy     string  // One of QuorumSortColumns (default registration_time)
+	Ascending  bool
+}
+
+// QuorumSortColumns are the fields a quorum listing can be sorted by
+var QuorumSortColumns = map[string]bool{
+	"registration_time": true,
+	"balance":           true,
+	"assignment_count":  true,
+	"last_ping":         true,
+}
+
+// QuorumListPage is one page of quorums and the total number matching the filter
+type QuorumListPage struct {
+	Total   int64
+	Limit   int
+	Offset  int
+	Quorums []models.QuorumInfo
+}
+
+// ListQuorums returns a page of registered quorums matching filter, sorted by
+// filter.SortBy (newest registrations first by default)
+func (ds *DBStore) ListQuorums(filter QuorumListFilter) (*QuorumListPage, error) {
+	sortBy := filter.SortBy
+	if sortBy == "" {
+		sortBy = "registration_time"
+	}
+	if !QuorumSortColumns[sortBy] {
+		return nil, fmt.Errorf("cannot sort quorums by %q", sortBy)
+	}
+
+	query := scopeNamespace(ds.reader().Model(&QuorumDB{}), filter.Namespace)
+	if filter.Available != nil {
+		query = query.Where("available = ?", *filter.Available)
+	}
+	if filter.DIDType != nil {
+		query = query.Where("did_type = ?", *filter.DIDType)
+	}
+	if filter.MinBalance > 0 {
+		query = query.Where("balance >= ?", filter.MinBalance)
+	}
+	if filter.Token != "" {
+		query = ds.applyTokenFilter(query, filter.Token)
+	}
+
+	page := &QuorumListPage{Limit: filter.Limit, Offset: filter.Offset, Quorums: []models.QuorumInfo{}}
+	if err := query.Count(&page.Total).Error; err != nil {
+		return nil, err
+	}
+
+	// DID breaks ties so pages don't overlap when many quorums share a sort value
+	direction := " DESC"
+	if filter.Ascending {
+		direction = " ASC"
+	}
+	query = query.Order(sortBy + direction).Order("did ASC").Offset(filter.Offset)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var quorums []QuorumDB
+	if err := query.Find(&quorums).Error; err != nil {
+		return nil, err
+	}
+	for _, q := range quorums {
+		page.Quorums = append(page.Quorums, ds.toQuorumInfo(q))
+	}
+	return page, nil
+}
+
+// toQuorumInfo converts a database row into the API representation, including derived fields
+func (ds *DBStore) toQuorumInfo(q QuorumDB) models.QuorumInfo {
+	// Deserialize supported tokens from JSON
+	var supportedTokens []string
+	if q.SupportedTokens != "" {
+		json.Unmarshal([]byte(q.SupportedTokens), &supportedTokens)
+	}
+
+	var capabilities []string
+	if q.Capabilities != "" {
+		json.Unmarshal([]byte(q.Capabilities), &capabilities)
+	}
+
+	var metadata map[string]string
+	if q.Metadata != "" {
+		json.Unmarshal([]byte(q.Metadata), &metadata)
+	}
+
+	return models.QuorumInfo{
+		DID:              q.DID,
+		PeerID:           q.PeerID,
+		Balance:          q.Balance,
+		DIDType:          q.DIDType,
+		Available:        q.Available,
+		ManuallyDisabled: q.ManuallyDisabled,
+		LastPing:         q.LastPing,
+		AssignmentCount:  int(q.AssignmentCount),
+		LastAssignment:   q.LastAssignment,
+		RegistrationTime: q.RegistrationTime,
+		SupportedTokens:  supportedTokens,
+		Capabilities:     capabilities,
+		Region:           q.Region,
+		Tier:             q.Tier,
+		Namespace:        q.Namespace,
+		Metadata:         metadata,
+
+		MinParticipationBalance: q.MinParticipationBalance,
+		MaxTransactionShare:     q.MaxTransactionShare,
+
+		NextHeartbeatDeadline: q.LastPing.Add(ds.config.AvailabilityWindow),
+	}
+}
+
+// marshalMetadata serializes quorum metadata, storing an empty map as an empty string
+func marshalMetadata(metadata map[string]string) string {
+	if len(metadata) == 0 {
+		return ""
+	}
+	metadataJSON, _ := json.Marshal(metadata)
+	return string(metadataJSON)
+}
+
+// GetPeerDIDs groups registered DIDs by peer ID, for debugging peers hosting several DIDs
+func (ds *DBStore) GetPeerDIDs() (map[string][]string, error) {
+	var rows []QuorumDB
+	if err := ds.db.Select("peer_id", "did").Order("peer_id, did").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	peers := make(map[string][]string)
+	for _, row := range rows {
+		peers[row.PeerID] = append(peers[row.PeerID], row.DID)
+	}
+	return peers, nil
+}
+
 // GetHealthStatus returns the health status of the storage
 func (ds *DBStore) GetHealthStatus() models.HealthStatus {
+	return ds.GetNamespaceHealthStatus("")
+}
+
+// GetNamespaceHealthStatus returns pool counts for namespace, or across every
+// namespace when namespace is empty
+func (ds *DBStore) GetNamespaceHealthStatus(namespace string) models.HealthStatus {
 	var totalQuorums int64
 	var availableQuorums int64
 
-	ds.db.Model(&QuorumDB{}).Count(&totalQuorums)
-	ds.db.Model(&QuorumDB{}).
-		Where("available = ?", true).
-		Where("last_ping > ?", time.Now().Add(-5*time.Minute)).
+	scopeNamespace(ds.db.Model(&QuorumDB{}), namespace).Count(&totalQuorums)
+	ds.applyHeartbeatFilter(scopeNamespace(ds.db.Model(&QuorumDB{}), namespace).Where("available = ?", true)).
 		Count(&availableQuorums)
 
-	return models.HealthStatus{
+	pool := ds.PoolStats()
+	health := models.HealthStatus{
 		Status:           "healthy",
 		TotalQuorums:     int(totalQuorums),
 		AvailableQuorums: int(availableQuorums),
+		Tokens:           ds.tokenAvailability(namespace),
 		LastCheck:        time.Now(),
+		ConnectionPool: &models.ConnectionPoolStats{
+			MaxOpen:        pool.MaxOpenConnections,
+			Open:           pool.OpenConnections,
+			InUse:          pool.InUse,
+			Idle:           pool.Idle,
+			WaitCount:      pool.WaitCount,
+			WaitDurationMs: pool.WaitDuration.Milliseconds(),
+		},
 	}
+
+	if ds.replica != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		if lag, err := ds.ReplicationLag(ctx); err != nil {
+			health.Status = "degraded"
+			health.ReplicaError = err.Error()
+		} else {
+			seconds := lag.Seconds()
+			health.ReplicationLagSeconds = &seconds
+			if lag > ds.config.MaxReplicaLag {
+				health.Status = "degraded"
+			}
+		}
+	}
+
+	return health
 }
 
-// CleanupStaleQuorums removes quorums that haven't pinged in a while
-func (ds *DBStore) CleanupStaleQuorums() int {
-	staleThreshold := 10 * time.Minute
+// CleanupStaleQuorums marks quorums that haven't pinged within the stale threshold as
+// unavailable. It is a no-op while staleness is frozen.
+func (ds *DBStore) CleanupStaleQuorums() (int, error) {
+	if _, frozen, err := ds.StalenessFrozenUntil(); err != nil || frozen {
+		return 0, err
+	}
+
+	staleThreshold := ds.config.StaleThreshold
 
-	result := ds.db.Model(&QuorumDB{}).
-		Where("last_ping < ?", time.Now().Add(-staleThreshold)).
-		Update("available", false)
+	var stale []QuorumDB
+	err := ds.poolTransaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&QuorumDB{}).
+			Select("did", "peer_id").
+			Where("available = ?", true).
+			Where("last_ping < ?", time.Now().Add(-staleThreshold)).
+			Find(&stale).Error; err != nil || len(stale) == 0 {
+			return err
+		}
 
-	return int(result.RowsAffected)
+		staleDIDs := make([]string, 0, len(stale))
+		for _, q := range stale {
+			staleDIDs = append(staleDIDs, q.DID)
+		}
+		if err := tx.Model(&QuorumDB{}).
+			Where("did IN ?", staleDIDs).
+			Update("available", false).Error; err != nil {
+			return err
+		}
+
+		for _, did := range staleDIDs {
+			if err := recordAvailabilityEvent(tx, did, EventStale); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Delivery is queued in the background, so slow subscribers don't hold up cleanup
+	staleEvents := make([]interface{}, 0, len(stale))
+	for _, q := range stale {
+		staleEvents = append(staleEvents, webhooks.QuorumEvent{DID: q.DID, PeerID: q.PeerID})
+	}
+	ds.publisher.Publish(webhooks.EventQuorumStale, staleEvents...)
+
+	return len(stale), nil
 }
 
-// GetQuorumStats returns statistics for a quorum
+// GetQuorumStats returns statistics for a quorum. A registered quorum that has never
+// been assigned gets zeroed stats; an unknown DID without stats is ErrQuorumNotFound.
 func (ds *DBStore) GetQuorumStats(did string) (*QuorumStats, error) {
 	var stats QuorumStats
 
-	err := ds.db.Where("quorum_did = ?", did).First(&stats).Error
-	if err == gorm.ErrRecordNotFound {
-		// Create new stats record
-		stats = QuorumStats{
-			QuorumDID: did,
+	err := ds.db.Where("quorum_d_id = ?", did).First(&stats).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if _, err := findQuorum(ds.db, did); err != nil {
+			return nil, err
 		}
-		ds.db.Create(&stats)
+		return &QuorumStats{QuorumDID: did}, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
 	return &stats, nil
 }
 
-// GetTransactionHistory returns transaction history
-func (ds *DBStore) GetTransactionHistory(limit int) ([]TransactionHistory, error) {
-	var history []TransactionHistory
+// recordAssignmentStats counts an assignment of share toward did's stats within an
+// open transaction, creating the stats row on a quorum's first assignment
+func recordAssignmentStats(tx *gorm.DB, did string, share float64, at time.Time) error {
+	result := tx.Model(&QuorumStats{}).Where("quorum_d_id = ?", did).Updates(map[string]interface{}{
+		"total_transactions": gorm.Expr("total_transactions + ?", 1),
+		"total_amount":       gorm.Expr("total_amount + ?", share),
+		"last_active":        at,
+	})
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.Error
+	}
+	return tx.Create(&QuorumStats{
+		QuorumDID:         did,
+		TotalTransactions: 1,
+		TotalAmount:       share,
+		LastActive:        at,
+	}).Error
+}
 
-	query := ds.db.Order("created_at DESC")
-	if limit > 0 {
-		query = query.Limit(limit)
+// GetBalanceHistory returns up to limit recorded balance changes for did, newest first.
+// A DID with no changes yields an empty list when it is registered and
+// ErrQuorumNotFound otherwise; history outlives unregistration.
+func (ds *DBStore) GetBalanceHistory(did string, limit int) ([]BalanceHistory, error) {
+	var history []BalanceHistory
+	if err := ds.db.Where("quorum_d_id = ?", did).
+		Order("timestamp DESC").Order("id DESC").
+		Limit(limit).
+		Find(&history).Error; err != nil {
+		return nil, err
+	}
+
+	if len(history) == 0 {
+		if _, err := findQuorum(ds.db, did); err != nil {
+			return nil, err
+		}
+	}
+	return history, nil
+}
+
+// TransactionHistoryFilter selects a page of transaction history. Zero-valued bounds
+// are unset; a Limit of 0 returns every matching record.
+type TransactionHistoryFilter struct {
+	Limit     int
+	Offset    int
+	From      time.Time // Recorded at or after
+	To        time.Time // Recorded before
+	MinAmount float64
+	MaxAmount float64
+}
+
+// TransactionHistoryPage is one page of transaction history and the total number of
+// records matching the filter, for building pagers
+type TransactionHistoryPage struct {
+	Total   int64
+	Limit   int
+	Offset  int
+	History []TransactionHistory
+}
+
+// GetTransactionHistory returns a page of transaction history, newest first
+func (ds *DBStore) GetTransactionHistory(filter TransactionHistoryFilter) (*TransactionHistoryPage, error) {
+	query := ds.reader().Model(&TransactionHistory{})
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	if filter.MinAmount > 0 {
+		query = query.Where("transaction_amount >= ?", filter.MinAmount)
+	}
+	if filter.MaxAmount > 0 {
+		query = query.Where("transaction_amount <= ?", filter.MaxAmount)
+	}
+
+	page := &TransactionHistoryPage{Limit: filter.Limit, Offset: filter.Offset, History: []TransactionHistory{}}
+	if err := query.Count(&page.Total).Error; err != nil {
+		return nil, err
 	}
 
-	err := query.Find(&history).Error
-	return history, err
+	query = query.Order("created_at DESC").Offset(filter.Offset)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if err := query.Find(&page.History).Error; err != nil {
+		return nil, err
+	}
+	return page, nil
 }
@@ -0,0 +1,225 @@
+package storage
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+// testDID returns a valid DID ending in suffix
+func testDID(suffix string) string {
+	return "bafybmi" + strings.Repeat("a", 52-len(suffix)) + suffix
+}
+
+// newTestStore opens a migrated SQLite store in a temporary directory
+func newTestStore(t *testing.T, config DBConfig) *DBStore {
+	t.Helper()
+	config.Type = "sqlite"
+	config.Database = filepath.Join(t.TempDir(), "advisory.db")
+	config.AutoMigrate = true
+
+	ds, err := NewDBStore(config)
+	if err != nil {
+		t.Fatalf("NewDBStore: %v", err)
+	}
+	t.Cleanup(func() { ds.Close() })
+	return ds
+}
+
+// registerTestQuorum registers a DID type 4 quorum holding balance, with its peer ID
+// derived from suffix, and returns its DID
+func registerTestQuorum(t *testing.T, store Store, suffix string, balance float64, modify ...func(*models.QuorumRegistrationRequest)) string {
+	t.Helper()
+	req := &models.QuorumRegistrationRequest{
+		DID:     testDID(suffix),
+		PeerID:  "peer" + suffix,
+		Balance: balance,
+		DIDType: 4,
+	}
+	for _, m := range modify {
+		m(req)
+	}
+	if err := store.RegisterQuorum(req); err != nil {
+		t.Fatalf("RegisterQuorum(%s): %v", suffix, err)
+	}
+	return req.DID
+}
+
+// setQuorumColumn overwrites one column of a quorum's row, to craft pool states the API
+// can't produce directly
+func setQuorumColumn(t *testing.T, ds *DBStore, did, column string, value interface{}) {
+	t.Helper()
+	if err := ds.db.Model(&QuorumDB{}).Where("did = ?", did).Update(column, value).Error; err != nil {
+		t.Fatalf("setting %s of %s: %v", column, did, err)
+	}
+}
+
+// selectionRequest is a selection of count quorums for amount, the parameters every
+// selection test starts from
+func selectionRequest(count int, amount float64) models.QuorumListRequest {
+	return models.QuorumListRequest{Count: count, TransactionAmount: amount}
+}
+
+// selectedDIDs returns the DIDs of a selection's quorums in order
+func selectedDIDs(result *SelectionResult) []string {
+	return quorumDIDs(result.Quorums)
+}
+
+// quorumDIDs returns the DIDs of selected quorums in order
+func quorumDIDs(quorums []models.QuorumData) []string {
+	dids := make([]string, 0, len(quorums))
+	for _, q := range quorums {
+		// Addresses are "PeerID.DID"
+		dids = append(dids, q.Address[strings.LastIndex(q.Address, ".")+1:])
+	}
+	return dids
+}
+
+func TestNextHeartbeatDeadline(t *testing.T) {
+	ds := newTestStore(t, DBConfig{AvailabilityWindow: 90 * time.Second})
+	did := registerTestQuorum(t, ds, "1", 100)
+
+	quorum, err := ds.GetQuorumByDID(did)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := quorum.LastPing.Add(90 * time.Second); !quorum.NextHeartbeatDeadline.Equal(want) {
+		t.Errorf("deadline = %v, want last_ping + 90s = %v", quorum.NextHeartbeatDeadline, want)
+	}
+
+	ms := NewMemoryStore()
+	if err := ms.RegisterQuorum(&models.QuorumRegistrationRequest{DID: did, PeerID: "peer1", Balance: 100, DIDType: 4}); err != nil {
+		t.Fatal(err)
+	}
+	quorum, err = ms.GetQuorumByDID(did)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := quorum.LastPing.Add(DefaultAvailabilityWindow); !quorum.NextHeartbeatDeadline.Equal(want) {
+		t.Errorf("memory store deadline = %v, want last_ping + %v = %v", quorum.NextHeartbeatDeadline, DefaultAvailabilityWindow, want)
+	}
+}
+
+func TestNewDBStoreDefaultsNonPositiveWindows(t *testing.T) {
+	for _, window := range []time.Duration{0, -time.Minute} {
+		ds := newTestStore(t, DBConfig{AvailabilityWindow: window, StaleThreshold: window})
+		if ds.config.AvailabilityWindow != DefaultAvailabilityWindow || ds.config.StaleThreshold != DefaultStaleThreshold {
+			t.Errorf("windows of %s became %s and %s, want the defaults", window, ds.config.AvailabilityWindow, ds.config.StaleThreshold)
+		}
+	}
+}
+
+func TestNamespaceIsolation(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	inTeamB := func(req *models.QuorumRegistrationRequest) { req.Namespace = "team-b" }
+	registerTestQuorum(t, ds, "1", 100)
+	registerTestQuorum(t, ds, "2", 100)
+	teamB := registerTestQuorum(t, ds, "3", 100, inTeamB)
+	silent := registerTestQuorum(t, ds, "4", 100, inTeamB)
+	setQuorumColumn(t, ds, silent, "last_ping", time.Now().Add(-time.Hour))
+
+	result, err := ds.SelectQuorums(selectionRequest(2, 2))
+	if err != nil {
+		t.Fatalf("default namespace selection: %v", err)
+	}
+	for _, did := range selectedDIDs(result) {
+		if did == teamB {
+			t.Errorf("default namespace selection took %s from team-b", did)
+		}
+	}
+	if _, err := ds.SelectQuorums(selectionRequest(3, 3)); err == nil {
+		t.Error("default namespace selection of 3 reached into team-b")
+	}
+
+	req := selectionRequest(1, 1)
+	req.Namespace = "team-b"
+	result, err = ds.SelectQuorums(req)
+	if err != nil {
+		t.Fatalf("team-b selection: %v", err)
+	}
+	if got := selectedDIDs(result); len(got) != 1 || got[0] != teamB {
+		t.Errorf("team-b selection = %v, want %s", got, teamB)
+	}
+	req = selectionRequest(2, 2)
+	req.Namespace = "team-b"
+	if _, err := ds.SelectQuorums(req); err == nil {
+		t.Error("team-b selection of 2 reached into the default namespace")
+	}
+
+	for namespace, want := range map[string][2]int{"": {4, 3}, "default": {2, 2}, "team-b": {2, 1}} {
+		health := ds.GetNamespaceHealthStatus(namespace)
+		if health.TotalQuorums != want[0] || health.AvailableQuorums != want[1] {
+			t.Errorf("health of %q = %d total, %d available; want %d, %d",
+				namespace, health.TotalQuorums, health.AvailableQuorums, want[0], want[1])
+		}
+	}
+}
+
+func TestGetPeerDIDs(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	onPeer := func(peerID string) func(*models.QuorumRegistrationRequest) {
+		return func(req *models.QuorumRegistrationRequest) { req.PeerID = peerID }
+	}
+	first := registerTestQuorum(t, ds, "1", 100, onPeer("peerA"))
+	second := registerTestQuorum(t, ds, "2", 100, onPeer("peerA"))
+	third := registerTestQuorum(t, ds, "3", 100, onPeer("peerB"))
+	gone := registerTestQuorum(t, ds, "4", 100, onPeer("peerC"))
+	if err := ds.UnregisterQuorum(gone, false); err != nil {
+		t.Fatal(err)
+	}
+
+	peers, err := ds.GetPeerDIDs()
+	if err != nil {
+		t.Fatalf("GetPeerDIDs: %v", err)
+	}
+	want := map[string][]string{"peerA": {first, second}, "peerB": {third}}
+	if len(peers) != len(want) {
+		t.Fatalf("peers = %v, want %v", peers, want)
+	}
+	for peerID, dids := range want {
+		if !slices.Equal(peers[peerID], dids) {
+			t.Errorf("DIDs of %s = %v, want %v", peerID, peers[peerID], dids)
+		}
+	}
+}
+
+func TestConcurrentFirstRegistrationKeepsOneRow(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	did := testDID("1")
+
+	for round := 0; round < 10; round++ {
+		req := func() *models.QuorumRegistrationRequest {
+			return &models.QuorumRegistrationRequest{DID: did, PeerID: "peer1", Balance: 100, DIDType: 4}
+		}
+		start := make(chan struct{})
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func(req *models.QuorumRegistrationRequest) {
+				<-start
+				errs <- ds.RegisterQuorum(req)
+			}(req())
+		}
+		close(start)
+		for i := 0; i < 2; i++ {
+			if err := <-errs; err != nil {
+				t.Fatalf("round %d: concurrent RegisterQuorum: %v", round, err)
+			}
+		}
+
+		var rows int64
+		if err := ds.db.Unscoped().Model(&QuorumDB{}).Where("did = ?", did).Count(&rows).Error; err != nil {
+			t.Fatal(err)
+		}
+		if rows != 1 {
+			t.Fatalf("round %d: %d rows for %s, want 1", round, rows, did)
+		}
+		// Start the next round from an unregistered DID again
+		if err := ds.db.Unscoped().Where("did = ?", did).Delete(&QuorumDB{}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultTierWeights are the collateral tiers used when none are configured. A quorum's
+// weight is how many assignments it absorbs for each one an untiered quorum takes.
+func DefaultTierWeights() map[string]float64 {
+	return map[string]float64{
+		"bronze": 1,
+		"silver": 2,
+		"gold":   3,
+	}
+}
+
+// HasTier reports whether tier is one of the configured collateral tiers
+func (ds *DBStore) HasTier(tier string) bool {
+	_, ok := ds.config.TierWeights[tier]
+	return ok
+}
+
+// tiersAtLeast returns every configured tier weighted at least as heavily as minTier
+func (ds *DBStore) tiersAtLeast(minTier string) []string {
+	minWeight := ds.config.TierWeights[minTier]
+
+	tiers := make([]string, 0, len(ds.config.TierWeights))
+	for tier, weight := range ds.config.TierWeights {
+		if weight >= minWeight {
+			tiers = append(tiers, tier)
+		}
+	}
+	sort.Strings(tiers)
+	return tiers
+}
+
+// tierPattern restricts tier names so they can be inlined in the selection ORDER BY
+var tierPattern = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// validateTierWeights checks tier names and requires every weight to be positive
+func validateTierWeights(weights map[string]float64) error {
+	for tier, weight := range weights {
+		if !tierPattern.MatchString(tier) {
+			return fmt.Errorf("invalid tier name %q: use up to 32 lowercase letters, digits, '-' or '_'", tier)
+		}
+		if weight <= 0 {
+			return fmt.Errorf("tier %q must have a positive weight", tier)
+		}
+	}
+	return nil
+}
+
+// weightedAssignmentOrder ranks quorums by assignment count divided by tier weight, so
+// a tier weighted 3 is picked about three times as often as an untiered (weight 1) one.
+// Tier names are validated in NewDBStore, so they are safe to inline.
+func (ds *DBStore) weightedAssignmentOrder() string {
+	tiers := make([]string, 0, len(ds.config.TierWeights))
+	for tier := range ds.config.TierWeights {
+		tiers = append(tiers, tier)
+	}
+	sort.Strings(tiers)
+
+	var order strings.Builder
+	order.WriteString("assignment_count / (CASE tier")
+	for _, tier := range tiers {
+		fmt.Fprintf(&order, " WHEN '%s' THEN %s", tier, strconv.FormatFloat(ds.config.TierWeights[tier], 'f', -1, 64))
+	}
+	order.WriteString(" ELSE 1.0 END) ASC")
+	return order.String()
+}
+
+// tierWeight returns a tier's selection weight, 1 for untiered or unknown tiers as in
+// weightedAssignmentOrder
+func (ds *DBStore) tierWeight(tier string) float64 {
+	if weight, ok := ds.config.TierWeights[tier]; ok {
+		return weight
+	}
+	return 1
+}
@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+func TestTierWeightingAndMinTier(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	inTier := func(tier string) func(*models.QuorumRegistrationRequest) {
+		return func(req *models.QuorumRegistrationRequest) { req.Tier = tier }
+	}
+	bronze := registerTestQuorum(t, ds, "1", 1000, inTier("bronze"))
+	silver := registerTestQuorum(t, ds, "2", 1000, inTier("silver"))
+	gold := registerTestQuorum(t, ds, "3", 1000, inTier("gold"))
+
+	quorum, err := ds.GetQuorumByDID(gold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quorum.Tier != "gold" {
+		t.Errorf("info tier = %q, want gold", quorum.Tier)
+	}
+
+	// Load splits 1:2:3 across the default weights
+	picks := make(map[string]int)
+	for i := 0; i < 60; i++ {
+		result, err := ds.SelectQuorums(selectionRequest(1, 1))
+		if err != nil {
+			t.Fatalf("selection %d: %v", i, err)
+		}
+		picks[selectedDIDs(result)[0]]++
+	}
+	for did, want := range map[string]int{bronze: 10, silver: 20, gold: 30} {
+		if got := picks[did]; got < want-1 || got > want+1 {
+			t.Errorf("%s picked %d of 60 times, want about %d", did, got, want)
+		}
+	}
+
+	req := selectionRequest(2, 2)
+	req.MinTier = "silver"
+	result, err := ds.SelectQuorums(req)
+	if err != nil {
+		t.Fatalf("SelectQuorums with min_tier=silver: %v", err)
+	}
+	got := selectedDIDs(result)
+	slices.Sort(got)
+	if want := []string{silver, gold}; !slices.Equal(got, want) {
+		t.Errorf("min_tier=silver selected %v, want %v", got, want)
+	}
+
+	req = selectionRequest(2, 2)
+	req.MinTier = "gold"
+	if _, err := ds.SelectQuorums(req); err == nil {
+		t.Error("min_tier=gold filled a selection of 2 from one gold quorum")
+	}
+}
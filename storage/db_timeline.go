@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+// Timeline event sources
+const (
+	TimelineSourceBalance      = "balance"
+	TimelineSourceAvailability = "availability"
+	TimelineSourceTransaction  = "transaction"
+)
+
+// timelineAssignment is one assignment of the quorum with its transaction's details
+type timelineAssignment struct {
+	Timestamp         time.Time
+	TransactionID     string
+	TransactionAmount float64
+	Token             string
+}
+
+// GetQuorumTimeline returns up to limit events for a quorum at or after since, oldest
+// first, or ErrQuorumNotFound when the DID is not registered and has no events in
+// range. Balance changes, availability events and transaction assignments are each
+// queried with the same bounds and merged, so the first limit events of the merged
+// stream are always among those fetched.
+func (ds *DBStore) GetQuorumTimeline(did string, since time.Time, limit int) ([]models.TimelineEvent, error) {
+	var balances []BalanceHistory
+	if err := ds.db.Where("quorum_d_id = ? AND timestamp >= ?", did, since).
+		Order("timestamp ASC").Limit(limit).
+		Find(&balances).Error; err != nil {
+		return nil, fmt.Errorf("failed to load balance history: %v", err)
+	}
+
+	var availability []AvailabilityEvent
+	if err := ds.db.Where("quorum_d_id = ? AND timestamp >= ?", did, since).
+		Order("timestamp ASC").Limit(limit).
+		Find(&availability).Error; err != nil {
+		return nil, fmt.Errorf("failed to load availability events: %v", err)
+	}
+
+	// Assignments are indexed by quorum and time; the transaction's history row adds its
+	// amount and token
+	var transactions []timelineAssignment
+	if err := ds.db.Model(&QuorumAssignment{}).
+		Select("quorum_assignments.timestamp, quorum_assignments.transaction_id, "+
+			"transaction_history.transaction_amount, transaction_history.token").
+		Joins("LEFT JOIN transaction_history ON transaction_history.transaction_id = quorum_assignments.transaction_id").
+		Where("quorum_assignments.quorum_did = ? AND quorum_assignments.timestamp >= ?", did, since).
+		Order("quorum_assignments.timestamp ASC").Limit(limit).
+		Scan(&transactions).Error; err != nil {
+		return nil, fmt.Errorf("failed to load transaction assignments: %v", err)
+	}
+
+	events := make([]models.TimelineEvent, 0, len(balances)+len(availability)+len(transactions))
+	for _, b := range balances {
+		oldBalance, newBalance := b.OldBalance, b.NewBalance
+		events = append(events, models.TimelineEvent{
+			Timestamp:  b.Timestamp,
+			Source:     TimelineSourceBalance,
+			Event:      b.ChangeReason,
+			OldBalance: &oldBalance,
+			NewBalance: &newBalance,
+		})
+	}
+	for _, a := range availability {
+		events = append(events, models.TimelineEvent{
+			Timestamp: a.Timestamp,
+			Source:    TimelineSourceAvailability,
+			Event:     a.Event,
+		})
+	}
+	for _, t := range transactions {
+		events = append(events, models.TimelineEvent{
+			Timestamp:         t.Timestamp,
+			Source:            TimelineSourceTransaction,
+			Event:             "assigned",
+			TransactionID:     t.TransactionID,
+			TransactionAmount: t.TransactionAmount,
+			Token:             t.Token,
+		})
+	}
+
+	// History outlives registration, so an empty timeline is only an error for unknown DIDs
+	if len(events) == 0 {
+		if _, err := findQuorum(ds.db, did); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+
+	return events, nil
+}
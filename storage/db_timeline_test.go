@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+func TestQuorumTimelineIsChronological(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	did := registerTestQuorum(t, ds, "1", 100)
+	other := registerTestQuorum(t, ds, "2", 100)
+
+	// Seed each source out of order, interleaved with the others, an hour before the
+	// registration's own events
+	base := time.Now().Add(-time.Hour)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	seed := []interface{}{
+		&BalanceHistory{QuorumDID: did, OldBalance: 80, NewBalance: 100, ChangeReason: "update", Timestamp: at(5)},
+		&BalanceHistory{QuorumDID: did, OldBalance: 50, NewBalance: 80, ChangeReason: "update", Timestamp: at(3)},
+		&AvailabilityEvent{QuorumDID: did, Event: EventAvailable, Timestamp: at(4)},
+		&AvailabilityEvent{QuorumDID: did, Event: EventStale, Timestamp: at(1)},
+		&TransactionHistory{TransactionID: "txn-b", TransactionAmount: 60, Token: "TRI", QuorumDIDs: `["` + other + `","` + did + `"]`, Timestamp: at(6)},
+		&QuorumAssignment{TransactionID: "txn-b", QuorumDID: other, Timestamp: at(6)},
+		&QuorumAssignment{TransactionID: "txn-b", QuorumDID: did, Timestamp: at(6)},
+		&TransactionHistory{TransactionID: "txn-a", TransactionAmount: 20, Token: "RBT", QuorumDIDs: `["` + did + `"]`, Timestamp: at(2)},
+		&QuorumAssignment{TransactionID: "txn-a", QuorumDID: did, Timestamp: at(2)},
+		// Another quorum's activity stays off the timeline
+		&TransactionHistory{TransactionID: "txn-other", QuorumDIDs: `["` + other + `"]`, Timestamp: at(3)},
+		&QuorumAssignment{TransactionID: "txn-other", QuorumDID: other, Timestamp: at(3)},
+		&AvailabilityEvent{QuorumDID: other, Event: EventStale, Timestamp: at(2)},
+	}
+	for _, row := range seed {
+		if err := ds.db.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events, err := ds.GetQuorumTimeline(did, base, 100)
+	if err != nil {
+		t.Fatalf("GetQuorumTimeline: %v", err)
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].Timestamp.Before(events[i-1].Timestamp) {
+			t.Fatalf("event %d (%s at %s) precedes event %d (%s at %s)",
+				i, events[i].Event, events[i].Timestamp, i-1, events[i-1].Event, events[i-1].Timestamp)
+		}
+	}
+
+	var sources []string
+	for _, e := range events {
+		sources = append(sources, e.Source+":"+e.Event+e.TransactionID)
+	}
+	want := []string{
+		"availability:" + EventStale,
+		"transaction:assignedtxn-a",
+		"balance:update",
+		"availability:" + EventAvailable,
+		"balance:update",
+		"transaction:assignedtxn-b",
+	}
+	// The seeded events come first, then whatever registration logged
+	if len(sources) <= len(want) || !slices.Equal(sources[:len(want)], want) {
+		t.Errorf("timeline = %v, want it to start %v", sources, want)
+	}
+	if *events[2].NewBalance != 80 || *events[4].NewBalance != 100 {
+		t.Errorf("balance events = %v then %v, want 80 then 100", *events[2].NewBalance, *events[4].NewBalance)
+	}
+	if events[1].TransactionAmount != 20 || events[1].Token != "RBT" || events[5].TransactionAmount != 60 || events[5].Token != "TRI" {
+		t.Errorf("transaction events = %+v and %+v, want 20 RBT and 60 TRI", events[1], events[5])
+	}
+
+	// since and limit cut the merged stream, not each source
+	events, err = ds.GetQuorumTimeline(did, at(3), 3)
+	if err != nil {
+		t.Fatalf("GetQuorumTimeline since: %v", err)
+	}
+	if len(events) != 3 || !events[0].Timestamp.Equal(at(3)) || !events[2].Timestamp.Equal(at(5)) {
+		t.Errorf("timeline since minute 3 = %+v, want minutes 3 to 5", events)
+	}
+}
+
+func TestQuorumTimelineShowsSelections(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	supportsTRI := func(req *models.QuorumRegistrationRequest) { req.SupportedTokens = []string{"TRI"} }
+	did := registerTestQuorum(t, ds, "1", 100, supportsTRI)
+
+	req := selectionRequest(1, 10)
+	req.FTName = "TRI"
+	req.TransactionID = "txn-tri"
+	if _, err := ds.SelectQuorums(req); err != nil {
+		t.Fatalf("SelectQuorums: %v", err)
+	}
+
+	events, err := ds.GetQuorumTimeline(did, time.Time{}, 100)
+	if err != nil {
+		t.Fatalf("GetQuorumTimeline: %v", err)
+	}
+	var assigned []models.TimelineEvent
+	for _, e := range events {
+		if e.Source == TimelineSourceTransaction {
+			assigned = append(assigned, e)
+		}
+	}
+	if len(assigned) != 1 || assigned[0].TransactionID != "txn-tri" || assigned[0].TransactionAmount != 10 || assigned[0].Token != "TRI" {
+		t.Errorf("transaction events = %+v, want txn-tri for 10 TRI", assigned)
+	}
+
+	if !ds.db.Migrator().HasIndex(&QuorumAssignment{}, "idx_quorum_assignments_did_time") {
+		t.Error("quorum_assignments has no (quorum_did, timestamp) index")
+	}
+}
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/gklps/advisory-node/metrics"
+)
+
+// DefaultSlowQueryThreshold is how long a store operation may take before it is logged as slow
+const DefaultSlowQueryThreshold = 500 * time.Millisecond
+
+// timeOperation starts timing a store operation; call the returned function when it
+// completes. The duration is recorded in advisory_operation_duration_seconds, and
+// operations slower than SlowQueryThreshold are logged with their parameters.
+//
+//	defer ds.timeOperation("select_quorums", req)()
+func (ds *DBStore) timeOperation(operation string, params interface{}) func() {
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		metrics.OperationDuration.WithLabelValues(operation).Observe(elapsed.Seconds())
+
+		if elapsed >= ds.config.SlowQueryThreshold {
+			paramsJSON, _ := json.Marshal(params)
+			log.Printf("🐢 Slow operation op=%s duration=%s threshold=%s params=%s\n",
+				operation, elapsed.Round(time.Millisecond), ds.config.SlowQueryThreshold, paramsJSON)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func TestSlowSelectionIsLogged(t *testing.T) {
+	ds := newTestStore(t, DBConfig{SlowQueryThreshold: 300 * time.Millisecond})
+	registerTestQuorum(t, ds, "1", 100)
+
+	var logged bytes.Buffer
+	previous := log.Writer()
+	log.SetOutput(&logged)
+	t.Cleanup(func() { log.SetOutput(previous) })
+
+	if _, err := ds.SelectQuorums(selectionRequest(1, 1)); err != nil {
+		t.Fatalf("SelectQuorums: %v", err)
+	}
+	if strings.Contains(logged.String(), "Slow operation") {
+		t.Fatalf("fast selection logged as slow: %s", logged.String())
+	}
+
+	// The next query takes longer than the threshold
+	var slowed atomic.Bool
+	if err := ds.db.Callback().Query().Before("gorm:query").Register("test:slow", func(*gorm.DB) {
+		if slowed.CompareAndSwap(false, true) {
+			time.Sleep(400 * time.Millisecond)
+		}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ds.SelectQuorums(selectionRequest(1, 1)); err != nil {
+		t.Fatalf("slow SelectQuorums: %v", err)
+	}
+	if !strings.Contains(logged.String(), "Slow operation op=select_quorums") {
+		t.Errorf("slow selection not logged, got: %s", logged.String())
+	}
+}
@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/gklps/advisory-node/models"
+	"gorm.io/gorm"
+)
+
+// QuorumToken is the normalized supported token list used for selection filtering. The
+// JSON supported_tokens column is kept for display; filters match against these rows.
+type QuorumToken struct {
+	ID        uint   `gorm:"primaryKey"`
+	QuorumDID string `gorm:"column:quorum_did;not null;size:59;uniqueIndex:idx_quorum_token"`
+	Token     string `gorm:"column:token;not null;size:64;uniqueIndex:idx_quorum_token;index"`
+}
+
+// TableName specifies the table name for QuorumToken
+func (QuorumToken) TableName() string {
+	return "quorum_tokens"
+}
+
+// replaceTokens rewrites the token rows used for selection filtering
+func replaceTokens(tx *gorm.DB, did string, tokens []string) error {
+	if err := tx.Where("quorum_did = ?", did).Delete(&QuorumToken{}).Error; err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	rows := make([]QuorumToken, 0, len(tokens))
+	for _, token := range tokens {
+		rows = append(rows, QuorumToken{QuorumDID: did, Token: token})
+	}
+	return tx.Create(&rows).Error
+}
+
+// migrateQuorumTokens fills quorum_tokens from the supported_tokens JSON of quorums
+// registered before the table existed. It only runs while the table is empty. Stored
+// names are upper-cased, trimmed and de-duplicated on the way, as registration now does,
+// so legacy rows such as ["rbt "] start matching token filters.
+func migrateQuorumTokens(db *gorm.DB) error {
+	var existing int64
+	if err := db.Model(&QuorumToken{}).Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	var migrated int
+	var quorums []QuorumDB
+	err := db.Select("id", "did", "supported_tokens").
+		Where("supported_tokens IS NOT NULL AND supported_tokens NOT IN ('', 'null', '[]')").
+		FindInBatches(&quorums, 500, func(tx *gorm.DB, _ int) error {
+			for _, q := range quorums {
+				var declared []string
+				if err := json.Unmarshal([]byte(q.SupportedTokens), &declared); err != nil {
+					log.Printf("⚠️  Skipping unreadable supported_tokens of %s: %v\n", q.DID, err)
+					continue
+				}
+
+				tokens := normalizeTokenList(declared)
+				if err := replaceTokens(tx, q.DID, tokens); err != nil {
+					return err
+				}
+				if !slices.Equal(tokens, declared) {
+					tokensJSON, _ := json.Marshal(tokens)
+					if err := tx.Model(&q).Update("supported_tokens", string(tokensJSON)).Error; err != nil {
+						return err
+					}
+				}
+				migrated++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return fmt.Errorf("failed to migrate supported tokens: %w", err)
+	}
+
+	if migrated > 0 {
+		log.Printf("🪙 Migrated supported tokens of %d quorums into quorum_tokens\n", migrated)
+	}
+	return nil
+}
+
+// normalizeTokenList upper-cases, trims and de-duplicates token names, dropping empty ones
+func normalizeTokenList(tokens []string) []string {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, token := range tokens {
+		token = strings.ToUpper(strings.TrimSpace(token))
+		if token != "" && !seen[token] {
+			seen[token] = true
+			normalized = append(normalized, token)
+		}
+	}
+	return normalized
+}
+
+// tokenAvailability counts the available quorums supporting each token, in namespace or
+// across every namespace when it is empty, with grouped counts over quorum_tokens
+func (ds *DBStore) tokenAvailability(namespace string) map[string]models.TokenAvailability {
+	available := func() *gorm.DB {
+		return ds.applyHeartbeatFilter(scopeNamespace(ds.db.Model(&QuorumDB{}), namespace).Where("available = ?", true))
+	}
+
+	var rows []struct {
+		Token string
+		Count int
+	}
+	available().
+		Joins("JOIN quorum_tokens ON quorum_tokens.quorum_did = quorums.did").
+		Select("quorum_tokens.token AS token, COUNT(*) AS count").
+		Group("quorum_tokens.token").
+		Scan(&rows)
+
+	declared := make(map[string]int, len(rows))
+	for _, row := range rows {
+		declared[row.Token] = row.Count
+	}
+
+	var undeclared int64
+	available().
+		Where("did NOT IN (?)", ds.db.Model(&QuorumToken{}).Select("quorum_did")).
+		Count(&undeclared)
+
+	return tokenAvailability(declared, int(undeclared), ds.config.EmptyTokensMeans)
+}
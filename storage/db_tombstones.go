@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+	"gorm.io/gorm"
+)
+
+// MaxSignatureSkew bounds how far a signed request's timestamp may be from server time
+const MaxSignatureSkew = 5 * time.Minute
+
+var (
+	// ErrInvalidSignature is returned when a signed quorum request fails verification
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	// ErrTombstoned is returned when re-registering a signed-off DID without a valid signature
+	ErrTombstoned = errors.New("DID was deregistered with a signed tombstone; re-registration must be signed with the same key")
+)
+
+// SignedMessage is the exact text a quorum signs for action ("unregister" or "register")
+func SignedMessage(action, did string, signedAt int64) string {
+	return fmt.Sprintf("%s:%s:%d", action, did, signedAt)
+}
+
+// verifySignature checks a base64 Ed25519 signature over SignedMessage(action, did, signedAt)
+// and that signedAt is within MaxSignatureSkew of now
+func verifySignature(publicKey, signature, action, did string, signedAt int64) error {
+	skew := time.Since(time.Unix(signedAt, 0))
+	if skew > MaxSignatureSkew || skew < -MaxSignatureSkew {
+		return fmt.Errorf("%w: signed_at is more than %s from server time", ErrInvalidSignature, MaxSignatureSkew)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("%w: public_key must be a base64-encoded Ed25519 key", ErrInvalidSignature)
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: signature must be base64-encoded", ErrInvalidSignature)
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(key), []byte(SignedMessage(action, did, signedAt)), sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// UnregisterQuorumSigned removes a quorum, soft-deleting it unless hard is set, and
+// records a tombstone holding the signing key, so the DID can only be registered again
+// with a signature from that key
+func (ds *DBStore) UnregisterQuorumSigned(did string, req *models.UnregisterSignature, hard bool) error {
+	if err := verifySignature(req.PublicKey, req.Signature, "unregister", did, req.SignedAt); err != nil {
+		return err
+	}
+
+	return ds.poolTransaction(func(tx *gorm.DB) error {
+		if err := unregisterQuorum(tx, did, hard); err != nil {
+			return err
+		}
+
+		// A later signed unregister replaces the previous tombstone
+		if err := tx.Where("did = ?", did).Delete(&QuorumTombstone{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&QuorumTombstone{
+			DID:       did,
+			PublicKey: req.PublicKey,
+			Signature: req.Signature,
+			SignedAt:  time.Unix(req.SignedAt, 0),
+		}).Error
+	})
+}
+
+// checkTombstone enforces a tombstone on registration within an open transaction: a
+// tombstoned DID needs a valid "register" signature from the tombstone's key, after
+// which the tombstone is cleared
+func checkTombstone(tx *gorm.DB, req *models.QuorumRegistrationRequest) error {
+	tombstone, err := verifyTombstone(tx, req)
+	if err != nil || tombstone == nil {
+		return err
+	}
+	return tx.Delete(tombstone).Error
+}
+
+// verifyTombstone returns the DID's tombstone when req carries a valid signature to lift
+// it, nil when the DID has none, and ErrTombstoned otherwise. It only reads.
+func verifyTombstone(db *gorm.DB, req *models.QuorumRegistrationRequest) (*QuorumTombstone, error) {
+	var tombstone QuorumTombstone
+	if err := db.Where("did = ?", req.DID).First(&tombstone).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if req.Signature == "" {
+		return nil, ErrTombstoned
+	}
+	if err := verifySignature(tombstone.PublicKey, req.Signature, "register", req.DID, req.SignedAt); err != nil {
+		return nil, fmt.Errorf("%w (%v)", ErrTombstoned, err)
+	}
+	return &tombstone, nil
+}
+
+// CheckRegistrationPolicy reports whether registration policy (currently the signed
+// tombstone check) would reject req, without writing anything
+func (ds *DBStore) CheckRegistrationPolicy(req *models.QuorumRegistrationRequest) error {
+	_, err := verifyTombstone(ds.db, req)
+	return err
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+// testKey generates an Ed25519 key pair, returning the base64 public key and a function
+// signing SignedMessage with the private key
+func testKey(t *testing.T) (string, func(action, did string, signedAt int64) string) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(action, did string, signedAt int64) string {
+		return base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(SignedMessage(action, did, signedAt))))
+	}
+	return base64.StdEncoding.EncodeToString(public), sign
+}
+
+func TestSignedTombstone(t *testing.T) {
+	ds := newTestStore(t, DBConfig{})
+	did := registerTestQuorum(t, ds, "1", 100)
+	publicKey, sign := testKey(t)
+	_, signWithOtherKey := testKey(t)
+	now := time.Now().Unix()
+
+	rejected := []struct {
+		name string
+		req  models.UnregisterSignature
+	}{
+		{"another key's signature", models.UnregisterSignature{PublicKey: publicKey, SignedAt: now, Signature: signWithOtherKey("unregister", did, now)}},
+		{"a register signature", models.UnregisterSignature{PublicKey: publicKey, SignedAt: now, Signature: sign("register", did, now)}},
+		{"an expired signature", models.UnregisterSignature{PublicKey: publicKey, SignedAt: now - 3600, Signature: sign("unregister", did, now-3600)}},
+	}
+	for _, tt := range rejected {
+		if err := ds.UnregisterQuorumSigned(did, &tt.req, false); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("unregister with %s: err = %v, want ErrInvalidSignature", tt.name, err)
+		}
+	}
+	if _, err := ds.GetQuorumByDID(did); err != nil {
+		t.Fatalf("rejected unregisters removed the quorum: %v", err)
+	}
+
+	err := ds.UnregisterQuorumSigned(did, &models.UnregisterSignature{
+		PublicKey: publicKey, SignedAt: now, Signature: sign("unregister", did, now),
+	}, false)
+	if err != nil {
+		t.Fatalf("UnregisterQuorumSigned: %v", err)
+	}
+	if _, err := ds.GetQuorumByDID(did); !errors.Is(err, ErrQuorumNotFound) {
+		t.Errorf("quorum after signed unregister: err = %v, want ErrQuorumNotFound", err)
+	}
+	var tombstone QuorumTombstone
+	if err := ds.db.Where("did = ?", did).First(&tombstone).Error; err != nil || tombstone.PublicKey != publicKey {
+		t.Fatalf("tombstone = %+v (%v), want one holding the signing key", tombstone, err)
+	}
+
+	reregister := func(signature string) error {
+		return ds.RegisterQuorum(&models.QuorumRegistrationRequest{
+			DID: did, PeerID: "peer1", Balance: 100, DIDType: 4, Signature: signature, SignedAt: now,
+		})
+	}
+	if err := reregister(""); !errors.Is(err, ErrTombstoned) {
+		t.Errorf("unsigned re-registration: err = %v, want ErrTombstoned", err)
+	}
+	if err := reregister(signWithOtherKey("register", did, now)); !errors.Is(err, ErrTombstoned) {
+		t.Errorf("re-registration signed by another key: err = %v, want ErrTombstoned", err)
+	}
+	if _, err := ds.GetQuorumByDID(did); !errors.Is(err, ErrQuorumNotFound) {
+		t.Fatalf("rejected re-registrations registered the quorum: %v", err)
+	}
+
+	// The tombstone's key lifts it
+	if err := reregister(sign("register", did, now)); err != nil {
+		t.Fatalf("signed re-registration: %v", err)
+	}
+	if _, err := ds.GetQuorumByDID(did); err != nil {
+		t.Errorf("quorum after signed re-registration: %v", err)
+	}
+	if err := ds.db.Where("did = ?", did).First(&QuorumTombstone{}).Error; err == nil {
+		t.Error("tombstone outlived the signed re-registration")
+	}
+}
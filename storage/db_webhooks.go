@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gklps/advisory-node/models"
+	"github.com/gklps/advisory-node/webhooks"
+)
+
+// ErrWebhookNotFound is returned when a webhook subscription ID does not exist
+var ErrWebhookNotFound = errors.New("webhook subscription not found")
+
+// RecordWebhookDeadLetter stores an undeliverable webhook event for later inspection
+func (ds *DBStore) RecordWebhookDeadLetter(url, eventType string, payload []byte, attempts int, lastError string) error {
+	return ds.db.Create(&WebhookDeadLetter{
+		URL:       url,
+		EventType: eventType,
+		Payload:   string(payload),
+		Attempts:  attempts,
+		LastError: lastError,
+	}).Error
+}
+
+// SetWebhookPublisher sets where cleanup publishes quorum_stale events. It is meant to
+// be called once at startup, before the cleanup routine runs; nil discards the events.
+func (ds *DBStore) SetWebhookPublisher(publisher *webhooks.Publisher) {
+	ds.publisher = publisher
+}
+
+// CreateWebhookSubscription subscribes url to events, which must already be validated
+func (ds *DBStore) CreateWebhookSubscription(url string, events []string) (*models.WebhookSubscription, error) {
+	sub := WebhookSubscription{URL: url, Events: strings.Join(events, ",")}
+	if err := ds.db.Create(&sub).Error; err != nil {
+		return nil, err
+	}
+	return toWebhookSubscription(sub), nil
+}
+
+// ListWebhookSubscriptions returns every subscription, oldest first
+func (ds *DBStore) ListWebhookSubscriptions() ([]models.WebhookSubscription, error) {
+	var subs []WebhookSubscription
+	if err := ds.db.Order("id").Find(&subs).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]models.WebhookSubscription, 0, len(subs))
+	for _, sub := range subs {
+		result = append(result, *toWebhookSubscription(sub))
+	}
+	return result, nil
+}
+
+// DeleteWebhookSubscription removes a subscription. Deliveries already queued for it
+// still go out.
+func (ds *DBStore) DeleteWebhookSubscription(id uint) error {
+	result := ds.db.Delete(&WebhookSubscription{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// WebhookSubscribers returns the URLs subscribed to eventType. Subscriptions are few,
+// so the event lists are matched here rather than in SQL.
+func (ds *DBStore) WebhookSubscribers(eventType string) ([]string, error) {
+	var subs []WebhookSubscription
+	if err := ds.db.Select("url", "events").Find(&subs).Error; err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	for _, sub := range subs {
+		for _, event := range strings.Split(sub.Events, ",") {
+			if event == eventType {
+				urls = append(urls, sub.URL)
+				break
+			}
+		}
+	}
+	return urls, nil
+}
+
+func toWebhookSubscription(sub WebhookSubscription) *models.WebhookSubscription {
+	return &models.WebhookSubscription{
+		ID:        sub.ID,
+		URL:       sub.URL,
+		Events:    strings.Split(sub.Events, ","),
+		CreatedAt: sub.CreatedAt,
+	}
+}
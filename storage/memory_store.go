@@ -1,7 +1,6 @@
 package storage
 
 import (
-	"errors"
 	"fmt"
 	"sort"
 	"sync"
@@ -16,15 +15,69 @@ type MemoryStore struct {
 	quorums   map[string]*models.QuorumInfo // Key: DID
 	peerIndex map[string]string             // Key: PeerID, Value: DID
 	startTime time.Time
+
+	emptyTokens string        // Tokens supported by quorums declaring none (see SetEmptyTokensMeans)
+	precision   int           // Decimal places required balances are rounded up to (see SetBalancePrecision)
+	window      time.Duration // How recently a quorum must have pinged to be selectable (see SetAvailabilityWindow)
 }
 
 // NewMemoryStore creates a new in-memory storage instance
 func NewMemoryStore() *MemoryStore {
 	return &MemoryStore{
-		quorums:   make(map[string]*models.QuorumInfo),
-		peerIndex: make(map[string]string),
-		startTime: time.Now(),
+		quorums:     make(map[string]*models.QuorumInfo),
+		peerIndex:   make(map[string]string),
+		startTime:   time.Now(),
+		emptyTokens: EmptyTokensRBTOnly,
+		precision:   DefaultBalancePrecision,
+		window:      DefaultAvailabilityWindow,
+	}
+}
+
+// SetEmptyTokensMeans sets which tokens quorums declaring none are treated as
+// supporting: EmptyTokensRBTOnly (the default), EmptyTokensAll or EmptyTokensNone
+func (ms *MemoryStore) SetEmptyTokensMeans(policy string) error {
+	if err := ValidateEmptyTokensMeans(policy); err != nil {
+		return err
 	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.emptyTokens = policy
+	return nil
+}
+
+// SetBalancePrecision sets the number of decimal places required balances are rounded
+// up to (DefaultBalancePrecision unless set)
+func (ms *MemoryStore) SetBalancePrecision(places int) error {
+	if err := ValidateBalancePrecision(places); err != nil {
+		return err
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.precision = places
+	return nil
+}
+
+// SetAvailabilityWindow sets how recently a quorum must have pinged to be selectable
+// (DefaultAvailabilityWindow unless set)
+func (ms *MemoryStore) SetAvailabilityWindow(window time.Duration) error {
+	if err := ValidateAvailabilityWindow(window); err != nil {
+		return err
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.window = window
+	return nil
+}
+
+// RequiredBalance is each of count quorums' share of amount, rounded up to the balance
+// precision
+func (ms *MemoryStore) RequiredBalance(amount float64, count int) float64 {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return RequiredBalanceShare(amount, count, ms.precision)
 }
 
 // RegisterQuorum registers a new quorum or updates an existing one
@@ -34,13 +87,23 @@ func (ms *MemoryStore) RegisterQuorum(req *models.QuorumRegistrationRequest) err
 
 	// Check if quorum already exists
 	if existing, ok := ms.quorums[req.DID]; ok {
-		// Update existing quorum
+		// Update existing quorum, dropping its old peer's index entry if the peer changed
+		if existing.PeerID != req.PeerID && ms.peerIndex[existing.PeerID] == req.DID {
+			delete(ms.peerIndex, existing.PeerID)
+		}
 		existing.PeerID = req.PeerID
 		existing.Balance = req.Balance
 		existing.DIDType = req.DIDType
 		existing.LastPing = time.Now()
 		existing.Available = true
 		existing.SupportedTokens = req.SupportedTokens
+		existing.Capabilities = req.Capabilities
+		existing.Region = req.Region
+		existing.Tier = req.Tier
+		existing.Namespace = req.Namespace
+		existing.Metadata = req.Metadata
+		existing.MinParticipationBalance = req.MinParticipationBalance
+		existing.MaxTransactionShare = req.MaxTransactionShare
 
 		// Update peer index
 		ms.peerIndex[req.PeerID] = req.DID
@@ -58,6 +121,14 @@ func (ms *MemoryStore) RegisterQuorum(req *models.QuorumRegistrationRequest) err
 		AssignmentCount:  0,
 		RegistrationTime: time.Now(),
 		SupportedTokens:  req.SupportedTokens,
+		Capabilities:     req.Capabilities,
+		Region:           req.Region,
+		Tier:             req.Tier,
+		Namespace:        req.Namespace,
+		Metadata:         req.Metadata,
+
+		MinParticipationBalance: req.MinParticipationBalance,
+		MaxTransactionShare:     req.MaxTransactionShare,
 	}
 
 	ms.quorums[req.DID] = quorum
@@ -73,7 +144,7 @@ func (ms *MemoryStore) ConfirmAvailability(did string) error {
 
 	quorum, ok := ms.quorums[did]
 	if !ok {
-		return errors.New("quorum not found")
+		return ErrQuorumNotFound
 	}
 
 	quorum.Available = true
@@ -82,8 +153,9 @@ func (ms *MemoryStore) ConfirmAvailability(did string) error {
 	return nil
 }
 
-// GetAvailableQuorums returns available quorums with load balancing and token filtering
-func (ms *MemoryStore) GetAvailableQuorums(count int, lastCharTID string, transactionAmount float64, ftName string) ([]models.QuorumData, error) {
+// GetAvailableQuorums returns available quorums with load balancing and token filtering,
+// restricted to didType when it is non-nil
+func (ms *MemoryStore) GetAvailableQuorums(count int, lastCharTID string, transactionAmount float64, ftName string, didType *int) ([]models.QuorumData, error) {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
 
@@ -92,13 +164,12 @@ func (ms *MemoryStore) GetAvailableQuorums(count int, lastCharTID string, transa
 	}
 
 	// Calculate required balance (transaction amount divided by number of quorums)
-	requiredBalance := transactionAmount / float64(count)
+	requiredBalance := RequiredBalanceShare(transactionAmount, count, ms.precision)
 
 	// Helper function to check if quorum supports a token
 	supportsToken := func(supportedTokens []string, token string) bool {
 		if len(supportedTokens) == 0 {
-			// If no tokens specified, assume it supports RBT (default)
-			return token == "" || token == "RBT"
+			return emptyTokensSupport(ms.emptyTokens, token)
 		}
 		for _, t := range supportedTokens {
 			if t == token {
@@ -111,10 +182,16 @@ func (ms *MemoryStore) GetAvailableQuorums(count int, lastCharTID string, transa
 	// Filter available quorums
 	var availableQuorums []*models.QuorumInfo
 	for _, q := range ms.quorums {
-		// Check if quorum is available and was pinged recently (within last 5 minutes)
-		if q.Available && time.Since(q.LastPing) < 5*time.Minute && q.Balance >= requiredBalance {
-			// Check token support
-			if ftName != "" && !supportsToken(q.SupportedTokens, ftName) {
+		// Check if quorum is available and was pinged within the availability window
+		if q.Available && time.Since(q.LastPing) < ms.window && hasSpareBalance(q.Balance-q.MinParticipationBalance, requiredBalance, ms.precision) &&
+			(q.MaxTransactionShare == 0 || q.MaxTransactionShare >= requiredBalance) {
+			// Check token support; quorums without a token list are subject to the
+			// empty-tokens policy even for default RBT requests
+			if (ftName != "" || len(q.SupportedTokens) == 0) && !supportsToken(q.SupportedTokens, ftName) {
+				continue
+			}
+
+			if didType != nil && q.DIDType != *didType {
 				continue
 			}
 
@@ -170,23 +247,27 @@ func (ms *MemoryStore) GetAvailableQuorums(count int, lastCharTID string, transa
 	return result, nil
 }
 
-// UnregisterQuorum removes a quorum from the pool
-func (ms *MemoryStore) UnregisterQuorum(did string) error {
+// UnregisterQuorum removes a quorum from the pool. Nothing is kept of removed quorums,
+// so hard makes no difference.
+func (ms *MemoryStore) UnregisterQuorum(did string, hard bool) error {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
 
-	quorum, ok := ms.quorums[did]
-	if !ok {
-		return errors.New("quorum not found")
+	if _, ok := ms.quorums[did]; !ok {
+		return ErrQuorumNotFound
 	}
 
-	// Remove from peer index
-	delete(ms.peerIndex, quorum.PeerID)
+	ms.remove(did)
+	return nil
+}
 
-	// Remove from quorums map
+// remove deletes a quorum and its peer index entry, unless the peer has registered
+// another DID since. The caller holds the write lock.
+func (ms *MemoryStore) remove(did string) {
+	if quorum, ok := ms.quorums[did]; ok && ms.peerIndex[quorum.PeerID] == did {
+		delete(ms.peerIndex, quorum.PeerID)
+	}
 	delete(ms.quorums, did)
-
-	return nil
 }
 
 // GetHealthStatus returns the health status of the storage
@@ -196,10 +277,18 @@ func (ms *MemoryStore) GetHealthStatus() models.HealthStatus {
 
 	totalQuorums := len(ms.quorums)
 	availableQuorums := 0
+	declared := make(map[string]int)
+	undeclared := 0
 
 	for _, q := range ms.quorums {
-		if q.Available && time.Since(q.LastPing) < 5*time.Minute {
+		if q.Available && time.Since(q.LastPing) < ms.window {
 			availableQuorums++
+			if len(q.SupportedTokens) == 0 {
+				undeclared++
+			}
+			for _, token := range q.SupportedTokens {
+				declared[token]++
+			}
 		}
 	}
 
@@ -207,6 +296,7 @@ func (ms *MemoryStore) GetHealthStatus() models.HealthStatus {
 		Status:           "healthy",
 		TotalQuorums:     totalQuorums,
 		AvailableQuorums: availableQuorums,
+		Tokens:           tokenAvailability(declared, undeclared, ms.emptyTokens),
 		Uptime:           time.Since(ms.startTime).String(),
 		LastCheck:        time.Now(),
 	}
@@ -219,7 +309,7 @@ func (ms *MemoryStore) UpdateHeartbeat(did string) error {
 
 	quorum, ok := ms.quorums[did]
 	if !ok {
-		return errors.New("quorum not found")
+		return ErrQuorumNotFound
 	}
 
 	quorum.LastPing = time.Now()
@@ -227,22 +317,20 @@ func (ms *MemoryStore) UpdateHeartbeat(did string) error {
 }
 
 // CleanupStaleQuorums removes quorums that haven't pinged in a while
-func (ms *MemoryStore) CleanupStaleQuorums() int {
+func (ms *MemoryStore) CleanupStaleQuorums() (int, error) {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
 
-	staleThreshold := 10 * time.Minute
 	removedCount := 0
 
 	for did, q := range ms.quorums {
-		if time.Since(q.LastPing) > staleThreshold {
-			delete(ms.peerIndex, q.PeerID)
-			delete(ms.quorums, did)
+		if time.Since(q.LastPing) > quorumRemovalAge {
+			ms.remove(did)
 			removedCount++
 		}
 	}
 
-	return removedCount
+	return removedCount, nil
 }
 
 // GetQuorumByDID returns a specific quorum by DID
@@ -252,8 +340,38 @@ func (ms *MemoryStore) GetQuorumByDID(did string) (*models.QuorumInfo, error) {
 
 	quorum, ok := ms.quorums[did]
 	if !ok {
-		return nil, errors.New("quorum not found")
+		return nil, ErrQuorumNotFound
+	}
+
+	info := *quorum
+	info.NextHeartbeatDeadline = quorum.LastPing.Add(ms.window)
+	return &info, nil
+}
+
+// GetPeerDIDs groups registered DIDs by peer ID, for debugging peers hosting several DIDs
+func (ms *MemoryStore) GetPeerDIDs() (map[string][]string, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	peers := make(map[string][]string)
+	for did, q := range ms.quorums {
+		peers[q.PeerID] = append(peers[q.PeerID], did)
 	}
+	for _, dids := range peers {
+		sort.Strings(dids)
+	}
+	return peers, nil
+}
+
+// PeerIndex returns a copy of the PeerID -> DID index, for debugging. A peer that
+// registered several DIDs maps to the most recently registered one.
+func (ms *MemoryStore) PeerIndex() map[string]string {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
 
-	return quorum, nil
+	index := make(map[string]string, len(ms.peerIndex))
+	for peerID, did := range ms.peerIndex {
+		index[peerID] = did
+	}
+	return index
 }
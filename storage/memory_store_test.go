@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+func TestMemoryStorePeerIndex(t *testing.T) {
+	ms := NewMemoryStore()
+	register := func(suffix, peerID string) string {
+		t.Helper()
+		did := testDID(suffix)
+		if err := ms.RegisterQuorum(&models.QuorumRegistrationRequest{DID: did, PeerID: peerID, Balance: 100, DIDType: 4}); err != nil {
+			t.Fatalf("RegisterQuorum(%s): %v", suffix, err)
+		}
+		return did
+	}
+	check := func(step string, want map[string]string) {
+		t.Helper()
+		if got := ms.PeerIndex(); !maps.Equal(got, want) {
+			t.Errorf("%s: peer index = %v, want %v", step, got, want)
+		}
+	}
+
+	first := register("1", "peerA")
+	second := register("2", "peerB")
+	check("after registering", map[string]string{"peerA": first, "peerB": second})
+
+	// A quorum moving to a new peer leaves no entry behind for the old one
+	register("1", "peerC")
+	check("after the peer change", map[string]string{"peerB": second, "peerC": first})
+
+	// One peer, many DIDs: the index follows the latest registration
+	third := register("3", "peerB")
+	check("after sharing a peer", map[string]string{"peerB": third, "peerC": first})
+
+	// Unregistering the DID the index no longer points at leaves the entry alone
+	if err := ms.UnregisterQuorum(second, false); err != nil {
+		t.Fatal(err)
+	}
+	check("after unregistering the older DID", map[string]string{"peerB": third, "peerC": first})
+	if err := ms.UnregisterQuorum(third, false); err != nil {
+		t.Fatal(err)
+	}
+	check("after unregistering the indexed DID", map[string]string{"peerC": first})
+
+	// Callers get a copy
+	ms.PeerIndex()["peerZ"] = first
+	check("after mutating a returned index", map[string]string{"peerC": first})
+}
@@ -0,0 +1,542 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keys. Each quorum is a hash under redisQuorumPrefix+DID; assignment counts and
+// last pings are kept in sorted sets so selection can walk quorums least-assigned first
+// and cleanup can find stale ones without scanning every record.
+const (
+	redisQuorumPrefix = "advisory:quorum:"
+	redisLoadKey      = "advisory:load"  // DID -> assignment count
+	redisPingsKey     = "advisory:pings" // DID -> last ping, unix milliseconds
+)
+
+// redisSelectScript picks and assigns quorums in one atomic step, so replicas sharing the
+// pool never hand out the same least-assigned quorums twice. It walks the load set in
+// ascending assignment count, stopping once every quorum that could be picked has been
+// seen, and returns the found count instead when there are not enough.
+var redisSelectScript = redis.NewScript(`
+local count = tonumber(ARGV[1])
+local required = tonumber(ARGV[2])
+local ftName = ARGV[3]
+local didType = ARGV[4]
+local lastChar = ARGV[5]
+local now = tonumber(ARGV[6])
+local window = tonumber(ARGV[7])
+local emptySupported = ARGV[8] == '1'
+local prefix = ARGV[9]
+local tolerance = tonumber(ARGV[10])
+
+local function supports(tokens)
+	if #tokens == 0 then
+		return emptySupported
+	end
+	for _, t in ipairs(tokens) do
+		if t == ftName then
+			return true
+		end
+	end
+	return false
+end
+
+local candidates = {}
+local loads = redis.call('ZRANGE', KEYS[1], 0, -1, 'WITHSCORES')
+for i = 1, #loads, 2 do
+	local did = loads[i]
+	local assigned = tonumber(loads[i + 1])
+	if ftName ~= 'TRI' and #candidates >= count and assigned > candidates[count].assigned then
+		break
+	end
+
+	local q = redis.call('HMGET', prefix .. did, 'available', 'balance', 'min_participation_balance',
+		'max_transaction_share', 'did_type', 'supported_tokens', 'last_assignment', 'peer_id')
+	local ping = tonumber(redis.call('ZSCORE', KEYS[2], did) or '0')
+	local share = tonumber(q[4])
+	local eligible = q[1] == '1' and now - ping < window and
+		tonumber(q[2]) - tonumber(q[3]) >= required - tolerance and (share == 0 or share >= required)
+
+	if eligible then
+		local tokens = cjson.decode(q[6])
+		if type(tokens) ~= 'table' then
+			tokens = {}
+		end
+		if (ftName ~= '' or #tokens == 0) and not supports(tokens) then
+			eligible = false
+		elseif didType ~= '' and q[5] ~= didType then
+			eligible = false
+		elseif lastChar ~= '' and ftName ~= 'TRI' and string.sub(did, -1) ~= lastChar then
+			eligible = false
+		end
+	end
+
+	if eligible then
+		table.insert(candidates, {did = did, assigned = assigned, last = tonumber(q[7]), peer = q[8]})
+	end
+end
+
+if #candidates < count then
+	return #candidates
+end
+
+if ftName == 'TRI' then
+	table.sort(candidates, function(a, b) return a.did < b.did end)
+else
+	table.sort(candidates, function(a, b)
+		if a.assigned ~= b.assigned then
+			return a.assigned < b.assigned
+		end
+		if a.last ~= b.last then
+			return a.last < b.last
+		end
+		return a.did < b.did
+	end)
+end
+
+local addresses = {}
+for i = 1, count do
+	local q = candidates[i]
+	redis.call('ZINCRBY', KEYS[1], 1, q.did)
+	redis.call('HSET', prefix .. q.did, 'last_assignment', ARGV[6])
+	table.insert(addresses, q.peer .. '.' .. q.did)
+end
+return addresses
+`)
+
+// redisTouchScript records a ping for an existing quorum, also marking it available when
+// ARGV[3] is '1'. It returns 0 when the quorum isn't registered.
+var redisTouchScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+if ARGV[3] == '1' then
+	redis.call('HSET', KEYS[1], 'available', '1')
+end
+redis.call('ZADD', KEYS[2], ARGV[1], ARGV[2])
+return 1
+`)
+
+// redisRemoveScript deletes a quorum and its index entries. With a cutoff in ARGV[2] it
+// only does so if the quorum hasn't pinged since, so cleanup can't remove a quorum that
+// heartbeated in the meantime. It returns 0 when nothing was removed.
+var redisRemoveScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+if ARGV[2] ~= '' then
+	local ping = redis.call('ZSCORE', KEYS[3], ARGV[1])
+	if ping and tonumber(ping) > tonumber(ARGV[2]) then
+		return 0
+	end
+end
+redis.call('DEL', KEYS[1])
+redis.call('ZREM', KEYS[2], ARGV[1])
+redis.call('ZREM', KEYS[3], ARGV[1])
+return 1
+`)
+
+// RedisStore keeps the quorum pool in Redis, so several replicas behind a load balancer
+// share one pool and one set of assignment counts. Selection runs as a Lua script, which
+// makes picking and counting assignments atomic across replicas. Scripts address quorum
+// records by computed keys, so a single Redis server (or primary) is required rather
+// than Redis Cluster.
+type RedisStore struct {
+	client    *redis.Client
+	startTime time.Time
+
+	emptyTokens string        // Tokens supported by quorums declaring none (see SetEmptyTokensMeans)
+	precision   int           // Decimal places required balances are rounded up to (see SetBalancePrecision)
+	window      time.Duration // How recently a quorum must have pinged to be selectable (see SetAvailabilityWindow)
+}
+
+// NewRedisStore connects to the Redis server at url, e.g. redis://:password@host:6379/0
+func NewRedisStore(url string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return &RedisStore{
+		client:      client,
+		startTime:   time.Now(),
+		emptyTokens: EmptyTokensRBTOnly,
+		precision:   DefaultBalancePrecision,
+		window:      DefaultAvailabilityWindow,
+	}, nil
+}
+
+// Close closes the connection to Redis
+func (rs *RedisStore) Close() error {
+	return rs.client.Close()
+}
+
+// SetEmptyTokensMeans sets which tokens quorums declaring none are treated as
+// supporting: EmptyTokensRBTOnly (the default), EmptyTokensAll or EmptyTokensNone
+func (rs *RedisStore) SetEmptyTokensMeans(policy string) error {
+	if err := ValidateEmptyTokensMeans(policy); err != nil {
+		return err
+	}
+	rs.emptyTokens = policy
+	return nil
+}
+
+// SetBalancePrecision sets the number of decimal places required balances are rounded
+// up to (DefaultBalancePrecision unless set)
+func (rs *RedisStore) SetBalancePrecision(places int) error {
+	if err := ValidateBalancePrecision(places); err != nil {
+		return err
+	}
+	rs.precision = places
+	return nil
+}
+
+// SetAvailabilityWindow sets how recently a quorum must have pinged to be selectable
+// (DefaultAvailabilityWindow unless set)
+func (rs *RedisStore) SetAvailabilityWindow(window time.Duration) error {
+	if err := ValidateAvailabilityWindow(window); err != nil {
+		return err
+	}
+	rs.window = window
+	return nil
+}
+
+// RequiredBalance is each of count quorums' share of amount, rounded up to the balance
+// precision
+func (rs *RedisStore) RequiredBalance(amount float64, count int) float64 {
+	return RequiredBalanceShare(amount, count, rs.precision)
+}
+
+// RegisterQuorum registers a new quorum or updates an existing one, keeping its
+// assignment count and registration time
+func (rs *RedisStore) RegisterQuorum(req *models.QuorumRegistrationRequest) error {
+	supportedTokens, err := json.Marshal(req.SupportedTokens)
+	if err != nil {
+		return err
+	}
+	capabilities, err := json.Marshal(req.Capabilities)
+	if err != nil {
+		return err
+	}
+	metadata, err := json.Marshal(req.Metadata)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	key := redisQuorumPrefix + req.DID
+	now := time.Now().UnixMilli()
+
+	_, err = rs.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			"did", req.DID,
+			"peer_id", req.PeerID,
+			"balance", formatFloat(req.Balance),
+			"did_type", strconv.Itoa(req.DIDType),
+			"available", "1",
+			"supported_tokens", string(supportedTokens),
+			"capabilities", string(capabilities),
+			"region", req.Region,
+			"tier", req.Tier,
+			"namespace", req.Namespace,
+			"metadata", string(metadata),
+			"min_participation_balance", formatFloat(req.MinParticipationBalance),
+			"max_transaction_share", formatFloat(req.MaxTransactionShare),
+		)
+		pipe.HSetNX(ctx, key, "registration_time", now)
+		pipe.HSetNX(ctx, key, "last_assignment", 0)
+		pipe.ZAddNX(ctx, redisLoadKey, redis.Z{Score: 0, Member: req.DID})
+		pipe.ZAdd(ctx, redisPingsKey, redis.Z{Score: float64(now), Member: req.DID})
+		return nil
+	})
+	return err
+}
+
+// ConfirmAvailability confirms that a quorum is available for assignments
+func (rs *RedisStore) ConfirmAvailability(did string) error {
+	return rs.touch(did, true)
+}
+
+// UpdateHeartbeat updates the last ping time for a quorum
+func (rs *RedisStore) UpdateHeartbeat(did string) error {
+	return rs.touch(did, false)
+}
+
+func (rs *RedisStore) touch(did string, available bool) error {
+	markAvailable := "0"
+	if available {
+		markAvailable = "1"
+	}
+
+	found, err := redisTouchScript.Run(context.Background(), rs.client,
+		[]string{redisQuorumPrefix + did, redisPingsKey},
+		time.Now().UnixMilli(), did, markAvailable,
+	).Int()
+	if err != nil {
+		return err
+	}
+	if found == 0 {
+		return ErrQuorumNotFound
+	}
+	return nil
+}
+
+// GetAvailableQuorums returns available quorums with load balancing and token filtering,
+// restricted to didType when it is non-nil. It selects as MemoryStore does, with the
+// assignment counts shared by every replica.
+func (rs *RedisStore) GetAvailableQuorums(count int, lastCharTID string, transactionAmount float64, ftName string, didType *int) ([]models.QuorumData, error) {
+	if count <= 0 {
+		count = 7 // Default to 7 quorums as per RubixGo requirement
+	}
+
+	// Calculate required balance (transaction amount divided by number of quorums)
+	requiredBalance := RequiredBalanceShare(transactionAmount, count, rs.precision)
+
+	didTypeFilter := ""
+	if didType != nil {
+		didTypeFilter = strconv.Itoa(*didType)
+	}
+	emptySupported := "0"
+	if emptyTokensSupport(rs.emptyTokens, ftName) {
+		emptySupported = "1"
+	}
+
+	result, err := redisSelectScript.Run(context.Background(), rs.client,
+		[]string{redisLoadKey, redisPingsKey},
+		count, formatFloat(requiredBalance), ftName, didTypeFilter, lastCharTID,
+		time.Now().UnixMilli(), rs.window.Milliseconds(), emptySupported, redisQuorumPrefix,
+		formatFloat(balanceTolerance(rs.precision)),
+	).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to select quorums: %w", err)
+	}
+
+	addresses, ok := result.([]interface{})
+	if !ok {
+		found, _ := result.(int64)
+		return nil, fmt.Errorf("not enough available quorums with required balance. Found %d, need %d (required balance: %.4f)",
+			found, count, requiredBalance)
+	}
+
+	quorums := make([]models.QuorumData, 0, len(addresses))
+	for _, address := range addresses {
+		quorums = append(quorums, models.QuorumData{
+			Type:    2, // Type 2 for private subnet quorums
+			Address: fmt.Sprint(address),
+		})
+	}
+	return quorums, nil
+}
+
+// UnregisterQuorum removes a quorum from the pool. Nothing is kept of removed quorums,
+// so hard makes no difference.
+func (rs *RedisStore) UnregisterQuorum(did string, hard bool) error {
+	removed, err := rs.remove(did, "")
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrQuorumNotFound
+	}
+	return nil
+}
+
+// remove deletes a quorum, unless cutoff is set and it has pinged since
+func (rs *RedisStore) remove(did, cutoff string) (bool, error) {
+	removed, err := redisRemoveScript.Run(context.Background(), rs.client,
+		[]string{redisQuorumPrefix + did, redisLoadKey, redisPingsKey},
+		did, cutoff,
+	).Int()
+	return removed == 1, err
+}
+
+// GetHealthStatus returns the health status of the storage, reporting "unhealthy" when
+// Redis can't be reached
+func (rs *RedisStore) GetHealthStatus() models.HealthStatus {
+	health := models.HealthStatus{
+		Status:    "healthy",
+		Uptime:    time.Since(rs.startTime).String(),
+		LastCheck: time.Now(),
+	}
+
+	ctx := context.Background()
+	total, err := rs.client.ZCard(ctx, redisLoadKey).Result()
+	if err != nil {
+		health.Status = "unhealthy"
+		return health
+	}
+	health.TotalQuorums = int(total)
+
+	since := time.Now().Add(-rs.window).UnixMilli()
+	recent, err := rs.client.ZRangeByScore(ctx, redisPingsKey, &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(since, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		health.Status = "unhealthy"
+		return health
+	}
+
+	pipe := rs.client.Pipeline()
+	records := make([]*redis.SliceCmd, len(recent))
+	for i, did := range recent {
+		records[i] = pipe.HMGet(ctx, redisQuorumPrefix+did, "available", "supported_tokens")
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		health.Status = "unhealthy"
+		return health
+	}
+
+	declared := make(map[string]int)
+	undeclared := 0
+	for _, record := range records {
+		fields := record.Val()
+		if fields[0] != "1" {
+			continue
+		}
+		health.AvailableQuorums++
+
+		var tokens []string
+		if value, ok := fields[1].(string); ok {
+			json.Unmarshal([]byte(value), &tokens)
+		}
+		if len(tokens) == 0 {
+			undeclared++
+		}
+		for _, token := range tokens {
+			declared[token]++
+		}
+	}
+	health.Tokens = tokenAvailability(declared, undeclared, rs.emptyTokens)
+	return health
+}
+
+// CleanupStaleQuorums removes quorums that haven't pinged in a while. Replicas may run it
+// concurrently; each stale quorum is removed once.
+func (rs *RedisStore) CleanupStaleQuorums() (int, error) {
+	cutoff := strconv.FormatInt(time.Now().Add(-quorumRemovalAge).UnixMilli(), 10)
+	stale, err := rs.client.ZRangeByScore(context.Background(), redisPingsKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: cutoff,
+	}).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	removedCount := 0
+	for _, did := range stale {
+		removed, err := rs.remove(did, cutoff)
+		if err != nil {
+			return removedCount, err
+		}
+		if removed {
+			removedCount++
+		}
+	}
+	return removedCount, nil
+}
+
+// GetQuorumByDID returns a specific quorum by DID
+func (rs *RedisStore) GetQuorumByDID(did string) (*models.QuorumInfo, error) {
+	ctx := context.Background()
+	pipe := rs.client.Pipeline()
+	record := pipe.HGetAll(ctx, redisQuorumPrefix+did)
+	assigned := pipe.ZScore(ctx, redisLoadKey, did)
+	ping := pipe.ZScore(ctx, redisPingsKey, did)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	fields := record.Val()
+	if len(fields) == 0 {
+		return nil, ErrQuorumNotFound
+	}
+
+	info := &models.QuorumInfo{
+		DID:              did,
+		PeerID:           fields["peer_id"],
+		Region:           fields["region"],
+		Tier:             fields["tier"],
+		Namespace:        fields["namespace"],
+		Available:        fields["available"] == "1",
+		AssignmentCount:  int(assigned.Val()),
+		LastPing:         time.UnixMilli(int64(ping.Val())),
+		LastAssignment:   parseMillis(fields["last_assignment"]),
+		RegistrationTime: parseMillis(fields["registration_time"]),
+	}
+	info.Balance, _ = strconv.ParseFloat(fields["balance"], 64)
+	info.DIDType, _ = strconv.Atoi(fields["did_type"])
+	info.MinParticipationBalance, _ = strconv.ParseFloat(fields["min_participation_balance"], 64)
+	info.MaxTransactionShare, _ = strconv.ParseFloat(fields["max_transaction_share"], 64)
+	for field, dest := range map[string]interface{}{
+		"supported_tokens": &info.SupportedTokens,
+		"capabilities":     &info.Capabilities,
+		"metadata":         &info.Metadata,
+	} {
+		if err := json.Unmarshal([]byte(fields[field]), dest); err != nil {
+			return nil, fmt.Errorf("corrupt %s for quorum %s: %w", field, did, err)
+		}
+	}
+
+	info.NextHeartbeatDeadline = info.LastPing.Add(rs.window)
+	return info, nil
+}
+
+// GetPeerDIDs groups registered DIDs by peer ID, for debugging peers hosting several DIDs
+func (rs *RedisStore) GetPeerDIDs() (map[string][]string, error) {
+	ctx := context.Background()
+	dids, err := rs.client.ZRange(ctx, redisLoadKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := rs.client.Pipeline()
+	peerIDs := make([]*redis.StringCmd, len(dids))
+	for i, did := range dids {
+		peerIDs[i] = pipe.HGet(ctx, redisQuorumPrefix+did, "peer_id")
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	peers := make(map[string][]string)
+	for i, did := range dids {
+		// Unregistered since the load set was read
+		if peerID, err := peerIDs[i].Result(); err == nil {
+			peers[peerID] = append(peers[peerID], did)
+		}
+	}
+	for _, dids := range peers {
+		sort.Strings(dids)
+	}
+	return peers, nil
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// parseMillis parses a unix millisecond timestamp, where 0 or a missing value is the zero time
+func parseMillis(value string) time.Time {
+	millis, _ := strconv.ParseInt(value, 10, 64)
+	if millis == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis)
+}
@@ -0,0 +1,295 @@
+package storage
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gklps/advisory-node/models"
+)
+
+// newTestRedisStore opens a RedisStore on mr; several stores on one server stand in for
+// replicas sharing a pool
+func newTestRedisStore(t *testing.T, mr *miniredis.Miniredis) *RedisStore {
+	t.Helper()
+	rs, err := NewRedisStore("redis://" + mr.Addr())
+	if err != nil {
+		t.Fatalf("NewRedisStore: %v", err)
+	}
+	t.Cleanup(func() { rs.Close() })
+	return rs
+}
+
+// setRedisPing backdates a quorum's last ping by age
+func setRedisPing(t *testing.T, mr *miniredis.Miniredis, did string, age time.Duration) {
+	t.Helper()
+	if _, err := mr.ZAdd(redisPingsKey, float64(time.Now().Add(-age).UnixMilli()), did); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// redisIndexed reports whether did has a quorum hash, an assignment count and a last ping
+func redisIndexed(mr *miniredis.Miniredis, did string) (record, load, ping bool) {
+	loads, _ := mr.ZMembers(redisLoadKey)
+	pings, _ := mr.ZMembers(redisPingsKey)
+	return mr.Exists(redisQuorumPrefix + did), slices.Contains(loads, did), slices.Contains(pings, did)
+}
+
+func TestRedisConcurrentSelectionSharesAssignments(t *testing.T) {
+	mr := miniredis.RunT(t)
+	replicas := []*RedisStore{newTestRedisStore(t, mr), newTestRedisStore(t, mr)}
+	var pool []string
+	for i := 1; i <= 6; i++ {
+		pool = append(pool, registerTestQuorum(t, replicas[0], strconv.Itoa(i), 100))
+	}
+
+	const selections, count = 10, 3
+	var wg sync.WaitGroup
+	errs := make(chan error, len(replicas)*selections)
+	for _, rs := range replicas {
+		wg.Add(1)
+		go func(rs *RedisStore) {
+			defer wg.Done()
+			for i := 0; i < selections; i++ {
+				quorums, err := rs.GetAvailableQuorums(count, "", 30, "", nil)
+				if err != nil {
+					errs <- err
+					continue
+				}
+				dids := quorumDIDs(quorums)
+				slices.Sort(dids)
+				if len(dids) != count || len(slices.Compact(dids)) != count {
+					errs <- errors.New("selection did not return count distinct quorums: " + strconv.Itoa(len(dids)))
+				}
+			}
+		}(rs)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	// Selection is atomic and least-assigned first, so the 60 assignments the two
+	// replicas made spread evenly instead of both handing out the same quorums
+	want := len(replicas) * selections * count / len(pool)
+	for _, did := range pool {
+		quorum, err := replicas[1].GetQuorumByDID(did)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if quorum.AssignmentCount != want {
+			t.Errorf("quorum %s assigned %d times, want %d", did, quorum.AssignmentCount, want)
+		}
+	}
+}
+
+func TestRedisSelectionFiltersTokensAndDIDType(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rs := newTestRedisStore(t, mr)
+	both := registerTestQuorum(t, rs, "1", 100, func(req *models.QuorumRegistrationRequest) {
+		req.SupportedTokens = []string{"RBT", "TRI"}
+	})
+	rbt := registerTestQuorum(t, rs, "2", 100, func(req *models.QuorumRegistrationRequest) {
+		req.SupportedTokens = []string{"RBT"}
+	})
+	undeclared := registerTestQuorum(t, rs, "3", 100)
+	typeZero := registerTestQuorum(t, rs, "4", 100, func(req *models.QuorumRegistrationRequest) {
+		req.SupportedTokens = []string{"RBT"}
+		req.DIDType = 0
+	})
+
+	selected := func(count int, ftName string, didType *int) ([]string, error) {
+		t.Helper()
+		quorums, err := rs.GetAvailableQuorums(count, "", 0, ftName, didType)
+		if err != nil {
+			return nil, err
+		}
+		dids := quorumDIDs(quorums)
+		slices.Sort(dids)
+		return dids, nil
+	}
+
+	if dids, err := selected(1, "TRI", nil); err != nil || !slices.Equal(dids, []string{both}) {
+		t.Errorf("TRI selection = %v (%v), want only %s", dids, err, both)
+	}
+	if _, err := selected(2, "TRI", nil); err == nil {
+		t.Error("selected two TRI quorums from a pool with one")
+	}
+	if dids, err := selected(4, "RBT", nil); err != nil || !slices.Equal(dids, []string{both, rbt, undeclared, typeZero}) {
+		t.Errorf("RBT selection = %v (%v), want every quorum under rbt-only", dids, err)
+	}
+
+	// Under the none policy the undeclared quorum drops out, even for RBT
+	if err := rs.SetEmptyTokensMeans(EmptyTokensNone); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := selected(4, "RBT", nil); err == nil {
+		t.Error("selected the undeclared quorum with empty tokens meaning none")
+	}
+
+	didType := 0
+	if dids, err := selected(1, "", &didType); err != nil || !slices.Equal(dids, []string{typeZero}) {
+		t.Errorf("DID type 0 selection = %v (%v), want only %s", dids, err, typeZero)
+	}
+	if _, err := selected(2, "", &didType); err == nil {
+		t.Error("selected two DID type 0 quorums from a pool with one")
+	}
+}
+
+func TestRedisAvailabilityWindow(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rs := newTestRedisStore(t, mr)
+	if err := rs.SetAvailabilityWindow(time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	did := registerTestQuorum(t, rs, "1", 100)
+	setRedisPing(t, mr, did, 2*time.Minute)
+
+	if _, err := rs.GetAvailableQuorums(1, "", 0, "", nil); err == nil {
+		t.Error("selected a quorum that last pinged outside the window")
+	}
+	if health := rs.GetHealthStatus(); health.TotalQuorums != 1 || health.AvailableQuorums != 0 {
+		t.Errorf("health = %d total, %d available; want 1 and 0", health.TotalQuorums, health.AvailableQuorums)
+	}
+	quorum, err := rs.GetQuorumByDID(did)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := quorum.LastPing.Add(time.Minute); !quorum.NextHeartbeatDeadline.Equal(want) {
+		t.Errorf("deadline = %v, want last_ping + 1m = %v", quorum.NextHeartbeatDeadline, want)
+	}
+
+	if err := rs.UpdateHeartbeat(did); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rs.GetAvailableQuorums(1, "", 0, "", nil); err != nil {
+		t.Errorf("quorum not selectable after a heartbeat: %v", err)
+	}
+
+	for _, window := range []time.Duration{0, quorumRemovalAge + time.Second} {
+		if err := rs.SetAvailabilityWindow(window); err == nil {
+			t.Errorf("SetAvailabilityWindow(%s) accepted", window)
+		}
+	}
+}
+
+func TestRedisCleanupSparesQuorumsThatHeartbeat(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rs := newTestRedisStore(t, mr)
+	stale := registerTestQuorum(t, rs, "1", 100)
+	revived := registerTestQuorum(t, rs, "2", 100)
+	fresh := registerTestQuorum(t, rs, "3", 100)
+	setRedisPing(t, mr, stale, quorumRemovalAge+time.Minute)
+	setRedisPing(t, mr, revived, quorumRemovalAge+time.Minute)
+
+	// Cleanup found revived stale, then its heartbeat landed before the removal
+	cutoff := strconv.FormatInt(time.Now().Add(-quorumRemovalAge).UnixMilli(), 10)
+	if err := rs.UpdateHeartbeat(revived); err != nil {
+		t.Fatal(err)
+	}
+	if removed, err := rs.remove(revived, cutoff); err != nil || removed {
+		t.Errorf("remove after a heartbeat = %v (%v), want the quorum kept", removed, err)
+	}
+
+	removed, err := rs.CleanupStaleQuorums()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 1 {
+		t.Errorf("cleanup removed %d quorums, want 1", removed)
+	}
+	if _, err := rs.GetQuorumByDID(stale); !errors.Is(err, ErrQuorumNotFound) {
+		t.Errorf("stale quorum: error = %v, want ErrQuorumNotFound", err)
+	}
+	for _, did := range []string{revived, fresh} {
+		if _, err := rs.GetQuorumByDID(did); err != nil {
+			t.Errorf("quorum %s removed by cleanup: %v", did, err)
+		}
+	}
+}
+
+func TestRedisCleanupRacingHeartbeats(t *testing.T) {
+	mr := miniredis.RunT(t)
+	replicas := []*RedisStore{newTestRedisStore(t, mr), newTestRedisStore(t, mr)}
+	var dids []string
+	for i := 1; i <= 20; i++ {
+		did := registerTestQuorum(t, replicas[0], strconv.Itoa(i), 100)
+		setRedisPing(t, mr, did, quorumRemovalAge+time.Minute)
+		dids = append(dids, did)
+	}
+
+	// Each quorum either heartbeats before cleanup reaches it and stays, or is removed
+	// and its heartbeat reports it gone; never a heartbeat on a half-removed quorum
+	heartbeats := make([]error, len(dids))
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		for i, did := range dids {
+			heartbeats[i] = replicas[0].UpdateHeartbeat(did)
+		}
+	}()
+	for _, rs := range replicas {
+		go func(rs *RedisStore) {
+			defer wg.Done()
+			if _, err := rs.CleanupStaleQuorums(); err != nil {
+				t.Error(err)
+			}
+		}(rs)
+	}
+	wg.Wait()
+
+	for i, did := range dids {
+		record, load, ping := redisIndexed(mr, did)
+		switch {
+		case heartbeats[i] == nil && !(record && load && ping):
+			t.Errorf("quorum %s heartbeated but was removed (record %v, load %v, ping %v)", did, record, load, ping)
+		case errors.Is(heartbeats[i], ErrQuorumNotFound) && (record || load || ping):
+			t.Errorf("quorum %s reported gone but left record %v, load %v, ping %v", did, record, load, ping)
+		case heartbeats[i] != nil && !errors.Is(heartbeats[i], ErrQuorumNotFound):
+			t.Errorf("heartbeat for %s: %v", did, heartbeats[i])
+		}
+	}
+}
+
+func TestRedisUnregisterRemovesEveryIndex(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rs := newTestRedisStore(t, mr)
+	gone := registerTestQuorum(t, rs, "1", 100)
+	kept := registerTestQuorum(t, rs, "2", 100)
+	if _, err := rs.GetAvailableQuorums(2, "", 0, "", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rs.UnregisterQuorum(gone, false); err != nil {
+		t.Fatal(err)
+	}
+	if record, load, ping := redisIndexed(mr, gone); record || load || ping {
+		t.Errorf("unregistered quorum left record %v, load %v, ping %v", record, load, ping)
+	}
+	if record, load, ping := redisIndexed(mr, kept); !record || !load || !ping {
+		t.Errorf("other quorum lost record %v, load %v, ping %v", record, load, ping)
+	}
+
+	peers, err := rs.GetPeerDIDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := peers["peer1"]; ok || len(peers) != 1 {
+		t.Errorf("peer DIDs = %v, want only peer2", peers)
+	}
+	if health := rs.GetHealthStatus(); health.TotalQuorums != 1 {
+		t.Errorf("health counts %d quorums, want 1", health.TotalQuorums)
+	}
+	if _, err := rs.GetAvailableQuorums(2, "", 0, "", nil); err == nil {
+		t.Error("selected the unregistered quorum")
+	}
+	if err := rs.UnregisterQuorum(gone, false); !errors.Is(err, ErrQuorumNotFound) {
+		t.Errorf("second unregister: error = %v, want ErrQuorumNotFound", err)
+	}
+}
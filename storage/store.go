@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+// Store is the quorum storage every backend provides: registration, selection,
+// heartbeats and lookups. Handlers that only need these depend on Store, so the pool can
+// live in memory, in Redis shared between replicas, or in a database. DBStore adds
+// history, reservations, webhooks and the other features of the full API on top (see
+// ExtendedStore).
+type Store interface {
+	RegisterQuorum(req *models.QuorumRegistrationRequest) error
+	ConfirmAvailability(did string) error
+	GetAvailableQuorums(count int, lastCharTID string, transactionAmount float64, ftName string, didType *int) ([]models.QuorumData, error)
+	// RequiredBalance is the balance each of count quorums needs for a transaction of
+	// amount, as GetAvailableQuorums checks it
+	RequiredBalance(amount float64, count int) float64
+	UpdateHeartbeat(did string) error
+	// UnregisterQuorum removes a quorum from the pool. Stores that keep history of
+	// unregistered quorums only delete it outright when hard is set.
+	UnregisterQuorum(did string, hard bool) error
+	GetQuorumByDID(did string) (*models.QuorumInfo, error)
+	GetPeerDIDs() (map[string][]string, error)
+	GetHealthStatus() models.HealthStatus
+	// CleanupStaleQuorums drops stale quorums from selection, returning how many
+	CleanupStaleQuorums() (int, error)
+}
+
+// ExtendedStore is what the full API needs besides Store: onboarding and batch
+// registration, signatures and tombstones, balance updates, selection analytics,
+// reservations, history, webhooks, API keys and pool export. DBStore implements it;
+// handlers reach it through a type assertion, so stores without it serve the core
+// endpoints only.
+type ExtendedStore interface {
+	// Registration
+	OnboardQuorum(req *models.QuorumRegistrationRequest) (*models.QuorumInfo, error)
+	RegisterQuorumsBatch(reqs []*models.QuorumRegistrationRequest) ([]RegistrationResult, error)
+	CheckRegistrationPolicy(req *models.QuorumRegistrationRequest) error
+	HasTier(tier string) bool
+	GetPublicKey(did string) (string, error)
+	KeyedDIDs(dids []string) ([]string, error)
+	UnregisterQuorumSigned(did string, req *models.UnregisterSignature, hard bool) error
+
+	// Quorum state
+	UpdateQuorumBalance(did string, newBalance float64) error
+	SetAvailability(did string, available bool) error
+	UpdateHeartbeatDetails(req *models.HeartbeatRequest) error
+	UpdateHeartbeatBatch(dids []string) (int, []string, error)
+	FreezeStaleness(until time.Time) error
+	ResetAssignments(did string) (int64, error)
+
+	// Selection
+	EffectiveRequiredBalance(requiredBalance float64) float64
+	BalanceSafetyMultiplier() float64
+	SetBalanceSafetyMultiplier(multiplier float64) error
+	SelectQuorums(req models.QuorumListRequest) (*SelectionResult, error)
+	SelectReplacement(req *models.QuorumReplaceRequest) (*models.QuorumData, error)
+	CountEligible(req models.QuorumListRequest) (int64, float64, error)
+	EligibleQuorums(req models.QuorumListRequest) ([]models.QuorumData, float64, error)
+	DiagnoseAvailability(req models.QuorumListRequest) (*models.AvailabilityDiagnosis, error)
+	ExplainSelection(req models.QuorumListRequest, limit int) (*models.SelectionExplanation, error)
+	GetNearEligible(req models.QuorumListRequest, tolerance float64) ([]models.NearEligibleQuorum, error)
+	ForecastEligibility(req models.QuorumListRequest, window time.Duration) (*models.EligibilityForecast, error)
+	RecentSelectionFailures(limit int) []models.SelectionFailure
+
+	// Reservations and transactions
+	ReserveQuorums(txnID string, dids []string, amount float64, ttl time.Duration) (time.Time, error)
+	ReleaseQuorums(txnID string) (int64, error)
+	ReserveScheduled(req models.ScheduledReservationRequest) (*models.ScheduledReservation, error)
+	CompleteTransaction(txnID, status string, decrementAssignments bool) (*models.TransactionCompletion, error)
+
+	// Listings and history
+	GetNamespaceHealthStatus(namespace string) models.HealthStatus
+	GetNamespaceQuorums(namespace string) ([]models.QuorumInfo, error)
+	ListQuorums(filter QuorumListFilter) (*QuorumListPage, error)
+	GetQuorumStats(did string) (*QuorumStats, error)
+	GetBalanceHistory(did string, limit int) ([]BalanceHistory, error)
+	GetQuorumTimeline(did string, since time.Time, limit int) ([]models.TimelineEvent, error)
+	CompareQuorums(a, b string) (*models.QuorumComparison, error)
+	GetTransactionHistory(filter TransactionHistoryFilter) (*TransactionHistoryPage, error)
+
+	// Webhooks, API keys and pool export
+	CreateWebhookSubscription(url string, events []string) (*models.WebhookSubscription, error)
+	ListWebhookSubscriptions() ([]models.WebhookSubscription, error)
+	DeleteWebhookSubscription(id uint) error
+	CreateAPIKey(name string, scopes []string) (string, *models.APIKeyInfo, error)
+	LookupAPIKey(key string) ([]string, error)
+	ListAPIKeys() ([]models.APIKeyInfo, error)
+	DeleteAPIKey(id uint) error
+	ExportPool(includeStats, includeHistory bool) (*models.PoolExport, error)
+	ImportPool(export *models.PoolExport, mode string) (*PoolImportResult, error)
+}
+
+// PeerIndexer is implemented by stores keeping a PeerID -> DID index, which the
+// in-memory binary dumps for debugging
+type PeerIndexer interface {
+	PeerIndex() map[string]string
+}
+
+var (
+	_ Store = (*MemoryStore)(nil)
+	_ Store = (*RedisStore)(nil)
+	_ Store = (*DBStore)(nil)
+
+	_ ExtendedStore = (*DBStore)(nil)
+	_ PeerIndexer   = (*MemoryStore)(nil)
+)
+
+// MaxQuorumCount bounds how many quorums one request may select or reserve
+const MaxQuorumCount = 100
+
+// quorumRemovalAge is how long without a ping before the cleanup of stores without
+// history removes a quorum outright
+const quorumRemovalAge = 10 * time.Minute
+
+// ValidateAvailabilityWindow checks that window is positive and no longer than the age at
+// which the cleanup of stores without history removes a quorum, so a quorum is never
+// removed while it is still selectable
+func ValidateAvailabilityWindow(window time.Duration) error {
+	if window <= 0 || window > quorumRemovalAge {
+		return fmt.Errorf("availability window must be positive and at most %s, got %s", quorumRemovalAge, window)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+// KnownTokens are the token types quorums may declare without further configuration.
+// Health reports always include them, so an empty pool shows as 0.
+var KnownTokens = []string{"RBT", "TRI"}
+
+// Policies for quorums that declare no supported tokens (see DBConfig.EmptyTokensMeans)
+const (
+	EmptyTokensRBTOnly = "rbt-only" // Empty lists support RBT only
+	EmptyTokensAll     = "all"      // Empty lists support every token, including TRI
+	EmptyTokensNone    = "none"     // Empty lists support nothing; quorums must declare their tokens
+)
+
+// ValidateEmptyTokensMeans checks that policy is one of the EmptyTokens* values
+func ValidateEmptyTokensMeans(policy string) error {
+	switch policy {
+	case EmptyTokensRBTOnly, EmptyTokensAll, EmptyTokensNone:
+		return nil
+	}
+	return fmt.Errorf("empty tokens policy must be %q, %q or %q, got %q",
+		EmptyTokensRBTOnly, EmptyTokensAll, EmptyTokensNone, policy)
+}
+
+// emptyTokensSupport reports whether a quorum declaring no tokens supports token under
+// policy. An empty token is a request for RBT.
+func emptyTokensSupport(policy, token string) bool {
+	switch policy {
+	case EmptyTokensAll:
+		return true
+	case EmptyTokensNone:
+		return false
+	default:
+		return token == "" || token == "RBT"
+	}
+}
+
+// tokenAvailability builds the per-token health report from the available quorums
+// declaring each token and the number declaring none, which count towards the tokens
+// policy gives them
+func tokenAvailability(declared map[string]int, undeclared int, policy string) map[string]models.TokenAvailability {
+	tokens := make(map[string]models.TokenAvailability, len(KnownTokens)+len(declared))
+	for _, token := range KnownTokens {
+		tokens[token] = models.TokenAvailability{}
+	}
+	for token, count := range declared {
+		tokens[token] = models.TokenAvailability{Available: count}
+	}
+
+	for token, availability := range tokens {
+		if emptyTokensSupport(policy, token) {
+			availability.Available += undeclared
+			tokens[token] = availability
+		}
+	}
+	return tokens
+}
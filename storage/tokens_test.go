@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/gklps/advisory-node/models"
+)
+
+func TestEmptyTokensMeans(t *testing.T) {
+	tests := []struct {
+		policy           string
+		wantRBT, wantTRI bool
+	}{
+		{EmptyTokensRBTOnly, true, false},
+		{EmptyTokensAll, true, true},
+		{EmptyTokensNone, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			// Both pools hold two quorums declaring no tokens
+			ds := newTestStore(t, DBConfig{EmptyTokensMeans: tt.policy})
+			ms := NewMemoryStore()
+			if err := ms.SetEmptyTokensMeans(tt.policy); err != nil {
+				t.Fatal(err)
+			}
+			for _, suffix := range []string{"1", "2"} {
+				registerTestQuorum(t, ds, suffix, 100)
+				if err := ms.RegisterQuorum(&models.QuorumRegistrationRequest{DID: testDID(suffix), PeerID: "peer" + suffix, Balance: 100, DIDType: 4}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for _, token := range []struct {
+				ftName string
+				want   bool
+			}{{"", tt.wantRBT}, {"RBT", tt.wantRBT}, {"TRI", tt.wantTRI}} {
+				req := selectionRequest(2, 2)
+				req.FTName = token.ftName
+				req.TransactionID = fmt.Sprintf("txn-%s-%s", tt.policy, token.ftName)
+				if _, err := ds.SelectQuorums(req); (err == nil) != token.want {
+					t.Errorf("DB selection for %q: err = %v, want success %t", token.ftName, err, token.want)
+				}
+				if _, err := ms.GetAvailableQuorums(2, "", 2, token.ftName, nil); (err == nil) != token.want {
+					t.Errorf("memory selection for %q: err = %v, want success %t", token.ftName, err, token.want)
+				}
+			}
+		})
+	}
+
+	if _, err := NewDBStore(DBConfig{Type: "sqlite", Database: filepath.Join(t.TempDir(), "advisory.db"), EmptyTokensMeans: "some"}); err == nil {
+		t.Error("NewDBStore accepted an unknown empty-tokens policy")
+	}
+	if err := NewMemoryStore().SetEmptyTokensMeans("some"); err == nil {
+		t.Error("SetEmptyTokensMeans accepted an unknown policy")
+	}
+}
@@ -21,7 +21,7 @@ curl -s "$BASE_URL/api/quorum/health" | jq .
 echo -e "\n2. Registering test quorums..."
 for i in {1..10}; do
     DID=$(generate_did)
-    PEER_ID="12D3KooWTestPeer$i"
+    PEER_ID=$(printf "12D3KooWTestPeer%036d" "$i" | tr 0 A)
     
     echo "   Registering quorum $i with DID: $DID"
     
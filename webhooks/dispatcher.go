@@ -0,0 +1,242 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gklps/advisory-node/metrics"
+)
+
+// Event is the JSON body POSTed to webhook subscribers
+type Event struct {
+	Type      string      `json:"type"`
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// DeadLetterSink persists events that could not be delivered
+type DeadLetterSink interface {
+	RecordWebhookDeadLetter(url, eventType string, payload []byte, attempts int, lastError string) error
+}
+
+// Config controls webhook delivery retries
+type Config struct {
+	MaxAttempts    int           // Delivery attempts before an event is dead-lettered
+	InitialBackoff time.Duration // Wait before the first retry; doubles on each further retry
+	MaxBackoff     time.Duration // Upper bound on the wait between retries
+	Timeout        time.Duration // Per-attempt HTTP timeout
+	QueueSize      int           // Deliveries buffered before new events are dead-lettered
+	Workers        int           // Concurrent deliveries
+
+	// Deliver to loopback, link-local and private addresses, which are refused at dial
+	// time otherwise
+	AllowPrivateTargets bool
+}
+
+// DefaultConfig returns the delivery settings used when none are configured
+func DefaultConfig() Config {
+	return Config{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		Timeout:        5 * time.Second,
+		QueueSize:      1000,
+		Workers:        4,
+	}
+}
+
+// delivery is one event bound for one subscriber URL
+type delivery struct {
+	url       string
+	eventType string
+	payload   []byte
+	attempts  int
+}
+
+// Dispatcher delivers webhook events in the background. Enqueue never blocks: failed
+// attempts are retried with exponential backoff on a timer rather than in a worker,
+// and events that exhaust their attempts (or arrive while the queue is full) are
+// handed to the dead-letter sink.
+type Dispatcher struct {
+	config Config
+	client *http.Client
+	sink   DeadLetterSink
+
+	queue chan delivery
+	done  chan struct{}
+	once  sync.Once
+	wg    sync.WaitGroup
+}
+
+// NewDispatcher starts a dispatcher; zero config fields fall back to DefaultConfig
+func NewDispatcher(config Config, sink DeadLetterSink) *Dispatcher {
+	defaults := DefaultConfig()
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaults.MaxAttempts
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = defaults.InitialBackoff
+	}
+	if config.MaxBackoff < config.InitialBackoff {
+		config.MaxBackoff = config.InitialBackoff
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaults.QueueSize
+	}
+	if config.Workers <= 0 {
+		config.Workers = defaults.Workers
+	}
+
+	d := &Dispatcher{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout, Transport: newTransport(config.AllowPrivateTargets)},
+		sink:   sink,
+		queue:  make(chan delivery, config.QueueSize),
+		done:   make(chan struct{}),
+	}
+
+	for i := 0; i < config.Workers; i++ {
+		d.wg.Add(1)
+		go d.worker()
+	}
+
+	return d
+}
+
+// newTransport returns the HTTP transport deliveries use. Unless private targets are
+// allowed, it refuses to connect to private addresses and ignores proxy settings, since
+// a proxy would resolve and connect to the subscriber on the dispatcher's behalf.
+func newTransport(allowPrivate bool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if allowPrivate {
+		return transport
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: refusePrivate}
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+	return transport
+}
+
+// Enqueue schedules event for delivery to url without blocking the caller. It reports
+// whether the event was queued; a full queue dead-letters it immediately.
+func (d *Dispatcher) Enqueue(url string, event Event) bool {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("⚠️  Failed to encode webhook event %s: %v\n", event.Type, err)
+		return false
+	}
+
+	return d.submit(delivery{url: url, eventType: event.Type, payload: payload})
+}
+
+// Close stops the workers. Deliveries still queued or waiting on a retry are dropped.
+func (d *Dispatcher) Close() {
+	d.once.Do(func() { close(d.done) })
+	d.wg.Wait()
+}
+
+// submit queues a delivery, dead-lettering it if the queue is full or closed
+func (d *Dispatcher) submit(del delivery) bool {
+	select {
+	case <-d.done:
+		return false
+	default:
+	}
+
+	select {
+	case d.queue <- del:
+		return true
+	default:
+		d.deadLetter(del, "delivery queue full")
+		return false
+	}
+}
+
+func (d *Dispatcher) worker() {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-d.done:
+			return
+		case del := <-d.queue:
+			d.attempt(del)
+		}
+	}
+}
+
+// attempt makes one delivery attempt and schedules a retry or dead-letters on failure
+func (d *Dispatcher) attempt(del delivery) {
+	del.attempts++
+
+	err := d.post(del)
+	if err == nil {
+		return
+	}
+
+	metrics.WebhookDeliveryFailures.Inc()
+	if del.attempts >= d.config.MaxAttempts {
+		d.deadLetter(del, err.Error())
+		return
+	}
+
+	time.AfterFunc(d.backoff(del.attempts), func() {
+		d.submit(del)
+	})
+}
+
+// backoff returns the wait after the given number of failed attempts
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.config.InitialBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= d.config.MaxBackoff {
+			return d.config.MaxBackoff
+		}
+	}
+	return wait
+}
+
+func (d *Dispatcher) post(del delivery) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, del.url, bytes.NewReader(del.payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("subscriber responded %s", resp.Status)
+	}
+	return nil
+}
+
+func (d *Dispatcher) deadLetter(del delivery, reason string) {
+	metrics.WebhookDeadLetters.Inc()
+	log.Printf("☠️  Webhook %s to %s dead-lettered after %d attempts: %s\n", del.eventType, del.url, del.attempts, reason)
+
+	if d.sink == nil {
+		return
+	}
+	if err := d.sink.RecordWebhookDeadLetter(del.url, del.eventType, del.payload, del.attempts, reason); err != nil {
+		log.Printf("⚠️  Failed to record webhook dead letter: %v\n", err)
+	}
+}
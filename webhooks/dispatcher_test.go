@@ -0,0 +1,209 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// deadLetter is one event handed to recordingSink
+type deadLetter struct {
+	url, eventType string
+	payload        []byte
+	attempts       int
+	lastError      string
+}
+
+// recordingSink passes dead-lettered events to a channel
+type recordingSink chan deadLetter
+
+func (s recordingSink) RecordWebhookDeadLetter(url, eventType string, payload []byte, attempts int, lastError string) error {
+	s <- deadLetter{url, eventType, payload, attempts, lastError}
+	return nil
+}
+
+func TestBackoffDoublesUpToMax(t *testing.T) {
+	d := &Dispatcher{config: Config{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}}
+	for attempts, want := range map[int]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 4 * time.Second,
+		4: 5 * time.Second,
+		9: 5 * time.Second,
+	} {
+		if got := d.backoff(attempts); got != want {
+			t.Errorf("backoff after %d attempts = %s, want %s", attempts, got, want)
+		}
+	}
+}
+
+func TestFailedDeliveriesRetryThenDeadLetter(t *testing.T) {
+	var mu sync.Mutex
+	var hits []time.Time
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits = append(hits, time.Now())
+		mu.Unlock()
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer subscriber.Close()
+
+	sink := make(recordingSink, 1)
+	d := NewDispatcher(Config{MaxAttempts: 3, InitialBackoff: 50 * time.Millisecond, MaxBackoff: 80 * time.Millisecond, AllowPrivateTargets: true}, sink)
+	defer d.Close()
+
+	if !d.Enqueue(subscriber.URL, Event{Type: "quorum.registered", Data: map[string]string{"did": "did1"}}) {
+		t.Fatal("Enqueue refused the event")
+	}
+
+	var dead deadLetter
+	select {
+	case dead = <-sink:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event never dead-lettered")
+	}
+	if dead.url != subscriber.URL || dead.eventType != "quorum.registered" || dead.attempts != 3 {
+		t.Errorf("dead letter = %s %s after %d attempts, want quorum.registered after 3", dead.url, dead.eventType, dead.attempts)
+	}
+	if !strings.Contains(dead.lastError, "500") {
+		t.Errorf("last error = %q, want the subscriber's status", dead.lastError)
+	}
+	var event Event
+	if err := json.Unmarshal(dead.payload, &event); err != nil || event.Type != "quorum.registered" {
+		t.Errorf("dead-lettered payload = %s (%v), want the event", dead.payload, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(hits) != 3 {
+		t.Fatalf("subscriber hit %d times, want 3", len(hits))
+	}
+	// The second retry waits twice as long as the first, capped at the maximum
+	if gap := hits[1].Sub(hits[0]); gap < 50*time.Millisecond {
+		t.Errorf("first retry after %s, want at least 50ms", gap)
+	}
+	if gap := hits[2].Sub(hits[1]); gap < 80*time.Millisecond {
+		t.Errorf("second retry after %s, want at least 80ms", gap)
+	}
+}
+
+func TestRetriedDeliverySucceeds(t *testing.T) {
+	delivered := make(chan struct{})
+	var mu sync.Mutex
+	hits := 0
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if hits++; hits < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		close(delivered)
+	}))
+	defer subscriber.Close()
+
+	sink := make(recordingSink, 1)
+	d := NewDispatcher(Config{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond, AllowPrivateTargets: true}, sink)
+	defer d.Close()
+	d.Enqueue(subscriber.URL, Event{Type: "quorum.stale"})
+
+	select {
+	case <-delivered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("event never delivered")
+	}
+	select {
+	case dead := <-sink:
+		t.Errorf("delivered event was also dead-lettered: %+v", dead)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestFullQueueDeadLetters(t *testing.T) {
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+	}))
+	defer subscriber.Close()
+
+	sink := make(recordingSink, 1)
+	d := NewDispatcher(Config{Workers: 1, QueueSize: 1, AllowPrivateTargets: true}, sink)
+	defer d.Close()
+	defer close(release)
+
+	// The only worker is held up by the first event and the second fills the queue
+	d.Enqueue(subscriber.URL, Event{Type: "first"})
+	<-received
+	if !d.Enqueue(subscriber.URL, Event{Type: "second"}) {
+		t.Fatal("second event refused with room in the queue")
+	}
+	if d.Enqueue(subscriber.URL, Event{Type: "third"}) {
+		t.Fatal("third event queued past the queue size")
+	}
+
+	select {
+	case dead := <-sink:
+		if dead.eventType != "third" || dead.attempts != 0 || dead.lastError != "delivery queue full" {
+			t.Errorf("dead letter = %+v, want third with no attempts", dead)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("overflowing event wasn't dead-lettered")
+	}
+}
+
+func TestPrivateTargetsRefusedAtDial(t *testing.T) {
+	hit := make(chan struct{}, 1)
+	subscriber := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit <- struct{}{}
+	}))
+	defer subscriber.Close()
+
+	// httptest listens on loopback, which is refused without AllowPrivateTargets
+	sink := make(recordingSink, 1)
+	d := NewDispatcher(Config{MaxAttempts: 1}, sink)
+	defer d.Close()
+	d.Enqueue(subscriber.URL, Event{Type: "quorum.stale"})
+
+	select {
+	case dead := <-sink:
+		if !strings.Contains(dead.lastError, ErrPrivateTarget.Error()) {
+			t.Errorf("last error = %q, want %q", dead.lastError, ErrPrivateTarget)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("delivery to loopback was never dead-lettered")
+	}
+	select {
+	case <-hit:
+		t.Error("subscriber on loopback received the event")
+	default:
+	}
+}
+
+func TestCheckTarget(t *testing.T) {
+	for host, private := range map[string]bool{
+		"127.0.0.1":       true,
+		"::1":             true,
+		"169.254.169.254": true,
+		"10.1.2.3":        true,
+		"172.16.0.1":      true,
+		"192.168.1.1":     true,
+		"fd00::1":         true,
+		"0.0.0.0":         true,
+		"localhost":       true,
+		"203.0.113.7":     false,
+		"2001:db8::1":     false,
+	} {
+		err := CheckTarget(context.Background(), host)
+		if got := errors.Is(err, ErrPrivateTarget); got != private {
+			t.Errorf("CheckTarget(%s) = %v, want private %v", host, err, private)
+		}
+	}
+}
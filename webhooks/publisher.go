@@ -0,0 +1,71 @@
+package webhooks
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Quorum lifecycle event types subscribers can filter on
+const (
+	EventQuorumStale        = "quorum_stale"
+	EventQuorumRegistered   = "quorum_registered"
+	EventQuorumUnregistered = "quorum_unregistered"
+)
+
+// EventTypes lists every event type a subscription may name
+var EventTypes = []string{EventQuorumStale, EventQuorumRegistered, EventQuorumUnregistered}
+
+// ValidateEventType checks that eventType is one of EventTypes
+func ValidateEventType(eventType string) error {
+	for _, known := range EventTypes {
+		if eventType == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown webhook event %q, expected one of %v", eventType, EventTypes)
+}
+
+// QuorumEvent is the Data of a quorum lifecycle event
+type QuorumEvent struct {
+	DID    string `json:"did"`
+	PeerID string `json:"peer_id,omitempty"`
+}
+
+// SubscriberSource looks up the URLs subscribed to an event type
+type SubscriberSource interface {
+	WebhookSubscribers(eventType string) ([]string, error)
+}
+
+// Publisher fans events out to their subscribers through a Dispatcher. A nil
+// Publisher discards events, so callers need not check whether webhooks are wired up.
+type Publisher struct {
+	dispatcher *Dispatcher
+	source     SubscriberSource
+}
+
+// NewPublisher creates a publisher delivering through dispatcher to subscribers in source
+func NewPublisher(dispatcher *Dispatcher, source SubscriberSource) *Publisher {
+	return &Publisher{dispatcher: dispatcher, source: source}
+}
+
+// Publish queues one event of eventType per data item for every subscriber. Subscribers
+// are looked up once per call and delivery happens in the background.
+func (p *Publisher) Publish(eventType string, data ...interface{}) {
+	if p == nil || len(data) == 0 {
+		return
+	}
+
+	urls, err := p.source.WebhookSubscribers(eventType)
+	if err != nil {
+		log.Printf("⚠️  Failed to look up %s webhook subscribers: %v\n", eventType, err)
+		return
+	}
+
+	now := time.Now()
+	for _, url := range urls {
+		for _, item := range data {
+			p.dispatcher.Enqueue(url, Event{Type: eventType, Timestamp: now, Data: item})
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateTarget is returned for webhook URLs whose host is a loopback, link-local or
+// private address, unless private targets are allowed
+var ErrPrivateTarget = errors.New("webhook target is a loopback, link-local or private address")
+
+// privateAddress reports whether ip is one a webhook must not reach by default: loopback,
+// link-local (including cloud metadata services), RFC 1918 or unique local, or unspecified
+func privateAddress(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified()
+}
+
+// CheckTarget refuses a webhook host that is, or resolves to, a private address. Hosts
+// that fail to resolve are let through; the dispatcher checks the address it actually
+// dials again at delivery time.
+func CheckTarget(ctx context.Context, host string) error {
+	if ip := net.ParseIP(host); ip != nil {
+		if privateAddress(ip) {
+			return fmt.Errorf("%w: %s", ErrPrivateTarget, host)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if privateAddress(addr.IP) {
+			return fmt.Errorf("%w: %s resolves to %s", ErrPrivateTarget, host, addr.IP)
+		}
+	}
+	return nil
+}
+
+// refusePrivate is a net.Dialer Control function refusing connections to private
+// addresses. It sees the resolved address, so a hostname re-pointed at an internal
+// address after subscribing is refused too.
+func refusePrivate(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || privateAddress(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateTarget, host)
+	}
+	return nil
+}